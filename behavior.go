@@ -0,0 +1,81 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlptranslator
+
+import (
+	"fmt"
+	"slices"
+)
+
+// BehaviorVersion pins the rules MetricNamer, LabelNamer and UnitNamer
+// translate names with. Any change to these rules renames series for someone,
+// so new rules are only applied to namers opting into the version introducing
+// them. The zero value keeps the BehaviorV1 rules.
+type BehaviorVersion string
+
+const (
+	// BehaviorCollectorContrib translates names like the normalizer of the
+	// OpenTelemetry Collector contrib Prometheus translator this library was
+	// extracted from, at the commit metric_namer.go was derived from:
+	//   - Unit suffixes are not appended to names already containing the unit,
+	//     or the "per" unit, as a token, and their underscores and colons
+	//     separate tokens.
+	//   - Namespaces are prepended without being escaped.
+	//   - Multiple consecutive underscores of label names are preserved, and
+	//     "key" is prepended to label names starting with a single underscore.
+	//
+	// Letters outside of ASCII, which the normalizer kept, are escaped like
+	// with the other versions, and UTF-8 names are translated with the
+	// BehaviorV1 rules, as the normalizer did not allow them.
+	BehaviorCollectorContrib BehaviorVersion = "collector-contrib"
+	// BehaviorV1 translates names with the rules of this library before
+	// BehaviorV2. The zero value of BehaviorVersion is equivalent.
+	BehaviorV1 BehaviorVersion = "v1"
+	// BehaviorV2 translates names with the latest rules. Unlike BehaviorV1:
+	//   - Namespaces are escaped when suffixes are added, as they are when
+	//     none is, and prefixed with an underscore when they start with a
+	//     digit and no suffix is added.
+	//   - Unit suffixes made of several tokens, such as "per_second", are not
+	//     appended again to names already containing them, and the trailing
+	//     underscores of units are trimmed, in names and in the units built
+	//     by UnitNamer.
+	//   - Names made of an underscore and the "total" or "ratio" suffix keep
+	//     their suffix, rather than getting it appended again.
+	//   - Metric names which are empty when UTF-8 is allowed are an error.
+	BehaviorV2 BehaviorVersion = "v2"
+)
+
+// behaviorVersions lists the known behavior versions, from the oldest rules
+// to the latest ones.
+var behaviorVersions = []BehaviorVersion{BehaviorCollectorContrib, BehaviorV1, BehaviorV2}
+
+// validate returns an error if v is not a known behavior version.
+func (v BehaviorVersion) validate() error {
+	if v != "" && !slices.Contains(behaviorVersions, v) {
+		return fmt.Errorf("unknown behavior version %q", v)
+	}
+	return nil
+}
+
+// before reports whether v pins rules older than the ones of other.
+func (v BehaviorVersion) before(other BehaviorVersion) bool {
+	return v.index() < other.index()
+}
+
+func (v BehaviorVersion) index() int {
+	if v == "" {
+		return slices.Index(behaviorVersions, BehaviorV1)
+	}
+	return slices.Index(behaviorVersions, v)
+}
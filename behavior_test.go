@@ -0,0 +1,233 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlptranslator
+
+import "testing"
+
+func TestMetricNamer_Build_Behavior(t *testing.T) {
+	tests := []struct {
+		name   string
+		namer  MetricNamer
+		metric Metric
+		want   map[BehaviorVersion]string
+	}{
+		{
+			name:   "namespace starting with digit without suffixes",
+			namer:  MetricNamer{Namespace: "1app"},
+			metric: Metric{Name: "requests", Type: MetricTypeGauge},
+			want: map[BehaviorVersion]string{
+				"":                       "1app_requests",
+				BehaviorV2:               "_1app_requests",
+				BehaviorV1:               "1app_requests",
+				BehaviorCollectorContrib: "1app_requests",
+			},
+		},
+		{
+			name:   "namespace with special characters with suffixes",
+			namer:  MetricNamer{Namespace: "my-app", WithMetricSuffixes: true},
+			metric: Metric{Name: "requests", Type: MetricTypeGauge},
+			want: map[BehaviorVersion]string{
+				"":                       "my-app_requests",
+				BehaviorV2:               "my_app_requests",
+				BehaviorV1:               "my-app_requests",
+				BehaviorCollectorContrib: "my-app_requests",
+			},
+		},
+		{
+			name:   "metric name already contains per unit suffix",
+			namer:  MetricNamer{WithMetricSuffixes: true},
+			metric: Metric{Name: "requests_per_second", Unit: "1/s", Type: MetricTypeGauge},
+			want: map[BehaviorVersion]string{
+				"":                       "requests_per_second_per_second",
+				BehaviorV2:               "requests_per_second",
+				BehaviorV1:               "requests_per_second_per_second",
+				BehaviorCollectorContrib: "requests_per_second",
+			},
+		},
+		{
+			name:   "metric name already contains unit and per unit suffixes",
+			namer:  MetricNamer{WithMetricSuffixes: true},
+			metric: Metric{Name: "throughput_bytes_per_second", Unit: "By/s", Type: MetricTypeGauge},
+			want: map[BehaviorVersion]string{
+				"":                       "throughput_bytes_per_second_per_second",
+				BehaviorV2:               "throughput_bytes_per_second",
+				BehaviorV1:               "throughput_bytes_per_second_per_second",
+				BehaviorCollectorContrib: "throughput_bytes_per_second",
+			},
+		},
+		{
+			name:   "metric name contains per unit without per",
+			namer:  MetricNamer{WithMetricSuffixes: true},
+			metric: Metric{Name: "latency_second", Unit: "ms/s", Type: MetricTypeGauge},
+			want: map[BehaviorVersion]string{
+				"":                       "latency_second_milliseconds_per_second",
+				BehaviorV2:               "latency_second_milliseconds_per_second",
+				BehaviorV1:               "latency_second_milliseconds_per_second",
+				BehaviorCollectorContrib: "latency_second_milliseconds",
+			},
+		},
+		{
+			name:   "unchanged rules",
+			namer:  MetricNamer{WithMetricSuffixes: true},
+			metric: Metric{Name: "http.server.duration", Unit: "s", Type: MetricTypeHistogram},
+			want: map[BehaviorVersion]string{
+				"":                       "http_server_duration_seconds",
+				BehaviorV2:               "http_server_duration_seconds",
+				BehaviorV1:               "http_server_duration_seconds",
+				BehaviorCollectorContrib: "http_server_duration_seconds",
+			},
+		},
+		{
+			name:   "metric name is only the total suffix with UTF8Allowed",
+			namer:  MetricNamer{UTF8Allowed: true, WithMetricSuffixes: true},
+			metric: Metric{Name: "_total", Type: MetricTypeMonotonicCounter},
+			want: map[BehaviorVersion]string{
+				"":                       "_total_total",
+				BehaviorV2:               "_total",
+				BehaviorV1:               "_total_total",
+				BehaviorCollectorContrib: "_total_total",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for behavior, want := range tt.want {
+				namer := tt.namer
+				namer.Behavior = behavior
+				got, err := namer.Build(tt.metric)
+				if err != nil {
+					t.Errorf("MetricNamer{Behavior: %q}.Build(%v), got err string = %q want nil", behavior, tt.metric, err)
+				}
+				if got != want {
+					t.Errorf("MetricNamer{Behavior: %q}.Build(%v) = %q, want %q", behavior, tt.metric, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestMetricNamer_Build_BehaviorErrors(t *testing.T) {
+	namer := MetricNamer{UTF8Allowed: true, Behavior: BehaviorV1}
+	if got, err := namer.Build(Metric{Type: MetricTypeGauge}); got != "" || err != nil {
+		t.Errorf("MetricNamer{Behavior: %q}.Build of empty name = %q, %v, want no error", namer.Behavior, got, err)
+	}
+	namer.Behavior = BehaviorV2
+	if _, err := namer.Build(Metric{Type: MetricTypeGauge}); err == nil {
+		t.Errorf("MetricNamer{Behavior: %q}.Build of empty name, got nil err", namer.Behavior)
+	}
+
+	namer.Behavior = "v0"
+	_, err := namer.Build(Metric{Name: "requests", Type: MetricTypeGauge})
+	if want := `unknown behavior version "v0"`; err == nil || err.Error() != want {
+		t.Errorf("MetricNamer{Behavior: %q}.Build, got err %v, want %q", namer.Behavior, err, want)
+	}
+}
+
+func TestLabelNamer_Build_Behavior(t *testing.T) {
+	tests := []struct {
+		label string
+		want  map[BehaviorVersion]string
+	}{
+		{
+			label: "foo__bar",
+			want: map[BehaviorVersion]string{
+				"":                       "foo_bar",
+				BehaviorV1:               "foo_bar",
+				BehaviorCollectorContrib: "foo__bar",
+			},
+		},
+		{
+			label: "_foo",
+			want: map[BehaviorVersion]string{
+				"":                       "_foo",
+				BehaviorV1:               "_foo",
+				BehaviorCollectorContrib: "key_foo",
+			},
+		},
+		{
+			label: "123invalid",
+			want: map[BehaviorVersion]string{
+				"":                       "key_123invalid",
+				BehaviorV1:               "key_123invalid",
+				BehaviorCollectorContrib: "key_123invalid",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.label, func(t *testing.T) {
+			for behavior, want := range tt.want {
+				namer := LabelNamer{Behavior: behavior}
+				got, err := namer.Build(tt.label)
+				if err != nil {
+					t.Errorf("LabelNamer{Behavior: %q}.Build(%q), got err string = %q want nil", behavior, tt.label, err)
+				}
+				if got != want {
+					t.Errorf("LabelNamer{Behavior: %q}.Build(%q) = %q, want %q", behavior, tt.label, got, want)
+				}
+			}
+		})
+	}
+
+	namer := LabelNamer{Behavior: "v0"}
+	if _, err := namer.Build("foo"); err == nil {
+		t.Errorf("LabelNamer{Behavior: %q}.Build, got nil err", namer.Behavior)
+	}
+}
+
+func TestUnitNamer_Build_Behavior(t *testing.T) {
+	tests := []struct {
+		unit string
+		want map[BehaviorVersion]string
+	}{
+		{
+			unit: "s_/m",
+			want: map[BehaviorVersion]string{
+				"":         "s__per_minute",
+				BehaviorV1: "s__per_minute",
+				BehaviorV2: "s_per_minute",
+			},
+		},
+		{
+			unit: "_By_/s",
+			want: map[BehaviorVersion]string{
+				"":         "By__per_second",
+				BehaviorV1: "By__per_second",
+				BehaviorV2: "By_per_second",
+			},
+		},
+		{
+			unit: "By/s",
+			want: map[BehaviorVersion]string{
+				"":         "bytes_per_second",
+				BehaviorV1: "bytes_per_second",
+				BehaviorV2: "bytes_per_second",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.unit, func(t *testing.T) {
+			for behavior, want := range tt.want {
+				namer := UnitNamer{Behavior: behavior}
+				if got := namer.Build(tt.unit); got != want {
+					t.Errorf("UnitNamer{Behavior: %q}.Build(%q) = %q, want %q", behavior, tt.unit, got, want)
+				}
+			}
+		})
+	}
+
+	namer := UnitNamer{Behavior: "v0"}
+	if got := namer.Build("By/s"); got != "" {
+		t.Errorf("UnitNamer{Behavior: %q}.Build = %q, want no unit", namer.Behavior, got)
+	}
+}
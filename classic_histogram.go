@@ -0,0 +1,156 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlptranslator
+
+import (
+	"fmt"
+	"math"
+	"slices"
+)
+
+// ClassicHistogramLayout selects the buckets of the classic histograms
+// converted from native histograms, for backends that do not support the
+// latter.
+//
+// With Bounds, the classic buckets have exactly the given upper bounds. When
+// a bound falls inside a native bucket, the count of that native bucket is
+// split assuming that its observations are spread exponentially within it,
+// the same assumption histogram_quantile() makes for native histograms. The
+// cumulative count of such a classic bucket is therefore off by at most the
+// count of the native bucket the bound falls into. Those native buckets span
+// a factor of 2^(2^-schema) between their bounds, about 9% at schema 3.
+// Bounds falling inside the zero bucket split it linearly.
+//
+// Without Bounds, the classic buckets are the native buckets downscaled to
+// Schema: the upper bound of every classic bucket is a native bucket
+// boundary, so the conversion is exact. A lower schema gives fewer, wider
+// buckets; each schema step down halves the resolution. Schema is capped to
+// the schema of the converted histogram.
+type ClassicHistogramLayout struct {
+	Bounds []float64
+	Schema int32
+}
+
+// ClassicBucket is a cumulative classic histogram bucket.
+type ClassicBucket struct {
+	UpperBound float64
+	Count      float64
+}
+
+// ClassicBuckets converts the native histogram into cumulative classic
+// buckets following the given layout. The last bucket always has an upper
+// bound of +Inf and holds the total count.
+func (h *NativeHistogram) ClassicBuckets(layout ClassicHistogramLayout) []ClassicBucket {
+	bounds := layout.Bounds
+	if len(bounds) == 0 {
+		bounds = h.schemaBounds(min(layout.Schema, h.Schema))
+	} else {
+		bounds = slices.Clone(bounds)
+		slices.Sort(bounds)
+		bounds = slices.Compact(bounds)
+	}
+
+	buckets := make([]ClassicBucket, 0, len(bounds)+1)
+	for _, b := range bounds {
+		if math.IsInf(b, 1) || math.IsNaN(b) {
+			continue
+		}
+		buckets = append(buckets, ClassicBucket{UpperBound: b, Count: h.countBelow(b)})
+	}
+	return append(buckets, ClassicBucket{UpperBound: math.Inf(1), Count: float64(h.Count)})
+}
+
+// countBelow returns the, possibly interpolated, number of observations
+// lower than or equal to b.
+func (h *NativeHistogram) countBelow(b float64) float64 {
+	var total float64
+
+	// Positive buckets hold observations in (lower, upper].
+	forEachNativeBucket(h.PositiveSpans, h.PositiveBuckets, func(idx int32, count uint64) {
+		lower, upper := nativeBucketUpperBound(idx-1, h.Schema), nativeBucketUpperBound(idx, h.Schema)
+		switch {
+		case b >= upper:
+			total += float64(count)
+		case b > lower:
+			total += float64(count) * math.Log(b/lower) / math.Log(upper/lower)
+		}
+	})
+
+	// Negative buckets hold observations in [-upper, -lower).
+	forEachNativeBucket(h.NegativeSpans, h.NegativeBuckets, func(idx int32, count uint64) {
+		lower, upper := nativeBucketUpperBound(idx-1, h.Schema), nativeBucketUpperBound(idx, h.Schema)
+		switch {
+		case b >= -lower:
+			total += float64(count)
+		case b >= -upper:
+			total += float64(count) * math.Log(upper/-b) / math.Log(upper/lower)
+		}
+	})
+
+	// The zero bucket holds observations in [-threshold, threshold].
+	switch {
+	case b >= h.ZeroThreshold:
+		total += float64(h.ZeroCount)
+	case b >= -h.ZeroThreshold:
+		total += float64(h.ZeroCount) * (b + h.ZeroThreshold) / (2 * h.ZeroThreshold)
+	}
+	return total
+}
+
+// schemaBounds returns the boundaries of the populated buckets of the
+// histogram, once downscaled to the given schema.
+func (h *NativeHistogram) schemaBounds(schema int32) []float64 {
+	scaleDown := h.Schema - schema
+	// A native bucket i is part of the bucket ((i-1) >> scaleDown) + 1 once
+	// downscaled, as indexes are shifted by one compared to OTLP.
+	downscaled := func(idx int32) int32 {
+		return ((idx - 1) >> scaleDown) + 1
+	}
+
+	var bounds []float64
+	forEachNativeBucket(h.NegativeSpans, h.NegativeBuckets, func(idx int32, _ uint64) {
+		bounds = append(bounds, -nativeBucketUpperBound(downscaled(idx)-1, schema))
+	})
+	if h.ZeroCount > 0 || len(bounds) > 0 {
+		bounds = append(bounds, h.ZeroThreshold)
+	}
+	forEachNativeBucket(h.PositiveSpans, h.PositiveBuckets, func(idx int32, _ uint64) {
+		bounds = append(bounds, nativeBucketUpperBound(downscaled(idx), schema))
+	})
+	slices.Sort(bounds)
+	return slices.Compact(bounds)
+}
+
+// BuildClassicHistogram builds classic histogram samples, with `_bucket`,
+// `_sum` and `_count` series, for an exponential histogram data point. The
+// data point is first converted into a native histogram, whose buckets are
+// then mapped to classic buckets following the given layout. See BuildNumber
+// for the meaning of extra.
+func (b *SampleBuilder) BuildClassicHistogram(metric Metric, extra Labels, p ExponentialHistogramDataPoint, layout ClassicHistogramLayout) ([]Sample, error) {
+	name, ls, err := b.names(metric, extra, p.Attributes)
+	if err != nil {
+		return nil, err
+	}
+	h, err := ExponentialToNativeHistogram(p)
+	if err != nil {
+		return nil, fmt.Errorf("metric %q: %w", metric.Name, err)
+	}
+	exemplars, err := b.pointExemplars(p.Flags, p.Exemplars)
+	if err != nil {
+		return nil, err
+	}
+	startTimeUnixNano := histogramStartTime(metric, p.StartTimeUnixNano)
+	samples := b.appendHistogramSamples(nil, name, ls, p.Flags, startTimeUnixNano, p.TimeUnixNano, h.ClassicBuckets(layout), exemplars, p.Sum, p.Count)
+	return b.appendCreated(samples, metric, ls, p.Flags, startTimeUnixNano, nanosToMillis(p.TimeUnixNano))
+}
@@ -0,0 +1,104 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/prometheus/otlptranslator"
+	"github.com/prometheus/otlptranslator/compare"
+)
+
+func runCompare(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := newFlagSet("compare", "[file]", stderr)
+	namespace := fs.String("namespace", "", "namespace prepended to metric names")
+	format := fs.String("format", "", "input format: csv, tsv or jsonl (default: from the file extension, csv for the standard input)")
+	output := fs.String("output", "markdown", "output format: csv, markdown or json")
+	var strategyNames, attributes listFlag
+	fs.Var(&strategyNames, "strategies", "comma-separated translation `strategies` to compare (default: all)")
+	fs.Var(&attributes, "attributes", "comma-separated attribute `names` to compare")
+	if status, ok := parseFlags(fs, args); !ok {
+		return status
+	}
+	if fs.NArg() > 1 {
+		fs.Usage()
+		return 2
+	}
+
+	var opts compare.Options
+	opts.Namespace = *namespace
+	for _, name := range strategyNames {
+		var s strategyFlag
+		if err := s.Set(name); err != nil {
+			fmt.Fprintf(stderr, "otlptranslate: %v\n", err)
+			return 2
+		}
+		opts.Strategies = append(opts.Strategies, otlptranslator.TranslationStrategyOption(s))
+	}
+	var write func(*compare.Report, io.Writer) error
+	switch strings.ToLower(*output) {
+	case "csv":
+		write = (*compare.Report).WriteCSV
+	case "markdown", "md":
+		write = (*compare.Report).WriteMarkdown
+	case "json":
+		write = (*compare.Report).WriteJSON
+	default:
+		fmt.Fprintf(stderr, "otlptranslate: unknown output format %q, want csv, markdown or json\n", *output)
+		return 2
+	}
+
+	in, name := stdin, ""
+	if fs.NArg() == 1 {
+		name = fs.Arg(0)
+		f, err := os.Open(name)
+		if err != nil {
+			return exitStatus(stderr, err)
+		}
+		defer f.Close()
+		in = f
+	}
+	rowFormat, err := inputFormat(*format, name)
+	if err != nil {
+		fmt.Fprintf(stderr, "otlptranslate: %v\n", err)
+		return 2
+	}
+	var metrics []otlptranslator.Metric
+	err = forEachRow(in, rowFormat, func(line int, r row) error {
+		metric, err := r.metric()
+		if err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+		metrics = append(metrics, metric)
+		return nil
+	})
+	if err != nil {
+		return exitStatus(stderr, err)
+	}
+
+	report, err := compare.NewReport(metrics, attributes, opts)
+	if err != nil {
+		return exitStatus(stderr, err)
+	}
+	if err := write(report, stdout); err != nil {
+		return exitStatus(stderr, err)
+	}
+	if report.HasErrors() {
+		return exitStatus(stderr, errTranslation)
+	}
+	return 0
+}
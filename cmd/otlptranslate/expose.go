@@ -0,0 +1,143 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/prometheus/otlptranslator"
+	"github.com/prometheus/otlptranslator/exposition"
+	"github.com/prometheus/otlptranslator/otlp"
+)
+
+// Output formats of the expose command.
+const (
+	formatText        = "text"
+	formatOpenMetrics = "openmetrics"
+)
+
+// writer is implemented by the writers of the exposition package.
+type writer interface {
+	otlp.Appender
+	WriteTo(w io.Writer) (int64, error)
+}
+
+func runExpose(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := newFlagSet("expose", "[file]", stderr)
+	strategy := addStrategyFlag(fs)
+	namespace := fs.String("namespace", "", "namespace prepended to metric names")
+	format := fs.String("format", formatText, "output `format`: text or openmetrics")
+	var (
+		promote, ignore listFlag
+		c               otlp.Converter
+	)
+	fs.Var(&promote, "promote-resource-attributes", "comma-separated resource `attributes` added as labels to every series")
+	fs.BoolVar(&c.PromoteAllResourceAttributes, "promote-all-resource-attributes", false, "add all resource attributes as labels to every series, but the ignored ones")
+	fs.Var(&ignore, "ignore-resource-attributes", "comma-separated resource `attributes` not promoted with -promote-all-resource-attributes")
+	fs.BoolVar(&c.KeepIdentifyingResourceAttributes, "keep-identifying-resource-attributes", false, "keep service.name, service.namespace and service.instance.id as target_info labels")
+	fs.BoolVar(&c.PromoteScopeMetadata, "promote-scope-metadata", false, "add otel_scope_name and otel_scope_version labels to every series")
+	fs.BoolVar(&c.DisableTargetInfo, "disable-target-info", false, "do not generate target_info")
+	deltaToCumulative := fs.Bool("delta-to-cumulative", false, "accumulate delta sums and histograms across the requests of the file into cumulative ones")
+	schema := fs.Int("classic-histogram-schema", 0, "`schema` exponential histograms are downscaled to before being written as classic histograms")
+	if status, ok := parseFlags(fs, args); !ok {
+		return status
+	}
+	if fs.NArg() > 1 {
+		fs.Usage()
+		return 2
+	}
+	if *schema < -4 || *schema > 8 {
+		fmt.Fprintf(stderr, "otlptranslate: classic histogram schema %d is not between -4 and 8\n", *schema)
+		return 2
+	}
+
+	namer := otlptranslator.NewMetricNamer(*namespace, otlptranslator.TranslationStrategyOption(*strategy))
+	labelNamer := otlptranslator.LabelNamer{UTF8Allowed: namer.UTF8Allowed}
+	var w writer
+	switch *format {
+	case formatText:
+		w = exposition.NewTextWriter(namer)
+	case formatOpenMetrics:
+		w = exposition.NewOpenMetricsWriter(namer)
+		c.SampleBuilder.CreatedSeries = true
+	default:
+		fmt.Fprintf(stderr, "otlptranslate: unknown format %q, want text or openmetrics\n", *format)
+		return 2
+	}
+	c.SampleBuilder.MetricNamer = namer
+	c.SampleBuilder.LabelNamer = labelNamer
+	c.PromoteResourceAttributes = promote
+	c.IgnoreResourceAttributes = ignore
+	c.ClassicHistogramLayout = otlptranslator.ClassicHistogramLayout{Schema: int32(*schema)}
+	if *deltaToCumulative {
+		c.Accumulator = otlptranslator.NewDeltaAccumulator(namer, labelNamer, otlptranslator.DeltaAccumulatorOptions{})
+	}
+
+	in := stdin
+	if fs.NArg() == 1 {
+		f, err := os.Open(fs.Arg(0))
+		if err != nil {
+			return exitStatus(stderr, err)
+		}
+		defer f.Close()
+		in = f
+	}
+	var failed bool
+	dec := otlp.NewJSONDecoder(in)
+	for {
+		req, err := dec.Decode()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return exitStatus(stderr, err)
+		}
+		if err := c.Convert(req, w); err != nil {
+			for _, line := range strings.Split(err.Error(), "\n") {
+				fmt.Fprintf(stderr, "otlptranslate: %s\n", line)
+			}
+			failed = true
+		}
+	}
+	if _, err := w.WriteTo(stdout); err != nil {
+		return exitStatus(stderr, err)
+	}
+	if failed {
+		return exitStatus(stderr, errTranslation)
+	}
+	return 0
+}
+
+// listFlag is a flag.Value holding a comma-separated list.
+type listFlag []string
+
+func (l *listFlag) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *listFlag) Set(v string) error {
+	for _, e := range strings.Split(v, ",") {
+		if e = strings.TrimSpace(e); e != "" {
+			*l = append(*l, e)
+		}
+	}
+	return nil
+}
+
+var _ flag.Value = (*listFlag)(nil)
@@ -0,0 +1,121 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// exposeInput is an OTLP/JSON request holding a delta counter, from a
+// resource with identifying and non-identifying attributes, and a metric
+// that cannot be translated.
+const exposeInput = `{"resourceMetrics":[{"resource":{"attributes":[` +
+	`{"key":"service.name","value":{"stringValue":"api"}},` +
+	`{"key":"service.instance.id","value":{"stringValue":"pod-1"}},` +
+	`{"key":"k8s.namespace.name","value":{"stringValue":"prod"}}]},` +
+	`"scopeMetrics":[{"scope":{"name":"http","version":"1.0"},"metrics":[` +
+	`{"name":"http.requests","description":"Requests.","sum":{"aggregationTemporality":1,"isMonotonic":true,"dataPoints":[` +
+	`{"startTimeUnixNano":"1000000000","timeUnixNano":"2000000000","asInt":"3"}]}}]}]}]}`
+
+func TestRunExpose(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		stdin      string
+		wantStatus int
+		wantStdout string
+		wantStderr string
+	}{
+		{
+			name:  "text with promotion and delta to cumulative",
+			args:  []string{"expose", "-promote-resource-attributes", "k8s.namespace.name", "-delta-to-cumulative"},
+			stdin: exposeInput + "\n" + strings.ReplaceAll(exposeInput, `"startTimeUnixNano":"1000000000","timeUnixNano":"2000000000"`, `"startTimeUnixNano":"2000000000","timeUnixNano":"3000000000"`),
+			wantStdout: `# HELP http_requests_total Requests.
+# TYPE http_requests_total counter
+http_requests_total{instance="pod-1",job="api",k8s_namespace_name="prod"} 3 2000
+http_requests_total{instance="pod-1",job="api",k8s_namespace_name="prod"} 6 3000
+# HELP target_info Target metadata
+# TYPE target_info gauge
+target_info{instance="pod-1",job="api",k8s_namespace_name="prod"} 1 2000
+target_info{instance="pod-1",job="api",k8s_namespace_name="prod"} 1 3000
+`,
+		},
+		{
+			name:  "openmetrics",
+			args:  []string{"expose", "-format", "openmetrics", "-strategy", "NoUTF8EscapingWithSuffixes", "-promote-scope-metadata", "-keep-identifying-resource-attributes"},
+			stdin: exposeInput,
+			wantStdout: `# TYPE "http.requests_total" unknown
+# HELP "http.requests_total" Requests.
+{"http.requests_total",instance="pod-1",job="api",otel_scope_name="http",otel_scope_version="1.0"} 3 2
+# TYPE target_info gauge
+# HELP target_info Target metadata
+target_info{instance="pod-1",job="api","k8s.namespace.name"="prod","service.instance.id"="pod-1","service.name"="api"} 1 2
+# EOF
+`,
+		},
+		{
+			name:  "promote all without target_info",
+			args:  []string{"expose", "-promote-all-resource-attributes", "-ignore-resource-attributes", "service.name,service.instance.id", "-disable-target-info", "-namespace", "shop"},
+			stdin: exposeInput,
+			wantStdout: `# HELP shop_http_requests_total Requests.
+# TYPE shop_http_requests_total untyped
+shop_http_requests_total{instance="pod-1",job="api",k8s_namespace_name="prod"} 3 2000
+`,
+		},
+		{
+			name:       "translation error",
+			args:       []string{"expose"},
+			stdin:      `{"resourceMetrics":[{"scopeMetrics":[{"metrics":[{"name":"","gauge":{"dataPoints":[{"asDouble":1}]}},{"name":"up","gauge":{"dataPoints":[{"asDouble":1}]}}]}]}]}`,
+			wantStatus: 1,
+			wantStdout: "# TYPE up gauge\nup 1\n",
+			wantStderr: `otlptranslate: metric "": normalization for metric "" resulted in empty name`,
+		},
+		{
+			name:       "invalid JSON",
+			args:       []string{"expose"},
+			stdin:      `{"resourceMetrics":`,
+			wantStatus: 1,
+			wantStderr: "otlptranslate: otlp: decoding JSON request: unexpected EOF\n",
+		},
+		{
+			name:       "unknown format",
+			args:       []string{"expose", "-format", "json"},
+			wantStatus: 2,
+			wantStderr: "otlptranslate: unknown format \"json\", want text or openmetrics\n",
+		},
+		{
+			name:       "invalid schema",
+			args:       []string{"expose", "-classic-histogram-schema", "9"},
+			wantStatus: 2,
+			wantStderr: "otlptranslate: classic histogram schema 9 is not between -4 and 8\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			status := run(tt.args, strings.NewReader(tt.stdin), &stdout, &stderr)
+			if status != tt.wantStatus {
+				t.Errorf("exit status = %d, want %d (stderr: %s)", status, tt.wantStatus, stderr.String())
+			}
+			if !strings.HasPrefix(stdout.String(), tt.wantStdout) || tt.wantStatus == 0 && stdout.String() != tt.wantStdout {
+				t.Errorf("stdout:\n%s\nwant:\n%s", stdout.String(), tt.wantStdout)
+			}
+			if !strings.Contains(stderr.String(), tt.wantStderr) {
+				t.Errorf("stderr = %q, want it to contain %q", stderr.String(), tt.wantStderr)
+			}
+		})
+	}
+}
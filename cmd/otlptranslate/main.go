@@ -0,0 +1,197 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command otlptranslate shows how OpenTelemetry metric and attribute names
+// are translated to Prometheus, without writing Go.
+//
+// Usage:
+//
+//	otlptranslate names [-strategy strategy] [-namespace namespace] [-format csv|tsv|jsonl] [-rules file] [file]
+//	otlptranslate labels [-strategy strategy] [key...]
+//	otlptranslate compare [-strategies strategies] [-namespace namespace] [-attributes names] [-output csv|markdown|json] [file]
+//	otlptranslate expose [-strategy strategy] [-namespace namespace] [-format text|openmetrics] [flags] [file]
+//	otlptranslate migrate-rules [-from strategy] [-to strategy] [-metrics file] [-attributes names] [-w] [file...]
+//	otlptranslate migrate-dashboards [-from strategy] [-to strategy] [-metrics file] [-attributes names] [-w] [file...]
+//
+// The names command reads `name,unit,type` rows from file, or from the
+// standard input, and prints the translated name of every metric, one per
+// line. Rows are CSV, TSV or JSON lines objects with name, unit and type
+// fields; an optional `name,unit,type` header is skipped. Types are gauge,
+// counter, updowncounter, histogram, exponential_histogram, summary or
+// unknown. With -rules, the renaming rules of the given YAML file are applied
+// around translation, and dropped metrics are not printed. The labels command
+// translates the given attribute keys, or the ones read from the standard
+// input, one per line.
+//
+// The compare command reads the same rows as the names command, and reports
+// the names of these metrics, and of the attributes given with -attributes,
+// under every translation strategy: names that differ between strategies are
+// marked, and names that collide within a strategy are listed, as they would
+// merge the series of different metrics or the values of different
+// attributes.
+//
+// The expose command reads OTLP/JSON metrics requests, such as the ones
+// written by the file exporter of the OpenTelemetry Collector, and writes the
+// exposition Prometheus would store for them: resource attributes are
+// promoted to labels as configured, job and instance labels are built from
+// the service.* resource attributes, and a target_info metric is generated
+// for every resource. Run `otlptranslate expose -h` for all the options.
+//
+// The migrate-rules command migrates Prometheus rule files, or the standard
+// input, from a translation strategy to another, and prints the diff of the
+// migration, or writes the migrated files with -w. Names that cannot be mapped
+// with certainty are reported on the standard error: listing the metrics and
+// attributes of the rules with -metrics and -attributes maps them exactly.
+// The migrate-dashboards command does the same for the queries, variables
+// and legends of Grafana dashboard JSON files.
+//
+// Commands exit with status 1 if an input cannot be translated, after
+// processing all the other ones, and with status 2 on usage errors.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/prometheus/otlptranslator"
+)
+
+// command is an otlptranslate subcommand.
+type command struct {
+	usage string
+	run   func(args []string, stdin io.Reader, stdout, stderr io.Writer) int
+}
+
+var commands = map[string]command{
+	"names":              {usage: "translate metric names read from `name,unit,type` rows", run: runNames},
+	"labels":             {usage: "translate attribute keys to label names", run: runLabels},
+	"compare":            {usage: "compare the names of metrics and attributes under every translation strategy", run: runCompare},
+	"expose":             {usage: "convert OTLP/JSON metrics to the Prometheus text format or OpenMetrics", run: runExpose},
+	"migrate-rules":      {usage: "migrate Prometheus rule files from a translation strategy to another", run: runMigrateRules},
+	"migrate-dashboards": {usage: "migrate Grafana dashboards from a translation strategy to another", run: runMigrateDashboards},
+}
+
+// errTranslation is returned by commands that reported translation errors.
+var errTranslation = errors.New("translation failed")
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run runs the command given by args, and returns the exit status.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "-help" || args[0] == "help" {
+		printUsage(stderr)
+		return 2
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "otlptranslate: unknown command %q\n", args[0])
+		printUsage(stderr)
+		return 2
+	}
+	return cmd.run(args[1:], stdin, stdout, stderr)
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: otlptranslate <command> [flags] [args]")
+	fmt.Fprintln(w, "\nCommands:")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-18s %s\n", name, commands[name].usage)
+	}
+	fmt.Fprintln(w, "\nRun 'otlptranslate <command> -h' for the flags of a command.")
+}
+
+// newFlagSet creates the flag set of a command, writing errors to stderr.
+func newFlagSet(name, args string, stderr io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: otlptranslate %s [flags] %s\n\nFlags:\n", name, args)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// strategies are the translation strategies accepted by the -strategy flag.
+var strategies = []otlptranslator.TranslationStrategyOption{
+	otlptranslator.UnderscoreEscapingWithSuffixes,
+	otlptranslator.UnderscoreEscapingWithoutSuffixes,
+	otlptranslator.NoUTF8EscapingWithSuffixes,
+	otlptranslator.NoTranslation,
+}
+
+// strategyFlag is a flag.Value holding a TranslationStrategyOption.
+type strategyFlag otlptranslator.TranslationStrategyOption
+
+func (s *strategyFlag) String() string {
+	return string(*s)
+}
+
+func (s *strategyFlag) Set(v string) error {
+	for _, strategy := range strategies {
+		if strings.EqualFold(v, string(strategy)) {
+			*s = strategyFlag(strategy)
+			return nil
+		}
+	}
+	names := make([]string, len(strategies))
+	for i, strategy := range strategies {
+		names[i] = string(strategy)
+	}
+	return fmt.Errorf("unknown strategy %q, want one of %s", v, strings.Join(names, ", "))
+}
+
+// addStrategyFlag adds the -strategy flag to fs.
+func addStrategyFlag(fs *flag.FlagSet) *strategyFlag {
+	s := strategyFlag(otlptranslator.UnderscoreEscapingWithSuffixes)
+	fs.Var(&s, "strategy", "translation `strategy`: UnderscoreEscapingWithSuffixes, UnderscoreEscapingWithoutSuffixes, NoUTF8EscapingWithSuffixes or NoTranslation")
+	return &s
+}
+
+// parseFlags parses the flags of a command, and returns the exit status to
+// use if the command must stop.
+func parseFlags(fs *flag.FlagSet, args []string) (int, bool) {
+	err := fs.Parse(args)
+	switch {
+	case errors.Is(err, flag.ErrHelp):
+		return 0, false
+	case err != nil:
+		return 2, false
+	default:
+		return 0, true
+	}
+}
+
+// exitStatus returns the exit status of a command that returned err.
+func exitStatus(stderr io.Writer, err error) int {
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, errTranslation):
+		return 1
+	default:
+		fmt.Fprintf(stderr, "otlptranslate: %v\n", err)
+		return 1
+	}
+}
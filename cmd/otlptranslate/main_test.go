@@ -0,0 +1,216 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	tsv := write("metrics.tsv", "http.server.duration\ts\thistogram\nqueue.size\t{item}\tupdowncounter\n")
+	rules := write("rules.yml", "rules:\n  - match:\n      name: vendor\\.(.*)\n    rename: acme.$1\n  - match:\n      type: summary\n    drop: true\n")
+	badRules := write("bad.yml", "rules:\n  - drop: maybe\n")
+	jsonl := write("metrics.jsonl", `{"name":"http.server.duration","unit":"s","type":"histogram"}`+"\n\n"+`{"name":"cpu.utilization","unit":"1","type":"gauge"}`+"\n")
+
+	tests := []struct {
+		name       string
+		args       []string
+		stdin      string
+		wantStatus int
+		wantStdout string
+		wantStderr string
+	}{
+		{
+			name:       "csv from stdin with header",
+			args:       []string{"names"},
+			stdin:      "name,unit,type\nhttp.server.duration,s,histogram\nrequests,{request},counter\nsystem.memory.usage,By\n",
+			wantStdout: "http_server_duration_seconds\nrequests_total\nsystem_memory_usage_bytes\n",
+		},
+		{
+			name:       "namespace and strategy",
+			args:       []string{"names", "-namespace", "app", "-strategy", "UnderscoreEscapingWithoutSuffixes"},
+			stdin:      "http.server.duration,s,histogram\n",
+			wantStdout: "app_http_server_duration\n",
+		},
+		{
+			name:       "tsv file",
+			args:       []string{"names", "-strategy", "NoUTF8EscapingWithSuffixes", tsv},
+			wantStdout: "http.server.duration_seconds\nqueue.size\n",
+		},
+		{
+			name:       "jsonl file",
+			args:       []string{"names", jsonl},
+			wantStdout: "http_server_duration_seconds\ncpu_utilization_ratio\n",
+		},
+		{
+			name:       "explicit format",
+			args:       []string{"names", "-format", "tsv"},
+			stdin:      "requests\t\tcounter\n",
+			wantStdout: "requests_total\n",
+		},
+		{
+			name:       "renaming rules",
+			args:       []string{"names", "-rules", rules},
+			stdin:      "vendor.requests,,counter\nrpc.duration,ms,summary\nup,,gauge\n",
+			wantStdout: "acme_requests_total\nup\n",
+		},
+		{
+			name:       "invalid renaming rules",
+			args:       []string{"names", "-rules", badRules},
+			wantStatus: 1,
+			wantStderr: "bad.yml: line 2: drop must be true or false\n",
+		},
+		{
+			name:       "translation errors",
+			args:       []string{"names"},
+			stdin:      "up,,gauge\nbad,,nope\n,,gauge\nrequests,,counter\n",
+			wantStatus: 1,
+			wantStdout: "up\nrequests_total\n",
+			wantStderr: "otlptranslate: line 2: unknown metric type \"nope\"\notlptranslate: line 3: ",
+		},
+		{
+			name:       "too many fields",
+			args:       []string{"names"},
+			stdin:      "a,b,c,d\n",
+			wantStatus: 1,
+			wantStderr: "otlptranslate: line 1: 4 fields, want at most 3\n",
+		},
+		{
+			name:       "invalid json row",
+			args:       []string{"names", "-format", "jsonl"},
+			stdin:      "{\n",
+			wantStatus: 1,
+			wantStderr: "otlptranslate: line 1: unexpected end of JSON input\n",
+		},
+		{
+			name:       "unknown strategy",
+			args:       []string{"names", "-strategy", "Underscores"},
+			wantStatus: 2,
+			wantStderr: `invalid value "Underscores" for flag -strategy: unknown strategy "Underscores"`,
+		},
+		{
+			name:       "unknown format",
+			args:       []string{"names", "-format", "xml"},
+			wantStatus: 2,
+			wantStderr: "otlptranslate: unknown format \"xml\", want csv, tsv or jsonl\n",
+		},
+		{
+			name:       "missing file",
+			args:       []string{"names", filepath.Join(dir, "missing.csv")},
+			wantStatus: 1,
+			wantStderr: "no such file or directory",
+		},
+		{
+			name:       "labels from arguments",
+			args:       []string{"labels", "http.method", "123abc"},
+			wantStdout: "http_method\nkey_123abc\n",
+		},
+		{
+			name:       "labels from stdin",
+			args:       []string{"labels", "-strategy", "NoTranslation"},
+			stdin:      "http.method\n\nnet.peer.name\n",
+			wantStdout: "http.method\nnet.peer.name\n",
+		},
+		{
+			name:       "empty label",
+			args:       []string{"labels", "", "ok"},
+			wantStatus: 1,
+			wantStdout: "ok\n",
+			wantStderr: "otlptranslate: label \"\": label name is empty\n",
+		},
+		{
+			name:  "compare",
+			args:  []string{"compare", "-strategies", "UnderscoreEscapingWithSuffixes,notranslation", "-attributes", "http.route", "-output", "csv"},
+			stdin: "requests,{request},counter\n",
+			wantStdout: "kind,name,unit,type,UnderscoreEscapingWithSuffixes,NoTranslation,differs,collides\n" +
+				"metric,requests,{request},counter,requests_total,requests,true,\n" +
+				"label,http.route,,,http_route,http.route,true,\n",
+		},
+		{
+			name:       "compare translation errors",
+			args:       []string{"compare", "-strategies", "NoTranslation", "-attributes", "__", "-output", "json"},
+			wantStatus: 1,
+			wantStdout: `{
+  "strategies": [
+    "NoTranslation"
+  ],
+  "metrics": [],
+  "labels": [
+    {
+      "name": "__",
+      "translations": [
+        {
+          "strategy": "NoTranslation",
+          "error": "label name \"__\" contains only underscores"
+        }
+      ],
+      "differs": false
+    }
+  ],
+  "collisions": []
+}
+`,
+		},
+		{
+			name:       "compare unknown output",
+			args:       []string{"compare", "-output", "html"},
+			wantStatus: 2,
+			wantStderr: "otlptranslate: unknown output format \"html\", want csv, markdown or json\n",
+		},
+		{
+			name:       "compare unknown strategy",
+			args:       []string{"compare", "-strategies", "Underscores"},
+			wantStatus: 2,
+			wantStderr: "otlptranslate: unknown strategy \"Underscores\"",
+		},
+		{
+			name:       "no command",
+			wantStatus: 2,
+			wantStderr: "Usage: otlptranslate <command>",
+		},
+		{
+			name:       "unknown command",
+			args:       []string{"rename"},
+			wantStatus: 2,
+			wantStderr: "otlptranslate: unknown command \"rename\"\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			status := run(tt.args, strings.NewReader(tt.stdin), &stdout, &stderr)
+			if status != tt.wantStatus {
+				t.Errorf("exit status = %d, want %d (stderr: %s)", status, tt.wantStatus, stderr.String())
+			}
+			if stdout.String() != tt.wantStdout {
+				t.Errorf("stdout = %q, want %q", stdout.String(), tt.wantStdout)
+			}
+			if !strings.Contains(stderr.String(), tt.wantStderr) {
+				t.Errorf("stderr = %q, want it to contain %q", stderr.String(), tt.wantStderr)
+			}
+		})
+	}
+}
@@ -0,0 +1,192 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/prometheus/otlptranslator"
+	"github.com/prometheus/otlptranslator/dashboards"
+	"github.com/prometheus/otlptranslator/rules"
+)
+
+// migrateFlags are the flags of the migrate-rules and migrate-dashboards
+// commands.
+type migrateFlags struct {
+	from, to    strategyFlag
+	namespace   string
+	metricsFile string
+	attributes  listFlag
+	write       bool
+}
+
+// addMigrateFlags adds the flags of the migrate commands to fs.
+func addMigrateFlags(fs *flag.FlagSet, what string) *migrateFlags {
+	f := &migrateFlags{
+		from: strategyFlag(otlptranslator.UnderscoreEscapingWithSuffixes),
+		to:   strategyFlag(otlptranslator.NoUTF8EscapingWithSuffixes),
+	}
+	fs.Var(&f.from, "from", fmt.Sprintf("translation `strategy` the %s were written for", what))
+	fs.Var(&f.to, "to", fmt.Sprintf("translation `strategy` to migrate the %s to", what))
+	fs.StringVar(&f.namespace, "namespace", "", "namespace prepended to metric names")
+	fs.StringVar(&f.metricsFile, "metrics", "", "`file` of known metrics, as `name,unit,type` rows in CSV, TSV or JSON lines")
+	fs.Var(&f.attributes, "attributes", "comma-separated known attribute `names`")
+	fs.BoolVar(&f.write, "w", false, fmt.Sprintf("write the migrated %s to the files instead of printing a diff", what))
+	return f
+}
+
+// migrateFunc migrates the content of a file, and returns the migrated
+// content, its diff and the ambiguities to report.
+type migrateFunc func(name string, content []byte) ([]byte, string, []fmt.Stringer, error)
+
+func runMigrateRules(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := newFlagSet("migrate-rules", "[file...]", stderr)
+	f := addMigrateFlags(fs, "rules")
+	if status, ok := parseFlags(fs, args); !ok {
+		return status
+	}
+	metrics, err := f.metrics()
+	if err != nil {
+		return exitStatus(stderr, err)
+	}
+	m, err := rules.NewMigrator(rules.MigratorOptions{
+		From:       otlptranslator.TranslationStrategyOption(f.from),
+		To:         otlptranslator.TranslationStrategyOption(f.to),
+		Namespace:  f.namespace,
+		Metrics:    metrics,
+		Attributes: f.attributes,
+	})
+	if err != nil {
+		return exitStatus(stderr, err)
+	}
+	return runMigrate(fs, f, func(name string, content []byte) ([]byte, string, []fmt.Stringer, error) {
+		res, err := m.Migrate(name, content)
+		ambiguities := make([]fmt.Stringer, len(res.Ambiguities))
+		for i, a := range res.Ambiguities {
+			ambiguities[i] = a
+		}
+		return res.Content, res.Diff, ambiguities, err
+	}, stdin, stdout, stderr)
+}
+
+func runMigrateDashboards(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := newFlagSet("migrate-dashboards", "[file...]", stderr)
+	f := addMigrateFlags(fs, "dashboards")
+	if status, ok := parseFlags(fs, args); !ok {
+		return status
+	}
+	metrics, err := f.metrics()
+	if err != nil {
+		return exitStatus(stderr, err)
+	}
+	m, err := dashboards.NewMigrator(dashboards.MigratorOptions{
+		From:       otlptranslator.TranslationStrategyOption(f.from),
+		To:         otlptranslator.TranslationStrategyOption(f.to),
+		Namespace:  f.namespace,
+		Metrics:    metrics,
+		Attributes: f.attributes,
+	})
+	if err != nil {
+		return exitStatus(stderr, err)
+	}
+	return runMigrate(fs, f, func(name string, content []byte) ([]byte, string, []fmt.Stringer, error) {
+		res, err := m.Migrate(name, content)
+		ambiguities := make([]fmt.Stringer, len(res.Ambiguities))
+		for i, a := range res.Ambiguities {
+			ambiguities[i] = a
+		}
+		return res.Content, res.Diff, ambiguities, err
+	}, stdin, stdout, stderr)
+}
+
+// runMigrate migrates the files given as arguments, or the standard input,
+// printing their diffs or writing them, and reporting ambiguities.
+func runMigrate(fs *flag.FlagSet, f *migrateFlags, migrate migrateFunc, stdin io.Reader, stdout, stderr io.Writer) int {
+	if f.write && fs.NArg() == 0 {
+		fmt.Fprintln(stderr, "otlptranslate: -w requires files")
+		return 2
+	}
+	var failed bool
+	migrateFile := func(name string, content []byte) {
+		migrated, diff, ambiguities, err := migrate(name, content)
+		if err != nil {
+			fmt.Fprintf(stderr, "otlptranslate: %v\n", err)
+			failed = true
+			return
+		}
+		for _, a := range ambiguities {
+			fmt.Fprintf(stderr, "otlptranslate: %s: %s\n", name, a)
+			failed = true
+		}
+		switch {
+		case !f.write:
+			io.WriteString(stdout, diff) //nolint:errcheck // Write errors are not reported for diffs.
+		case diff != "":
+			if err := os.WriteFile(name, migrated, 0o666); err != nil {
+				fmt.Fprintf(stderr, "otlptranslate: %v\n", err)
+				failed = true
+			}
+		}
+	}
+	if fs.NArg() == 0 {
+		content, err := io.ReadAll(stdin)
+		if err != nil {
+			return exitStatus(stderr, err)
+		}
+		migrateFile("stdin", content)
+	}
+	for _, name := range fs.Args() {
+		content, err := os.ReadFile(name)
+		if err != nil {
+			fmt.Fprintf(stderr, "otlptranslate: %v\n", err)
+			failed = true
+			continue
+		}
+		migrateFile(name, content)
+	}
+	if failed {
+		return exitStatus(stderr, errTranslation)
+	}
+	return 0
+}
+
+// metrics reads the known metrics of the -metrics file, in the format
+// matching its extension.
+func (f *migrateFlags) metrics() ([]otlptranslator.Metric, error) {
+	if f.metricsFile == "" {
+		return nil, nil
+	}
+	file, err := os.Open(f.metricsFile)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	format, err := inputFormat("", f.metricsFile)
+	if err != nil {
+		return nil, err
+	}
+	var metrics []otlptranslator.Metric
+	err = forEachRow(file, format, func(line int, r row) error {
+		metric, err := r.metric()
+		if err != nil {
+			return fmt.Errorf("%s: line %d: %w", f.metricsFile, line, err)
+		}
+		metrics = append(metrics, metric)
+		return nil
+	})
+	return metrics, err
+}
@@ -0,0 +1,142 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const rulesInput = `groups:
+  - name: http
+    rules:
+      - alert: HighLatency
+        expr: histogram_quantile(0.9, sum by (le, http_route) (rate(http_server_request_duration_seconds_bucket[5m]))) > 1
+`
+
+func TestRunMigrateRules(t *testing.T) {
+	dir := t.TempDir()
+	metrics := filepath.Join(dir, "metrics.csv")
+	if err := os.WriteFile(metrics, []byte("http.server.request.duration,s,histogram\n"), 0o666); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantStatus int
+		wantStdout string
+		wantStderr string
+	}{
+		{
+			name: "known names",
+			args: []string{"migrate-rules", "-metrics", metrics, "-attributes", "http.route"},
+			wantStdout: `--- a/stdin
++++ b/stdin
+@@ -2,4 +2,4 @@
+   - name: http
+     rules:
+       - alert: HighLatency
+-        expr: histogram_quantile(0.9, sum by (le, http_route) (rate(http_server_request_duration_seconds_bucket[5m]))) > 1
++        expr: histogram_quantile(0.9, sum by (le, "http.route") (rate({"http.server.request.duration_seconds_bucket"}[5m]))) > 1
+`,
+		},
+		{
+			name:       "ambiguous names",
+			args:       []string{"migrate-rules", "-from", "UnderscoreEscapingWithSuffixes", "-to", "NoTranslation"},
+			wantStatus: 1,
+			wantStdout: "--- a/stdin\n",
+			wantStderr: `otlptranslate: stdin: line 5, group "http", rule "HighLatency": http_route: the attribute is unknown`,
+		},
+		{
+			name:       "write without files",
+			args:       []string{"migrate-rules", "-w"},
+			wantStatus: 2,
+			wantStderr: "otlptranslate: -w requires files\n",
+		},
+		{
+			name:       "unknown strategy",
+			args:       []string{"migrate-rules", "-to", "Other"},
+			wantStatus: 2,
+			wantStderr: `unknown strategy "Other"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			status := run(tt.args, strings.NewReader(rulesInput), &stdout, &stderr)
+			if status != tt.wantStatus {
+				t.Errorf("exit status = %d, want %d (stderr: %s)", status, tt.wantStatus, stderr.String())
+			}
+			if !strings.HasPrefix(stdout.String(), tt.wantStdout) || tt.wantStatus == 0 && stdout.String() != tt.wantStdout {
+				t.Errorf("stdout:\n%s\nwant:\n%s", stdout.String(), tt.wantStdout)
+			}
+			if !strings.Contains(stderr.String(), tt.wantStderr) {
+				t.Errorf("stderr = %q, want it to contain %q", stderr.String(), tt.wantStderr)
+			}
+		})
+	}
+}
+
+func TestRunMigrateRules_Write(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "rules.yml")
+	if err := os.WriteFile(file, []byte(rulesInput), 0o666); err != nil {
+		t.Fatal(err)
+	}
+	var stdout, stderr bytes.Buffer
+	args := []string{"migrate-rules", "-to", "UnderscoreEscapingWithoutSuffixes", "-w", file}
+	if status := run(args, strings.NewReader(""), &stdout, &stderr); status != 0 {
+		t.Fatalf("exit status = %d (stderr: %s)", status, stderr.String())
+	}
+	got, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Replace(rulesInput, "http_server_request_duration_seconds_bucket", "http_server_request_duration_bucket", 1)
+	if string(got) != want || stdout.Len() > 0 {
+		t.Errorf("rule file =\n%s\nwant\n%s\nstdout: %s", got, want, stdout.String())
+	}
+}
+
+func TestRunMigrateDashboards(t *testing.T) {
+	in := `{"panels": [{"title": "Requests", "targets": [{"expr": "sum by (http_route) (rate(http_server_requests_total[5m]))", "legendFormat": "{{http_route}}"}]}]}` + "\n"
+	var stdout, stderr bytes.Buffer
+	args := []string{"migrate-dashboards", "-to", "UnderscoreEscapingWithoutSuffixes"}
+	if status := run(args, strings.NewReader(in), &stdout, &stderr); status != 0 {
+		t.Fatalf("exit status = %d (stderr: %s)", status, stderr.String())
+	}
+	want := `--- a/stdin
++++ b/stdin
+@@ -1 +1 @@
+-{"panels": [{"title": "Requests", "targets": [{"expr": "sum by (http_route) (rate(http_server_requests_total[5m]))", "legendFormat": "{{http_route}}"}]}]}
++{"panels": [{"title": "Requests", "targets": [{"expr": "sum by (http_route) (rate(http_server_requests[5m]))", "legendFormat": "{{http_route}}"}]}]}
+`
+	if stdout.String() != want {
+		t.Errorf("stdout:\n%s\nwant:\n%s", stdout.String(), want)
+	}
+
+	stdout.Reset()
+	stderr.Reset()
+	args = []string{"migrate-dashboards", "-to", "NoTranslation", "-attributes", "http.route"}
+	if status := run(args, strings.NewReader(in), &stdout, &stderr); status != 1 {
+		t.Errorf("exit status = %d, want 1 (stderr: %s)", status, stderr.String())
+	}
+	wantStderr := `otlptranslate: stdin: panels[0].targets[0].expr, panel "Requests": http_server_requests_total: the metric is unknown, and underscores of its name may stand for escaped characters (candidates: http_server_requests)`
+	if !strings.Contains(stderr.String(), wantStderr) || !strings.Contains(stdout.String(), `{{http.route}}`) {
+		t.Errorf("stdout = %s\nstderr = %s", stdout.String(), stderr.String())
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/prometheus/otlptranslator"
+	"github.com/prometheus/otlptranslator/renaming"
+)
+
+func runNames(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := newFlagSet("names", "[file]", stderr)
+	strategy := addStrategyFlag(fs)
+	namespace := fs.String("namespace", "", "namespace prepended to metric names")
+	format := fs.String("format", "", "input format: csv, tsv or jsonl (default: from the file extension, csv for the standard input)")
+	rulesFile := fs.String("rules", "", "YAML `file` of renaming rules applied around translation")
+	if status, ok := parseFlags(fs, args); !ok {
+		return status
+	}
+	if fs.NArg() > 1 {
+		fs.Usage()
+		return 2
+	}
+	rules := &renaming.Rules{}
+	if *rulesFile != "" {
+		var err error
+		if rules, err = renaming.LoadFile(*rulesFile); err != nil {
+			return exitStatus(stderr, err)
+		}
+	}
+
+	in, name := stdin, ""
+	if fs.NArg() == 1 {
+		name = fs.Arg(0)
+		f, err := os.Open(name)
+		if err != nil {
+			return exitStatus(stderr, err)
+		}
+		defer f.Close()
+		in = f
+	}
+	rowFormat, err := inputFormat(*format, name)
+	if err != nil {
+		fmt.Fprintf(stderr, "otlptranslate: %v\n", err)
+		return 2
+	}
+
+	namer := otlptranslator.NewMetricNamer(*namespace, otlptranslator.TranslationStrategyOption(*strategy))
+	w := bufio.NewWriter(stdout)
+	defer w.Flush()
+	var failed bool
+	err = forEachRow(in, rowFormat, func(line int, r row) error {
+		metric, err := r.metric()
+		if err == nil {
+			var (
+				translated string
+				ok         bool
+			)
+			if translated, ok, err = rules.Build(&namer, metric); err == nil {
+				if ok {
+					fmt.Fprintln(w, translated)
+				}
+				return nil
+			}
+		}
+		failed = true
+		w.Flush()
+		fmt.Fprintf(stderr, "otlptranslate: line %d: %v\n", line, err)
+		return nil
+	})
+	if err == nil && failed {
+		err = errTranslation
+	}
+	return exitStatus(stderr, err)
+}
+
+func runLabels(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := newFlagSet("labels", "[key...]", stderr)
+	strategy := addStrategyFlag(fs)
+	if status, ok := parseFlags(fs, args); !ok {
+		return status
+	}
+
+	keys := fs.Args()
+	if len(keys) == 0 {
+		s := bufio.NewScanner(stdin)
+		for s.Scan() {
+			if key := strings.TrimSpace(s.Text()); key != "" {
+				keys = append(keys, key)
+			}
+		}
+		if err := s.Err(); err != nil {
+			return exitStatus(stderr, err)
+		}
+	}
+
+	namer := otlptranslator.LabelNamer{UTF8Allowed: !otlptranslator.TranslationStrategyOption(*strategy).ShouldEscape()}
+	w := bufio.NewWriter(stdout)
+	defer w.Flush()
+	var err error
+	for _, key := range keys {
+		translated, buildErr := namer.Build(key)
+		if buildErr != nil {
+			w.Flush()
+			fmt.Fprintf(stderr, "otlptranslate: label %q: %v\n", key, buildErr)
+			err = errTranslation
+			continue
+		}
+		fmt.Fprintln(w, translated)
+	}
+	return exitStatus(stderr, err)
+}
@@ -0,0 +1,140 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/prometheus/otlptranslator"
+)
+
+// Input formats of the names command.
+const (
+	formatCSV   = "csv"
+	formatTSV   = "tsv"
+	formatJSONL = "jsonl"
+)
+
+// inputFormat returns the format of the input rows: the one given by the
+// -format flag, or the one matching the extension of the file.
+func inputFormat(format, file string) (string, error) {
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(file)) {
+		case ".tsv":
+			return formatTSV, nil
+		case ".jsonl", ".ndjson", ".json":
+			return formatJSONL, nil
+		default:
+			return formatCSV, nil
+		}
+	}
+	switch format = strings.ToLower(format); format {
+	case formatCSV, formatTSV, formatJSONL:
+		return format, nil
+	default:
+		return "", fmt.Errorf("unknown format %q, want csv, tsv or jsonl", format)
+	}
+}
+
+// row is a `name,unit,type` input row.
+type row struct {
+	Name string `json:"name"`
+	Unit string `json:"unit"`
+	Type string `json:"type"`
+}
+
+// metricTypeAliases maps the values of the type column accepted besides the
+// names of otlptranslator.ParseMetricType to metric types.
+var metricTypeAliases = map[string]otlptranslator.MetricType{
+	"":                      otlptranslator.MetricTypeUnknown,
+	"sum":                   otlptranslator.MetricTypeMonotonicCounter,
+	"monotonic_counter":     otlptranslator.MetricTypeMonotonicCounter,
+	"non_monotonic_counter": otlptranslator.MetricTypeNonMonotonicCounter,
+}
+
+// metric returns the metric described by the row.
+func (r row) metric() (otlptranslator.Metric, error) {
+	name := strings.ToLower(strings.TrimSpace(r.Type))
+	typ, ok := metricTypeAliases[name]
+	if !ok {
+		var err error
+		if typ, err = otlptranslator.ParseMetricType(name); err != nil {
+			return otlptranslator.Metric{}, fmt.Errorf("unknown metric type %q", r.Type)
+		}
+	}
+	return otlptranslator.Metric{Name: r.Name, Unit: r.Unit, Type: typ}, nil
+}
+
+// forEachRow calls fn for every row of in, along with its line number. A
+// `name,unit,type` header, and empty lines, are skipped. It stops at the
+// first error returned by fn or met while reading.
+func forEachRow(in io.Reader, format string, fn func(line int, r row) error) error {
+	if format == formatJSONL {
+		return forEachJSONRow(in, fn)
+	}
+	cr := csv.NewReader(in)
+	if format == formatTSV {
+		cr.Comma = '\t'
+		cr.LazyQuotes = true
+	}
+	cr.FieldsPerRecord = -1
+	for first := true; ; first = false {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		line, _ := cr.FieldPos(0)
+		if len(record) > 3 {
+			return fmt.Errorf("line %d: %d fields, want at most 3", line, len(record))
+		}
+		record = append(record, "", "")
+		r := row{Name: strings.TrimSpace(record[0]), Unit: strings.TrimSpace(record[1]), Type: record[2]}
+		if first && strings.EqualFold(r.Name, "name") && strings.EqualFold(r.Unit, "unit") && strings.EqualFold(r.Type, "type") {
+			continue
+		}
+		if err := fn(line, r); err != nil {
+			return err
+		}
+	}
+}
+
+func forEachJSONRow(in io.Reader, fn func(line int, r row) error) error {
+	s := bufio.NewScanner(in)
+	s.Buffer(nil, 1<<20)
+	for line := 1; s.Scan(); line++ {
+		b := bytes.TrimSpace(s.Bytes())
+		if len(b) == 0 {
+			continue
+		}
+		var r row
+		if err := json.Unmarshal(b, &r); err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+		if err := fn(line, r); err != nil {
+			return err
+		}
+	}
+	return s.Err()
+}
@@ -0,0 +1,23 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package compare reports how a corpus of OpenTelemetry metrics and
+// attributes is translated under every translation strategy, to see the
+// impact of a strategy before choosing it.
+//
+// Main components:
+//   - NewReport: Translates the corpus under the compared strategies
+//   - Report: The translated names, the ones differing between strategies,
+//     and the names colliding within a strategy, written as CSV, Markdown or
+//     JSON
+package compare
@@ -0,0 +1,275 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compare
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/prometheus/otlptranslator"
+)
+
+// Strategies are the translation strategies compared by default.
+var Strategies = []otlptranslator.TranslationStrategyOption{
+	otlptranslator.UnderscoreEscapingWithSuffixes,
+	otlptranslator.UnderscoreEscapingWithoutSuffixes,
+	otlptranslator.NoUTF8EscapingWithSuffixes,
+	otlptranslator.NoTranslation,
+}
+
+// Options configures a Report.
+type Options struct {
+	// Namespace is prepended to metric names.
+	Namespace string
+	// Strategies are the compared translation strategies, in the order of
+	// the report columns. All the Strategies are compared if empty.
+	Strategies []otlptranslator.TranslationStrategyOption
+}
+
+// Kinds of translated names.
+const (
+	KindMetric = "metric"
+	KindLabel  = "label"
+)
+
+// Report holds the names of a corpus of metrics and attributes translated
+// under several strategies.
+type Report struct {
+	Strategies []otlptranslator.TranslationStrategyOption `json:"strategies"`
+	Metrics    []Row                                      `json:"metrics"`
+	Labels     []Row                                      `json:"labels"`
+	Collisions []Collision                                `json:"collisions"`
+}
+
+// Row is a metric or an attribute of the corpus, and its translations.
+type Row struct {
+	Name string `json:"name"`
+	// Unit and Type are only set for metrics.
+	Unit string `json:"unit,omitempty"`
+	Type string `json:"type,omitempty"`
+	// Translations holds the translation of the row under every strategy
+	// of the report, in the same order.
+	Translations []Translation `json:"translations"`
+	// Differs reports whether the translations are not all the same.
+	Differs bool `json:"differs"`
+}
+
+// Translation is the translation of a name under a strategy.
+type Translation struct {
+	Strategy otlptranslator.TranslationStrategyOption `json:"strategy"`
+	Name     string                                   `json:"name,omitempty"`
+	// Error is set if the name cannot be translated under the strategy.
+	Error string `json:"error,omitempty"`
+	// Collides reports whether the translated name collides with the one
+	// of another row under the strategy.
+	Collides bool `json:"collides,omitempty"`
+}
+
+// String returns the translated name, or the translation error.
+func (t Translation) String() string {
+	if t.Error != "" {
+		return "error: " + t.Error
+	}
+	return t.Name
+}
+
+// Collision is a name shared by several metrics, or several attributes,
+// once translated under a strategy. Colliding metrics are exposed as a single
+// metric whose series may silently overwrite each other, and colliding
+// attributes are merged into a single label.
+type Collision struct {
+	Strategy otlptranslator.TranslationStrategyOption `json:"strategy"`
+	Kind     string                                   `json:"kind"`
+	// Name is the colliding name. For metrics, it is the name of a series,
+	// such as the `_count` series of a histogram.
+	Name string `json:"name"`
+	// Sources describes the colliding metrics, or attributes.
+	Sources []string `json:"sources"`
+}
+
+// NewReport translates metrics and attributes under the strategies of opts.
+// Duplicate metrics and attributes are reported once.
+func NewReport(metrics []otlptranslator.Metric, attributes []string, opts Options) (*Report, error) {
+	strategies := opts.Strategies
+	if len(strategies) == 0 {
+		strategies = Strategies
+	}
+	for _, s := range strategies {
+		if !slices.Contains(Strategies, s) {
+			return nil, fmt.Errorf("unknown translation strategy %q", s)
+		}
+	}
+	r := &Report{Strategies: slices.Clone(strategies), Collisions: []Collision{}}
+
+	metrics = compactMetrics(metrics)
+	r.Metrics = make([]Row, len(metrics))
+	for i, m := range metrics {
+		r.Metrics[i] = Row{Name: m.Name, Unit: m.Unit, Type: m.Type.String()}
+	}
+	attributes = compactAttributes(attributes)
+	r.Labels = make([]Row, len(attributes))
+	for i, a := range attributes {
+		r.Labels[i] = Row{Name: a}
+	}
+
+	for i, s := range strategies {
+		namer := otlptranslator.NewMetricNamer(opts.Namespace, s)
+		series := map[string][]int{}
+		for j, m := range metrics {
+			name, err := namer.Build(m)
+			r.Metrics[j].Translations = append(r.Metrics[j].Translations, translation(s, name, err))
+			if err != nil {
+				continue
+			}
+			for _, n := range seriesNames(name, m.Type) {
+				series[n] = append(series[n], j)
+			}
+		}
+		r.addCollisions(i, KindMetric, series, r.Metrics, func(j int) string {
+			return describe(metrics[j])
+		})
+
+		labelNamer := otlptranslator.LabelNamer{UTF8Allowed: !s.ShouldEscape()}
+		labels := map[string][]int{}
+		for j, a := range attributes {
+			name, err := labelNamer.Build(a)
+			r.Labels[j].Translations = append(r.Labels[j].Translations, translation(s, name, err))
+			if err == nil {
+				labels[name] = append(labels[name], j)
+			}
+		}
+		r.addCollisions(i, KindLabel, labels, r.Labels, func(j int) string {
+			return attributes[j]
+		})
+	}
+
+	for _, rows := range [][]Row{r.Metrics, r.Labels} {
+		for i := range rows {
+			rows[i].Differs = differs(rows[i].Translations)
+		}
+	}
+	slices.SortStableFunc(r.Collisions, func(a, b Collision) int {
+		if c := slices.Index(strategies, a.Strategy) - slices.Index(strategies, b.Strategy); c != 0 {
+			return c
+		}
+		if a.Kind != b.Kind {
+			// Metric collisions come first.
+			return strings.Compare(b.Kind, a.Kind)
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	return r, nil
+}
+
+// HasErrors reports whether a name of the report cannot be translated under
+// one of the strategies.
+func (r *Report) HasErrors() bool {
+	for _, rows := range [][]Row{r.Metrics, r.Labels} {
+		for _, row := range rows {
+			for _, t := range row.Translations {
+				if t.Error != "" {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// addCollisions records the names shared by several rows under
+// the i-th strategy, and flags the translations of these rows.
+func (r *Report) addCollisions(i int, kind string, names map[string][]int, rows []Row, source func(int) string) {
+	for name, idx := range names {
+		idx = slices.Compact(idx)
+		if len(idx) < 2 {
+			continue
+		}
+		c := Collision{Strategy: r.Strategies[i], Kind: kind, Name: name}
+		for _, j := range idx {
+			rows[j].Translations[i].Collides = true
+			c.Sources = append(c.Sources, source(j))
+		}
+		r.Collisions = append(r.Collisions, c)
+	}
+}
+
+func translation(s otlptranslator.TranslationStrategyOption, name string, err error) Translation {
+	if err != nil {
+		return Translation{Strategy: s, Error: err.Error()}
+	}
+	return Translation{Strategy: s, Name: name}
+}
+
+func differs(translations []Translation) bool {
+	for _, t := range translations[1:] {
+		if t.Name != translations[0].Name || t.Error != translations[0].Error {
+			return true
+		}
+	}
+	return false
+}
+
+// seriesNames returns the names of the series of a metric translated to
+// name, as exposed by Prometheus.
+func seriesNames(name string, typ otlptranslator.MetricType) []string {
+	switch typ {
+	case otlptranslator.MetricTypeHistogram:
+		return []string{name, name + "_bucket", name + "_sum", name + "_count"}
+	case otlptranslator.MetricTypeSummary:
+		return []string{name, name + "_sum", name + "_count"}
+	default:
+		return []string{name}
+	}
+}
+
+// describe identifies a metric in collisions, as metrics sharing a name may
+// differ by their unit or type.
+func describe(m otlptranslator.Metric) string {
+	if m.Unit == "" {
+		return fmt.Sprintf("%s (%s)", m.Name, m.Type)
+	}
+	return fmt.Sprintf("%s (%s, %s)", m.Name, m.Unit, m.Type)
+}
+
+// compactMetrics removes the metrics with the same name, unit and type as a
+// previous one.
+func compactMetrics(metrics []otlptranslator.Metric) []otlptranslator.Metric {
+	type key struct {
+		name, unit string
+		typ        otlptranslator.MetricType
+	}
+	seen := make(map[key]bool, len(metrics))
+	res := make([]otlptranslator.Metric, 0, len(metrics))
+	for _, m := range metrics {
+		k := key{m.Name, m.Unit, m.Type}
+		if !seen[k] {
+			seen[k] = true
+			res = append(res, m)
+		}
+	}
+	return res
+}
+
+func compactAttributes(attributes []string) []string {
+	seen := make(map[string]bool, len(attributes))
+	res := make([]string, 0, len(attributes))
+	for _, a := range attributes {
+		if !seen[a] {
+			seen[a] = true
+			res = append(res, a)
+		}
+	}
+	return res
+}
@@ -0,0 +1,209 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compare
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/otlptranslator"
+)
+
+var corpus = []otlptranslator.Metric{
+	{Name: "http.server.request.duration", Unit: "s", Type: otlptranslator.MetricTypeHistogram},
+	{Name: "http.server.request.duration.count", Type: otlptranslator.MetricTypeGauge},
+	{Name: "requests", Unit: "{request}", Type: otlptranslator.MetricTypeMonotonicCounter},
+	{Name: "requests", Unit: "{request}", Type: otlptranslator.MetricTypeMonotonicCounter},
+	{Name: "requests", Unit: "{request}", Type: otlptranslator.MetricTypeGauge},
+}
+
+func TestNewReport(t *testing.T) {
+	r, err := NewReport(corpus, []string{"http.route", "http_route", "job", "__"}, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names := func(rows []Row) [][]string {
+		res := make([][]string, len(rows))
+		for i, row := range rows {
+			for _, tr := range row.Translations {
+				res[i] = append(res[i], tr.String())
+			}
+		}
+		return res
+	}
+	wantMetrics := [][]string{
+		{"http_server_request_duration_seconds", "http_server_request_duration", "http.server.request.duration_seconds", "http.server.request.duration"},
+		{"http_server_request_duration_count", "http_server_request_duration_count", "http.server.request.duration.count", "http.server.request.duration.count"},
+		{"requests_total", "requests", "requests_total", "requests"},
+		{"requests", "requests", "requests", "requests"},
+	}
+	if got := names(r.Metrics); !reflect.DeepEqual(got, wantMetrics) {
+		t.Errorf("metrics = %q, want %q", got, wantMetrics)
+	}
+	wantLabels := [][]string{
+		{"http_route", "http_route", "http.route", "http.route"},
+		{"http_route", "http_route", "http_route", "http_route"},
+		{"job", "job", "job", "job"},
+		{
+			`error: normalization for label name "__" resulted in invalid name "_"`,
+			`error: normalization for label name "__" resulted in invalid name "_"`,
+			`error: label name "__" contains only underscores`,
+			`error: label name "__" contains only underscores`,
+		},
+	}
+	if got := names(r.Labels); !reflect.DeepEqual(got, wantLabels) {
+		t.Errorf("labels = %q, want %q", got, wantLabels)
+	}
+
+	var differing []string
+	for _, rows := range [][]Row{r.Metrics, r.Labels} {
+		for _, row := range rows {
+			if row.Differs {
+				differing = append(differing, row.Name)
+			}
+		}
+	}
+	wantDiffering := []string{"http.server.request.duration", "http.server.request.duration.count", "requests", "http.route", "__"}
+	if !reflect.DeepEqual(differing, wantDiffering) {
+		t.Errorf("differing rows = %q, want %q", differing, wantDiffering)
+	}
+
+	wantCollisions := []Collision{
+		{
+			Strategy: otlptranslator.UnderscoreEscapingWithoutSuffixes,
+			Kind:     KindMetric,
+			Name:     "http_server_request_duration_count",
+			Sources:  []string{"http.server.request.duration (s, histogram)", "http.server.request.duration.count (gauge)"},
+		},
+		{
+			Strategy: otlptranslator.UnderscoreEscapingWithoutSuffixes,
+			Kind:     KindMetric,
+			Name:     "requests",
+			Sources:  []string{"requests ({request}, counter)", "requests ({request}, gauge)"},
+		},
+		{
+			Strategy: otlptranslator.UnderscoreEscapingWithoutSuffixes,
+			Kind:     KindLabel,
+			Name:     "http_route",
+			Sources:  []string{"http.route", "http_route"},
+		},
+		{
+			Strategy: otlptranslator.NoTranslation,
+			Kind:     KindMetric,
+			Name:     "requests",
+			Sources:  []string{"requests ({request}, counter)", "requests ({request}, gauge)"},
+		},
+	}
+	var got []Collision
+	for _, c := range r.Collisions {
+		if c.Strategy != otlptranslator.UnderscoreEscapingWithSuffixes {
+			got = append(got, c)
+		}
+	}
+	if !reflect.DeepEqual(got, wantCollisions) {
+		t.Errorf("collisions = %+v, want %+v", got, wantCollisions)
+	}
+	if !r.HasErrors() {
+		t.Error("HasErrors() = false, want true")
+	}
+}
+
+func TestNewReport_Strategies(t *testing.T) {
+	r, err := NewReport(corpus[:1], nil, Options{
+		Namespace:  "app",
+		Strategies: []otlptranslator.TranslationStrategyOption{otlptranslator.NoTranslation},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Translation{{Strategy: otlptranslator.NoTranslation, Name: "app_http.server.request.duration"}}
+	if !reflect.DeepEqual(r.Metrics[0].Translations, want) || r.Metrics[0].Differs {
+		t.Errorf("metric = %+v, want translations %+v", r.Metrics[0], want)
+	}
+
+	if _, err := NewReport(corpus, nil, Options{Strategies: []otlptranslator.TranslationStrategyOption{"Bogus"}}); err == nil {
+		t.Error("unknown strategy: got no error")
+	}
+}
+
+func TestReport_Write(t *testing.T) {
+	r, err := NewReport(corpus[:2], []string{"http.route", "http_route"}, Options{
+		Strategies: []otlptranslator.TranslationStrategyOption{
+			otlptranslator.UnderscoreEscapingWithSuffixes,
+			otlptranslator.UnderscoreEscapingWithoutSuffixes,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := r.WriteCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	wantCSV := `kind,name,unit,type,UnderscoreEscapingWithSuffixes,UnderscoreEscapingWithoutSuffixes,differs,collides
+metric,http.server.request.duration,s,histogram,http_server_request_duration_seconds,http_server_request_duration,true,UnderscoreEscapingWithoutSuffixes
+metric,http.server.request.duration.count,,gauge,http_server_request_duration_count,http_server_request_duration_count,false,UnderscoreEscapingWithoutSuffixes
+label,http.route,,,http_route,http_route,false,UnderscoreEscapingWithSuffixes UnderscoreEscapingWithoutSuffixes
+label,http_route,,,http_route,http_route,false,UnderscoreEscapingWithSuffixes UnderscoreEscapingWithoutSuffixes
+`
+	if got := buf.String(); got != wantCSV {
+		t.Errorf("CSV:\n%s\nwant:\n%s", got, wantCSV)
+	}
+
+	buf.Reset()
+	if err := r.WriteMarkdown(&buf); err != nil {
+		t.Fatal(err)
+	}
+	wantMarkdown := "# Translation strategies\n\n" +
+		"1 of 2 metrics and 0 of 2 attributes are translated differently between strategies, with 3 collisions.\n\n" +
+		"## Metrics\n\n" +
+		"| Name | Unit | Type | UnderscoreEscapingWithSuffixes | UnderscoreEscapingWithoutSuffixes | Differs |\n" +
+		"| --- | --- | --- | --- | --- | --- |\n" +
+		"| `http.server.request.duration` | s | histogram | `http_server_request_duration_seconds` | `http_server_request_duration` (collision) | yes |\n" +
+		"| `http.server.request.duration.count` |  | gauge | `http_server_request_duration_count` | `http_server_request_duration_count` (collision) |  |\n\n" +
+		"## Labels\n\n" +
+		"| Attribute | UnderscoreEscapingWithSuffixes | UnderscoreEscapingWithoutSuffixes | Differs |\n" +
+		"| --- | --- | --- | --- |\n" +
+		"| `http.route` | `http_route` (collision) | `http_route` (collision) |  |\n" +
+		"| `http_route` | `http_route` (collision) | `http_route` (collision) |  |\n\n" +
+		"## Collisions\n\n" +
+		"| Strategy | Kind | Name | Sources |\n" +
+		"| --- | --- | --- | --- |\n" +
+		"| UnderscoreEscapingWithSuffixes | label | `http_route` | http.route<br>http\\_route |\n" +
+		"| UnderscoreEscapingWithoutSuffixes | metric | `http_server_request_duration_count` | http.server.request.duration (s, histogram)<br>http.server.request.duration.count (gauge) |\n" +
+		"| UnderscoreEscapingWithoutSuffixes | label | `http_route` | http.route<br>http\\_route |\n"
+	if got := buf.String(); got != wantMarkdown {
+		t.Errorf("Markdown:\n%s\nwant:\n%s", got, wantMarkdown)
+	}
+
+	buf.Reset()
+	if err := r.WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var decoded Report
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(&decoded, r) {
+		t.Errorf("JSON round trip = %+v, want %+v", decoded, *r)
+	}
+	if !strings.Contains(buf.String(), `"collides": true`) {
+		t.Errorf("JSON does not flag collisions:\n%s", buf.String())
+	}
+}
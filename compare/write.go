@@ -0,0 +1,175 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compare
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteCSV writes the report as CSV, with a `kind,name,unit,type` header
+// followed by a column per strategy, a `differs` column and a `collides`
+// column listing the strategies under which the row collides. Collisions are
+// not written separately.
+func (r *Report) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	header := []string{"kind", "name", "unit", "type"}
+	for _, s := range r.Strategies {
+		header = append(header, string(s))
+	}
+	if err := cw.Write(append(header, "differs", "collides")); err != nil {
+		return err
+	}
+	for _, rows := range []struct {
+		kind string
+		rows []Row
+	}{{KindMetric, r.Metrics}, {KindLabel, r.Labels}} {
+		for _, row := range rows.rows {
+			record := []string{rows.kind, row.Name, row.Unit, row.Type}
+			var collides []string
+			for _, t := range row.Translations {
+				record = append(record, t.String())
+				if t.Collides {
+					collides = append(collides, string(t.Strategy))
+				}
+			}
+			record = append(record, fmt.Sprint(row.Differs), strings.Join(collides, " "))
+			if err := cw.Write(record); err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteJSON writes the report as an indented JSON object.
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// WriteMarkdown writes the report as Markdown tables of metrics, labels and
+// collisions. Rows whose translations differ between strategies are marked,
+// and so are the translated names that collide.
+func (r *Report) WriteMarkdown(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "# Translation strategies\n\n%d of %d metrics and %d of %d attributes are translated differently between strategies, with %d collisions.\n",
+		countDiffering(r.Metrics), len(r.Metrics), countDiffering(r.Labels), len(r.Labels), len(r.Collisions))
+
+	strategies := make([]string, len(r.Strategies))
+	for i, s := range r.Strategies {
+		strategies[i] = string(s)
+	}
+	if len(r.Metrics) > 0 {
+		fmt.Fprint(bw, "\n## Metrics\n\n")
+		writeTableHeader(bw, append([]string{"Name", "Unit", "Type"}, append(strategies, "Differs")...))
+		for _, row := range r.Metrics {
+			writeTableRow(bw, append([]string{code(row.Name), markdownText(row.Unit), row.Type}, markdownTranslations(row)...))
+		}
+	}
+	if len(r.Labels) > 0 {
+		fmt.Fprint(bw, "\n## Labels\n\n")
+		writeTableHeader(bw, append([]string{"Attribute"}, append(strategies, "Differs")...))
+		for _, row := range r.Labels {
+			writeTableRow(bw, append([]string{code(row.Name)}, markdownTranslations(row)...))
+		}
+	}
+	if len(r.Collisions) > 0 {
+		fmt.Fprint(bw, "\n## Collisions\n\n")
+		writeTableHeader(bw, []string{"Strategy", "Kind", "Name", "Sources"})
+		for _, c := range r.Collisions {
+			sources := make([]string, len(c.Sources))
+			for i, s := range c.Sources {
+				sources[i] = markdownText(s)
+			}
+			writeTableRow(bw, []string{string(c.Strategy), c.Kind, code(c.Name), strings.Join(sources, "<br>")})
+		}
+	}
+	return bw.Flush()
+}
+
+func countDiffering(rows []Row) int {
+	var n int
+	for _, row := range rows {
+		if row.Differs {
+			n++
+		}
+	}
+	return n
+}
+
+// markdownTranslations returns the cells of the translations of row, and
+// of its Differs column.
+func markdownTranslations(row Row) []string {
+	cells := make([]string, 0, len(row.Translations)+1)
+	for _, t := range row.Translations {
+		var cell string
+		switch {
+		case t.Error != "":
+			cell = "error: " + markdownText(t.Error)
+		case t.Collides:
+			cell = code(t.Name) + " (collision)"
+		default:
+			cell = code(t.Name)
+		}
+		cells = append(cells, cell)
+	}
+	differs := ""
+	if row.Differs {
+		differs = "yes"
+	}
+	return append(cells, differs)
+}
+
+// writeTableHeader writes the header row of a Markdown table, and its
+// delimiter row.
+func writeTableHeader(w *bufio.Writer, cells []string) {
+	writeTableRow(w, cells)
+	delimiters := make([]string, len(cells))
+	for i := range delimiters {
+		delimiters[i] = "---"
+	}
+	writeTableRow(w, delimiters)
+}
+
+func writeTableRow(w *bufio.Writer, cells []string) {
+	fmt.Fprintf(w, "| %s |\n", strings.Join(cells, " | "))
+}
+
+// code formats a name as a Markdown code span.
+func code(s string) string {
+	if s == "" {
+		return ""
+	}
+	if strings.Contains(s, "`") {
+		return markdownText(s)
+	}
+	return "`" + strings.ReplaceAll(s, "|", `\|`) + "`"
+}
+
+// markdownText escapes the characters of s that would break a table cell or
+// be interpreted as Markdown.
+func markdownText(s string) string {
+	return markdownReplacer.Replace(s)
+}
+
+var markdownReplacer = strings.NewReplacer(
+	`\`, `\\`, "|", `\|`, "`", "\\`", "*", `\*`, "_", `\_`, "<", "&lt;", "\n", " ",
+)
@@ -0,0 +1,126 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conformance
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/prometheus/otlptranslator"
+	"github.com/prometheus/otlptranslator/internal/legacy"
+)
+
+// CheckMetricName checks the properties metric name translations should
+// have, for any input, and returns an error describing the first violated
+// one. Inputs that cannot be translated are fine, as long as the namer
+// returns an error. The properties are:
+//   - Translating is deterministic.
+//   - Translated names are valid under the strategy: non-empty, valid UTF-8,
+//     and legacy Prometheus names if the strategy escapes them.
+//   - Translating a translated name again, without namespace, returns it
+//     unchanged, so that already translated names are not mangled, such as
+//     by duplicating a `_total` suffix.
+//
+// Names and units that are not valid UTF-8 are outside of the specification,
+// as OTLP strings are UTF-8, and are not checked.
+//
+// It is meant for fuzz targets:
+//
+//	f.Fuzz(func(t *testing.T, name, unit string) {
+//		metric := otlptranslator.Metric{Name: name, Unit: unit, Type: otlptranslator.MetricTypeGauge}
+//		if err := conformance.CheckMetricName(myNamer{}, metric, "", otlptranslator.UnderscoreEscapingWithSuffixes); err != nil {
+//			t.Error(err)
+//		}
+//	})
+func CheckMetricName(namer Namer, metric otlptranslator.Metric, namespace string, strategy otlptranslator.TranslationStrategyOption) error {
+	if !utf8.ValidString(metric.Name) || !utf8.ValidString(metric.Unit) || !utf8.ValidString(namespace) {
+		return nil
+	}
+	call := fmt.Sprintf("MetricName(%+v, %q, %s)", metric, namespace, strategy)
+	name, err := namer.MetricName(metric, namespace, strategy)
+	if err := checkDeterministic(call, name, err, func() (string, error) {
+		return namer.MetricName(metric, namespace, strategy)
+	}); err != nil {
+		return err
+	}
+	if err != nil {
+		return nil
+	}
+	if err := checkValid(call, name, strategy.ShouldEscape(), legacy.IsMetricName); err != nil {
+		return err
+	}
+
+	again := metric
+	again.Name = name
+	twice, err := namer.MetricName(again, "", strategy)
+	switch {
+	case err != nil:
+		return fmt.Errorf("MetricName(%+v, \"\", %s) of translated name %q: %w", again, strategy, name, err)
+	case twice != name:
+		return fmt.Errorf("MetricName(%+v, \"\", %s) = %q, want translated name %q unchanged", again, strategy, twice, name)
+	}
+	return nil
+}
+
+// CheckLabelName checks the properties attribute name translations should
+// have, as CheckMetricName does for metrics: translating is
+// deterministic, translated names are valid under the strategy, and
+// translating a translated name again returns it unchanged.
+func CheckLabelName(namer Namer, attribute string, strategy otlptranslator.TranslationStrategyOption) error {
+	if !utf8.ValidString(attribute) {
+		return nil
+	}
+	call := fmt.Sprintf("LabelName(%q, %s)", attribute, strategy)
+	name, err := namer.LabelName(attribute, strategy)
+	if err := checkDeterministic(call, name, err, func() (string, error) {
+		return namer.LabelName(attribute, strategy)
+	}); err != nil {
+		return err
+	}
+	if err != nil {
+		return nil
+	}
+	if err := checkValid(call, name, strategy.ShouldEscape(), legacy.IsLabelName); err != nil {
+		return err
+	}
+
+	twice, err := namer.LabelName(name, strategy)
+	switch {
+	case err != nil:
+		return fmt.Errorf("LabelName(%q, %s) of translated name: %w", name, strategy, err)
+	case twice != name:
+		return fmt.Errorf("LabelName(%q, %s) = %q, want translated name unchanged", name, strategy, twice)
+	}
+	return nil
+}
+
+func checkDeterministic(call, name string, err error, translate func() (string, error)) error {
+	name2, err2 := translate()
+	if name2 != name || (err == nil) != (err2 == nil) {
+		return fmt.Errorf("%s is not deterministic: %q, %v, then %q, %v", call, name, err, name2, err2)
+	}
+	return nil
+}
+
+func checkValid(call, name string, escaped bool, isLegacy func(string) bool) error {
+	switch {
+	case name == "":
+		return fmt.Errorf("%s = \"\" without error", call)
+	case !utf8.ValidString(name):
+		return fmt.Errorf("%s = %q, which is not valid UTF-8", call, name)
+	case escaped && !isLegacy(name):
+		return fmt.Errorf("%s = %q, which is not a valid legacy Prometheus name", call, name)
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conformance
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/prometheus/otlptranslator"
+)
+
+// Data holds the golden files of the suite: data/metric_names.json, holding
+// MetricCases, and data/label_names.json, holding LabelCases.
+//
+//go:embed data/*.json
+var Data embed.FS
+
+// Namer is the implementation checked by the suite.
+type Namer interface {
+	// MetricName translates the name of metric, prefixed with namespace if
+	// not empty, under strategy.
+	MetricName(metric otlptranslator.Metric, namespace string, strategy otlptranslator.TranslationStrategyOption) (string, error)
+	// LabelName translates an attribute name under strategy.
+	LabelName(attribute string, strategy otlptranslator.TranslationStrategyOption) (string, error)
+}
+
+// Reference is the Namer of this library, built from MetricNamer and
+// LabelNamer.
+var Reference Namer = referenceNamer{}
+
+type referenceNamer struct{}
+
+func (referenceNamer) MetricName(metric otlptranslator.Metric, namespace string, strategy otlptranslator.TranslationStrategyOption) (string, error) {
+	namer := otlptranslator.NewMetricNamer(namespace, strategy)
+	return namer.Build(metric)
+}
+
+func (referenceNamer) LabelName(attribute string, strategy otlptranslator.TranslationStrategyOption) (string, error) {
+	namer := otlptranslator.LabelNamer{UTF8Allowed: !strategy.ShouldEscape()}
+	return namer.Build(attribute)
+}
+
+// MetricCase is a case of metric name translation.
+type MetricCase struct {
+	Description string                                   `json:"description"`
+	Strategy    otlptranslator.TranslationStrategyOption `json:"strategy"`
+	Namespace   string                                   `json:"namespace,omitempty"`
+	Metric      Metric                                   `json:"metric"`
+	// Output is the expected name, if Error is empty.
+	Output string `json:"output,omitempty"`
+	// Error is the error returned by this library, if the name cannot be
+	// translated.
+	Error string `json:"error,omitempty"`
+}
+
+// Metric is an OpenTelemetry metric, as written in the golden files.
+type Metric struct {
+	Name string `json:"name"`
+	Unit string `json:"unit"`
+	// Type is gauge, counter, updowncounter, histogram,
+	// exponential_histogram, summary or unknown.
+	Type string `json:"type"`
+	// Temporality is delta, cumulative or empty.
+	Temporality string `json:"temporality,omitempty"`
+}
+
+// LabelCase is a case of attribute name translation.
+type LabelCase struct {
+	Description string                                   `json:"description"`
+	Strategy    otlptranslator.TranslationStrategyOption `json:"strategy"`
+	Attribute   string                                   `json:"attribute"`
+	// Output is the expected name, if Error is empty.
+	Output string `json:"output,omitempty"`
+	// Error is the error returned by this library, if the name cannot be
+	// translated.
+	Error string `json:"error,omitempty"`
+}
+
+var temporalities = map[string]otlptranslator.Temporality{
+	"":           otlptranslator.TemporalityUnspecified,
+	"delta":      otlptranslator.TemporalityDelta,
+	"cumulative": otlptranslator.TemporalityCumulative,
+}
+
+// Convert returns the metric as an otlptranslator.Metric.
+func (m Metric) Convert() (otlptranslator.Metric, error) {
+	typ, err := otlptranslator.ParseMetricType(m.Type)
+	if err != nil {
+		return otlptranslator.Metric{}, err
+	}
+	temporality, ok := temporalities[m.Temporality]
+	if !ok {
+		return otlptranslator.Metric{}, fmt.Errorf("unknown temporality %q", m.Temporality)
+	}
+	return otlptranslator.Metric{Name: m.Name, Unit: m.Unit, Type: typ, Temporality: temporality}, nil
+}
+
+// MetricCases returns the cases of data/metric_names.json.
+func MetricCases() ([]MetricCase, error) {
+	var cases []MetricCase
+	if err := load("data/metric_names.json", &cases); err != nil {
+		return nil, err
+	}
+	for _, c := range cases {
+		if _, err := c.Metric.Convert(); err != nil {
+			return nil, fmt.Errorf("data/metric_names.json: %q: %w", c.Description, err)
+		}
+	}
+	return cases, nil
+}
+
+// LabelCases returns the cases of data/label_names.json.
+func LabelCases() ([]LabelCase, error) {
+	var cases []LabelCase
+	return cases, load("data/label_names.json", &cases)
+}
+
+func load(name string, cases any) error {
+	b, err := Data.ReadFile(name)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(b, cases); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+// Run runs the suite against namer, with a subtest per case named after its
+// strategy and description.
+func Run(t *testing.T, namer Namer) {
+	t.Helper()
+	metricCases, err := MetricCases()
+	if err != nil {
+		t.Fatal(err)
+	}
+	labelCases, err := LabelCases()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("metric names", func(t *testing.T) {
+		for _, c := range metricCases {
+			t.Run(string(c.Strategy)+"/"+c.Description, func(t *testing.T) {
+				metric, _ := c.Metric.Convert()
+				got, err := namer.MetricName(metric, c.Namespace, c.Strategy)
+				check(t, fmt.Sprintf("MetricName(%+v, %q, %s)", c.Metric, c.Namespace, c.Strategy), got, err, c.Output, c.Error)
+			})
+		}
+	})
+	t.Run("label names", func(t *testing.T) {
+		for _, c := range labelCases {
+			t.Run(string(c.Strategy)+"/"+c.Description, func(t *testing.T) {
+				got, err := namer.LabelName(c.Attribute, c.Strategy)
+				check(t, fmt.Sprintf("LabelName(%q, %s)", c.Attribute, c.Strategy), got, err, c.Output, c.Error)
+			})
+		}
+	})
+}
+
+func check(t *testing.T, call, got string, err error, want, wantErr string) {
+	t.Helper()
+	switch {
+	case wantErr != "" && err == nil:
+		t.Errorf("%s = %q, want error %q", call, got, wantErr)
+	case wantErr == "" && err != nil:
+		t.Errorf("%s error = %v, want %q", call, err, want)
+	case wantErr == "" && got != want:
+		t.Errorf("%s = %q, want %q", call, got, want)
+	}
+}
@@ -0,0 +1,44 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conformance
+
+import "testing"
+
+func TestReference(t *testing.T) {
+	Run(t, Reference)
+}
+
+// TestReference_Errors checks that the errors recorded in the golden files
+// are the ones of this library, so that they stay up to date.
+func TestReference_Errors(t *testing.T) {
+	metricCases, err := MetricCases()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range metricCases {
+		metric, _ := c.Metric.Convert()
+		if _, err := Reference.MetricName(metric, c.Namespace, c.Strategy); err != nil && err.Error() != c.Error {
+			t.Errorf("%s/%s: error = %q, want %q", c.Strategy, c.Description, err, c.Error)
+		}
+	}
+	labelCases, err := LabelCases()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range labelCases {
+		if _, err := Reference.LabelName(c.Attribute, c.Strategy); err != nil && err.Error() != c.Error {
+			t.Errorf("%s/%s: error = %q, want %q", c.Strategy, c.Description, err, c.Error)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package conformance is a data-driven test suite checking that an
+// implementation translates OpenTelemetry metric and attribute names exactly
+// like this library, under every translation strategy.
+//
+// The cases are golden JSON files, in the data directory of this package, so
+// that implementations in other languages can read them directly. Every case
+// has a translation strategy, an input, an optional namespace, and either the
+// expected output or the error returned by this library. Go implementations
+// run the suite with Run:
+//
+//	func TestConformance(t *testing.T) {
+//		conformance.Run(t, myNamer{})
+//	}
+//
+// Error messages are not part of the specification: cases expecting an error
+// only check that one is returned.
+//
+// CheckMetricName and CheckLabelName check properties of translations beyond
+// the golden cases, such as validity under the strategy and stability when a
+// translated name is translated again, for use in fuzz targets.
+package conformance
@@ -36,3 +36,15 @@ const (
 	// https://github.com/OpenObservability/OpenMetrics/blob/1386544931307dff279688f332890c31b6c5de36/specification/OpenMetrics.md#supporting-target-metadata-in-both-push-based-and-pull-based-systems
 	TargetInfoMetricName = "target_info"
 )
+
+const (
+	// MetricNameLabel is the name of the label holding the metric name of a
+	// series.
+	MetricNameLabel = "__name__"
+	// BucketLabel is the name of the label holding the upper bound of a
+	// classic histogram bucket.
+	BucketLabel = "le"
+	// QuantileLabel is the name of the label holding the quantile of a summary
+	// sample.
+	QuantileLabel = "quantile"
+)
@@ -0,0 +1,304 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dashboards
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// valueKind is the kind of a JSON value.
+type valueKind int
+
+const (
+	otherValue valueKind = iota
+	stringValue
+	objectValue
+	arrayValue
+)
+
+// value is a JSON value, along with its position in the source.
+type value struct {
+	kind       valueKind
+	start, end int
+
+	// Strings.
+	str string
+	// offsets holds the source offset of every byte of the string, plus the
+	// offset of its closing quote, so that edits of the string can be applied
+	// to the source.
+	offsets []int
+
+	// Objects.
+	keys   []string
+	fields []*value
+
+	// Arrays.
+	items []*value
+}
+
+// get returns the value of an object field, or nil.
+func (v *value) get(key string) *value {
+	if v == nil || v.kind != objectValue {
+		return nil
+	}
+	for i, k := range v.keys {
+		if k == key {
+			return v.fields[i]
+		}
+	}
+	return nil
+}
+
+// string returns the value of a string, or an empty string.
+func (v *value) string() string {
+	if v == nil || v.kind != stringValue {
+		return ""
+	}
+	return v.str
+}
+
+// jsonParser parses JSON documents, keeping the position of their values.
+type jsonParser struct {
+	src []byte
+	pos int
+}
+
+// parseJSON parses a JSON document, and returns its root value.
+func parseJSON(src []byte) (*value, error) {
+	p := &jsonParser{src: src}
+	v, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	if p.skipSpaces(); p.pos < len(p.src) {
+		return nil, p.errorf("unexpected content after the document")
+	}
+	return v, nil
+}
+
+func (p *jsonParser) errorf(format string, args ...any) error {
+	line := bytes.Count(p.src[:min(p.pos, len(p.src))], []byte("\n")) + 1
+	return fmt.Errorf("line %d: %s", line, fmt.Sprintf(format, args...))
+}
+
+func (p *jsonParser) skipSpaces() {
+	for p.pos < len(p.src) {
+		switch p.src[p.pos] {
+		case ' ', '\t', '\n', '\r':
+			p.pos++
+		default:
+			return
+		}
+	}
+}
+
+// expect skips spaces and the given byte.
+func (p *jsonParser) expect(c byte) error {
+	p.skipSpaces()
+	if p.pos >= len(p.src) || p.src[p.pos] != c {
+		return p.errorf("expected %q", c)
+	}
+	p.pos++
+	return nil
+}
+
+func (p *jsonParser) parseValue() (*value, error) {
+	p.skipSpaces()
+	if p.pos >= len(p.src) {
+		return nil, p.errorf("unexpected end of input")
+	}
+	switch c := p.src[p.pos]; {
+	case c == '{':
+		return p.parseObject()
+	case c == '[':
+		return p.parseArray()
+	case c == '"':
+		return p.parseString()
+	case c == '-' || c >= '0' && c <= '9' || c == 't' || c == 'f' || c == 'n':
+		v := &value{kind: otherValue, start: p.pos}
+		for p.pos < len(p.src) && bytes.IndexByte([]byte(",]} \t\n\r"), p.src[p.pos]) < 0 {
+			p.pos++
+		}
+		v.end = p.pos
+		switch lit := string(p.src[v.start:v.end]); lit {
+		case "true", "false", "null":
+		default:
+			if _, err := strconv.ParseFloat(lit, 64); err != nil {
+				p.pos = v.start
+				return nil, p.errorf("invalid literal %q", lit)
+			}
+		}
+		return v, nil
+	default:
+		return nil, p.errorf("unexpected character %q", c)
+	}
+}
+
+func (p *jsonParser) parseObject() (*value, error) {
+	v := &value{kind: objectValue, start: p.pos}
+	p.pos++
+	if p.skipSpaces(); p.pos < len(p.src) && p.src[p.pos] == '}' {
+		p.pos++
+		v.end = p.pos
+		return v, nil
+	}
+	for {
+		p.skipSpaces()
+		if p.pos >= len(p.src) || p.src[p.pos] != '"' {
+			return nil, p.errorf("expected an object key")
+		}
+		key, err := p.parseString()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(':'); err != nil {
+			return nil, err
+		}
+		field, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		v.keys = append(v.keys, key.str)
+		v.fields = append(v.fields, field)
+		if p.skipSpaces(); p.pos < len(p.src) && p.src[p.pos] == '}' {
+			p.pos++
+			v.end = p.pos
+			return v, nil
+		}
+		if err := p.expect(','); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *jsonParser) parseArray() (*value, error) {
+	v := &value{kind: arrayValue, start: p.pos}
+	p.pos++
+	if p.skipSpaces(); p.pos < len(p.src) && p.src[p.pos] == ']' {
+		p.pos++
+		v.end = p.pos
+		return v, nil
+	}
+	for {
+		item, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		v.items = append(v.items, item)
+		if p.skipSpaces(); p.pos < len(p.src) && p.src[p.pos] == ']' {
+			p.pos++
+			v.end = p.pos
+			return v, nil
+		}
+		if err := p.expect(','); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// simpleEscapes maps the characters following a backslash to their value.
+var simpleEscapes = map[byte]byte{'"': '"', '\\': '\\', '/': '/', 'b': '\b', 'f': '\f', 'n': '\n', 'r': '\r', 't': '\t'}
+
+func (p *jsonParser) parseString() (*value, error) {
+	v := &value{kind: stringValue, start: p.pos}
+	var str []byte
+	add := func(s []byte, offset int) {
+		str = append(str, s...)
+		for range s {
+			v.offsets = append(v.offsets, offset)
+		}
+	}
+	for p.pos++; ; {
+		if p.pos >= len(p.src) {
+			return nil, p.errorf("unterminated string")
+		}
+		switch c := p.src[p.pos]; {
+		case c == '"':
+			v.offsets = append(v.offsets, p.pos)
+			p.pos++
+			v.end = p.pos
+			v.str = string(str)
+			return v, nil
+		case c < 0x20:
+			return nil, p.errorf("control character in string")
+		case c != '\\':
+			str = append(str, c)
+			v.offsets = append(v.offsets, p.pos)
+			p.pos++
+		case p.pos+1 < len(p.src) && simpleEscapes[p.src[p.pos+1]] != 0:
+			add([]byte{simpleEscapes[p.src[p.pos+1]]}, p.pos)
+			p.pos += 2
+		case p.pos+1 < len(p.src) && p.src[p.pos+1] == 'u':
+			start := p.pos
+			r, err := p.parseUnicodeEscape()
+			if err != nil {
+				return nil, err
+			}
+			if utf16.IsSurrogate(r) && p.pos+1 < len(p.src) && p.src[p.pos] == '\\' && p.src[p.pos+1] == 'u' {
+				save := p.pos
+				r2, err := p.parseUnicodeEscape()
+				if err != nil {
+					return nil, err
+				}
+				if r = utf16.DecodeRune(r, r2); r == utf8.RuneError {
+					p.pos = save
+				}
+			}
+			add(utf8.AppendRune(nil, r), start)
+		default:
+			return nil, p.errorf("invalid escape sequence")
+		}
+	}
+}
+
+// parseUnicodeEscape parses a \uXXXX escape sequence.
+func (p *jsonParser) parseUnicodeEscape() (rune, error) {
+	if p.pos+6 > len(p.src) {
+		return 0, p.errorf("invalid escape sequence")
+	}
+	r, err := strconv.ParseUint(string(p.src[p.pos+2:p.pos+6]), 16, 16)
+	if err != nil {
+		return 0, p.errorf("invalid escape sequence")
+	}
+	p.pos += 6
+	return rune(r), nil
+}
+
+// escapeJSON escapes s for a JSON string, the way encoding/json does but
+// without escaping HTML characters.
+func escapeJSON(s string) string {
+	var b bytes.Buffer
+	for _, r := range s {
+		switch {
+		case r == '"' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\r':
+			b.WriteString(`\r`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case r < 0x20 || r == '\u2028' || r == '\u2029':
+			fmt.Fprintf(&b, `\u%04x`, r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
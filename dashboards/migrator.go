@@ -0,0 +1,503 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dashboards
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/otlptranslator"
+	"github.com/prometheus/otlptranslator/internal/diff"
+	"github.com/prometheus/otlptranslator/internal/legacy"
+	"github.com/prometheus/otlptranslator/internal/migrate"
+	"github.com/prometheus/otlptranslator/promql"
+)
+
+// MigratorOptions configures a Migrator.
+type MigratorOptions struct {
+	// From is the translation strategy the dashboards were written for.
+	From otlptranslator.TranslationStrategyOption
+	// To is the translation strategy the dashboards are migrated to.
+	To otlptranslator.TranslationStrategyOption
+	// Namespace is the namespace of metric names, under both strategies.
+	Namespace string
+	// Metrics are the known OpenTelemetry metrics. Their names are mapped
+	// exactly, while the names of other metrics are reverse translated.
+	Metrics []otlptranslator.Metric
+	// Attributes are the known OpenTelemetry attribute names. Their labels
+	// are mapped exactly, while other labels are reverse translated.
+	Attributes []string
+}
+
+// Ambiguity is a name, or a query, that a Migrator could not map with
+// certainty, and that should be reviewed.
+type Ambiguity struct {
+	// Path is the path of the string holding the name in the dashboard, such
+	// as panels[2].targets[0].expr.
+	Path string
+	// Panel is the title of the panel, if any.
+	Panel string
+	// Name is the metric or label name, or the selector, that is ambiguous.
+	Name string
+	// Candidates are the possible new names, if known.
+	Candidates []string
+	// Reason explains why the name is ambiguous.
+	Reason string
+}
+
+// String returns the path, name and reason of the ambiguity.
+func (a Ambiguity) String() string {
+	var b strings.Builder
+	b.WriteString(a.Path)
+	if a.Panel != "" {
+		fmt.Fprintf(&b, ", panel %q", a.Panel)
+	}
+	if a.Name != "" {
+		fmt.Fprintf(&b, ": %s", a.Name)
+	}
+	fmt.Fprintf(&b, ": %s", a.Reason)
+	if len(a.Candidates) > 0 {
+		fmt.Fprintf(&b, " (candidates: %s)", strings.Join(a.Candidates, ", "))
+	}
+	return b.String()
+}
+
+// Result is the result of the migration of a dashboard.
+type Result struct {
+	// Content is the migrated dashboard.
+	Content []byte
+	// Diff is the unified diff of the migration, empty if nothing changed.
+	Diff string
+	// Ambiguities are the cases that should be reviewed, in the order of the
+	// dashboard.
+	Ambiguities []Ambiguity
+}
+
+// Migrator migrates Grafana dashboards from a translation strategy to
+// another.
+//
+// Metric and label names are mapped as by the rules package: exactly for
+// known metrics and attributes, and by reverse translation otherwise. The
+// Migrator rewrites, for Prometheus data sources:
+//   - The expressions of panel targets, including the panels of rows, and of
+//     annotations.
+//   - The labels referenced by the legend formats of targets, and by the
+//     title and text formats and tag keys of annotations.
+//   - The queries of query variables, be they PromQL or label_values,
+//     label_names and query_result functions, and the keys of the filters of
+//     ad hoc variables.
+//
+// Grafana variables, such as $job, ${job} or [[job]], are kept in queries.
+// Labels of label_values, tag keys and ad hoc filters are not renamed to
+// names needing quotes, which Grafana does not add there: they are reported
+// as ambiguities instead. Targets are only skipped if their data source, or the one of their panel,
+// has a type other than prometheus: data sources given by name are assumed
+// to be Prometheus ones.
+//
+// Only the strings holding migrated names are edited: the rest of the JSON
+// is preserved byte for byte. Dashboards can also be wrapped in a dashboard
+// field, as returned by the Grafana HTTP API.
+//
+// Example usage:
+//
+//	m, err := dashboards.NewMigrator(dashboards.MigratorOptions{
+//		From:       otlptranslator.UnderscoreEscapingWithSuffixes,
+//		To:         otlptranslator.NoUTF8EscapingWithSuffixes,
+//		Attributes: []string{"http.route"},
+//	})
+//	if err != nil {
+//		// handle err
+//	}
+//	res, err := m.Migrate("http.json", content)
+type Migrator struct {
+	mapper *migrate.Mapper
+}
+
+// NewMigrator creates a Migrator. It returns an error if a strategy is
+// unknown, or if a known metric or attribute cannot be translated.
+func NewMigrator(opts MigratorOptions) (*Migrator, error) {
+	mapper, err := migrate.NewMapper(migrate.Options(opts))
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{mapper: mapper}, nil
+}
+
+// Migrate migrates the content of a dashboard. The file name is only used
+// in the diff and in errors. It returns an error if the content is not a
+// JSON object.
+func (m *Migrator) Migrate(filename string, content []byte) (Result, error) {
+	root, err := parseJSON(content)
+	if err != nil {
+		return Result{}, fmt.Errorf("%s: %w", filename, err)
+	}
+	if root.kind != objectValue {
+		return Result{}, fmt.Errorf("%s: the dashboard must be a JSON object", filename)
+	}
+	mg := &migration{m: m}
+	if d := root.get("dashboard"); d != nil && d.kind == objectValue && root.get("panels") == nil {
+		mg.migrateDashboard(d, "dashboard.")
+	} else {
+		mg.migrateDashboard(root, "")
+	}
+
+	slices.SortFunc(mg.edits, func(a, b diff.Edit) int { return a.Start - b.Start })
+	var out []byte
+	last := 0
+	for _, e := range mg.edits {
+		out = append(out, content[last:e.Start]...)
+		out = append(out, e.Text...)
+		last = e.End
+	}
+	out = append(out, content[last:]...)
+	return Result{
+		Content:     out,
+		Diff:        diff.Unified("a/"+filename, "b/"+filename, string(content), string(out)),
+		Ambiguities: mg.ambiguities,
+	}, nil
+}
+
+// migration is the migration of a dashboard. It translates the names of the
+// query being migrated as a promql.Translator, reporting ambiguities instead
+// of returning errors.
+type migration struct {
+	m           *Migrator
+	edits       []diff.Edit
+	ambiguities []Ambiguity
+	// path and panel locate the names being translated.
+	path, panel string
+}
+
+// ambiguous reports an ambiguous name at the current path.
+func (mg *migration) ambiguous(name string, candidates []string, reason string) {
+	a := Ambiguity{Path: mg.path, Panel: mg.panel, Name: name, Candidates: candidates, Reason: reason}
+	if !slices.ContainsFunc(mg.ambiguities, func(b Ambiguity) bool {
+		return a.Path == b.Path && a.Name == b.Name && a.Reason == b.Reason
+	}) {
+		mg.ambiguities = append(mg.ambiguities, a)
+	}
+}
+
+// MetricName implements promql.Translator.
+func (mg *migration) MetricName(name string) (string, error) {
+	if strings.HasPrefix(name, variablePrefix) {
+		return name, nil
+	}
+	newName, a := mg.m.mapper.MetricName(name)
+	if a != nil {
+		mg.ambiguous(name, a.Candidates, a.Reason)
+	}
+	return newName, nil
+}
+
+// LabelName implements promql.Translator.
+func (mg *migration) LabelName(name string) (string, error) {
+	if strings.HasPrefix(name, "__") {
+		return name, nil
+	}
+	newName, a := mg.m.mapper.LabelName(name)
+	if a != nil {
+		mg.ambiguous(name, a.Candidates, a.Reason)
+	}
+	return newName, nil
+}
+
+// migrateDashboard migrates the fields of a dashboard, in the order of the
+// JSON document.
+func (mg *migration) migrateDashboard(d *value, prefix string) {
+	for i, key := range d.keys {
+		field := d.fields[i]
+		mg.panel = ""
+		switch key {
+		case "panels":
+			mg.migratePanels(field, prefix+"panels", nil)
+		case "rows":
+			// Rows of dashboards older than Grafana 5.
+			for j, row := range field.items {
+				mg.migratePanels(row.get("panels"), fmt.Sprintf("%srows[%d].panels", prefix, j), nil)
+			}
+		case "templating":
+			if list := field.get("list"); list != nil {
+				for j, v := range list.items {
+					mg.migrateVariable(v, fmt.Sprintf("%stemplating.list[%d]", prefix, j))
+				}
+			}
+		case "annotations":
+			if list := field.get("list"); list != nil {
+				for j, a := range list.items {
+					mg.migrateAnnotation(a, fmt.Sprintf("%sannotations.list[%d]", prefix, j))
+				}
+			}
+		}
+	}
+}
+
+// migratePanels migrates the targets of panels, and of the panels of rows.
+func (mg *migration) migratePanels(panels *value, path string, rowDatasource *value) {
+	if panels == nil {
+		return
+	}
+	for i, panel := range panels.items {
+		panelPath := fmt.Sprintf("%s[%d]", path, i)
+		datasource := panel.get("datasource")
+		if datasource == nil {
+			datasource = rowDatasource
+		}
+		mg.panel = panel.get("title").string()
+		if targets := panel.get("targets"); targets != nil {
+			for j, target := range targets.items {
+				if !isPrometheus(target.get("datasource"), datasource) {
+					continue
+				}
+				targetPath := fmt.Sprintf("%s.targets[%d]", panelPath, j)
+				mg.migrateQuery(target.get("expr"), targetPath+".expr")
+				mg.migrateFormat(target.get("legendFormat"), targetPath+".legendFormat")
+			}
+		}
+		mg.migratePanels(panel.get("panels"), panelPath+".panels", datasource)
+	}
+}
+
+// isPrometheus reports whether the first set data source is a Prometheus
+// one, or is given by name.
+func isPrometheus(datasources ...*value) bool {
+	for _, ds := range datasources {
+		switch {
+		case ds == nil || ds.kind == otherValue:
+			continue
+		case ds.kind == stringValue:
+			return !strings.HasPrefix(ds.str, "-- ")
+		case ds.kind == objectValue:
+			t := ds.get("type")
+			return t == nil || t.string() == "prometheus"
+		}
+	}
+	return true
+}
+
+// migrateVariable migrates the query of a query variable, or the filter keys
+// of an ad hoc variable.
+func (mg *migration) migrateVariable(v *value, path string) {
+	if !isPrometheus(v.get("datasource")) {
+		return
+	}
+	switch v.get("type").string() {
+	case "query":
+		query := v.get("query")
+		if query.kind == objectValue {
+			mg.migrateVariableQuery(query.get("query"), path+".query.query")
+		} else {
+			mg.migrateVariableQuery(query, path+".query")
+		}
+		mg.migrateVariableQuery(v.get("definition"), path+".definition")
+	case "adhoc":
+		if filters := v.get("filters"); filters != nil {
+			for i, f := range filters.items {
+				mg.migrateLabelList(f.get("key"), fmt.Sprintf("%s.filters[%d].key", path, i))
+			}
+		}
+	}
+}
+
+// migrateAnnotation migrates the query and the formats of an annotation.
+func (mg *migration) migrateAnnotation(a *value, path string) {
+	if !isPrometheus(a.get("datasource")) {
+		return
+	}
+	mg.migrateQuery(a.get("expr"), path+".expr")
+	mg.migrateFormat(a.get("titleFormat"), path+".titleFormat")
+	mg.migrateFormat(a.get("textFormat"), path+".textFormat")
+	mg.migrateLabelList(a.get("tagKeys"), path+".tagKeys")
+}
+
+// variablePrefix starts the placeholders standing for Grafana variables in
+// queries, which the Rewriter keeps as reserved names.
+const variablePrefix = "__grafana_variable_"
+
+// variableRef matches the references to Grafana variables.
+var variableRef = regexp.MustCompile(`\$\w+|\$\{[^}]*\}|\[\[[^\]]*\]\]`)
+
+// rewrite rewrites a PromQL query holding Grafana variables.
+func (mg *migration) rewrite(query string) string {
+	var variables []string
+	query = variableRef.ReplaceAllStringFunc(query, func(ref string) string {
+		variables = append(variables, ref)
+		return variablePrefix + strconv.Itoa(len(variables)-1) + "__"
+	})
+	rewritten, unresolved, err := promql.NewTranslatorRewriter(mg).Rewrite(query)
+	if err != nil {
+		mg.ambiguous("", nil, fmt.Sprintf("the query cannot be migrated: %v", err))
+		return ""
+	}
+	restore := func(s string) string {
+		for i := len(variables) - 1; i >= 0; i-- {
+			s = strings.ReplaceAll(s, variablePrefix+strconv.Itoa(i)+"__", variables[i])
+		}
+		return s
+	}
+	for _, u := range unresolved {
+		mg.ambiguous(restore(u.Selector), nil, u.Reason)
+	}
+	return restore(rewritten)
+}
+
+// migrateQuery migrates a PromQL query.
+func (mg *migration) migrateQuery(v *value, path string) {
+	if v == nil || v.kind != stringValue || strings.TrimSpace(v.str) == "" {
+		return
+	}
+	mg.path = path
+	if query := mg.rewrite(v.str); query != "" {
+		mg.replace(v, query)
+	}
+}
+
+// variableQuery matches the functions of query variables.
+var variableQuery = regexp.MustCompile(`^(\s*)(label_values|label_names|query_result|metrics)\s*\(([\s\S]*)\)(\s*)$`)
+
+// migrateVariableQuery migrates the query of a query variable.
+func (mg *migration) migrateVariableQuery(v *value, path string) {
+	if v == nil || v.kind != stringValue || strings.TrimSpace(v.str) == "" {
+		return
+	}
+	mg.path = path
+	match := variableQuery.FindStringSubmatchIndex(v.str)
+	if match == nil {
+		mg.migrateQuery(v, path)
+		return
+	}
+	fn, args := v.str[match[4]:match[5]], v.str[match[6]:match[7]]
+	var migrated string
+	switch fn {
+	case "metrics":
+		mg.ambiguous(args, nil, "metric name regular expressions cannot be migrated")
+		return
+	case "label_values":
+		// The label is the last argument, after an optional series selector.
+		selector, label := "", args
+		if i := lastTopLevelComma(args); i >= 0 {
+			selector, label = args[:i+1], args[i+1:]
+		}
+		if strings.TrimSpace(selector) != "" {
+			query := mg.rewrite(selector[:len(selector)-1])
+			if query == "" {
+				return
+			}
+			selector = query + ","
+		}
+		migrated = selector + mg.labelInQuery(label)
+	default:
+		if strings.TrimSpace(args) != "" {
+			if args = mg.rewrite(args); args == "" {
+				return
+			}
+		}
+		migrated = args
+	}
+	mg.replace(v, v.str[:match[6]]+migrated+v.str[match[7]:])
+}
+
+// lastTopLevelComma returns the index of the last comma of s outside of
+// parentheses, braces, brackets and strings, or -1.
+func lastTopLevelComma(s string) int {
+	last, depth := -1, 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'' || c == '`':
+			quote = c
+		case c == '(' || c == '{' || c == '[':
+			depth++
+		case c == ')' || c == '}' || c == ']':
+			depth--
+		case c == ',' && depth == 0:
+			last = i
+		}
+	}
+	return last
+}
+
+// labelInText migrates a label name surrounded by spaces, unless it is a
+// Grafana variable.
+func (mg *migration) labelInText(s string) string {
+	name := strings.TrimSpace(s)
+	if name == "" || variableRef.MatchString(name) {
+		return s
+	}
+	newName, _ := mg.LabelName(name)
+	return strings.Replace(s, name, newName, 1)
+}
+
+// labelInQuery migrates a label name surrounded by spaces, like labelInText,
+// where Grafana passes it to Prometheus unquoted: in label_values queries and
+// in label lists. Labels that would need quotes once migrated are reported,
+// and left unchanged.
+func (mg *migration) labelInQuery(s string) string {
+	name, migrated := strings.TrimSpace(s), mg.labelInText(s)
+	if newName := strings.TrimSpace(migrated); newName != name && !legacy.IsLabelName(newName) {
+		mg.ambiguous(name, []string{newName}, "Grafana does not quote label names here, so the label cannot be renamed to a name that needs quotes")
+		return s
+	}
+	return migrated
+}
+
+// legendLabel matches the label references of legend formats.
+var legendLabel = regexp.MustCompile(`\{\{\s*(.+?)\s*\}\}`)
+
+// migrateFormat migrates the label references of a legend format, such as
+// "{{http_route}} {{instance}}".
+func (mg *migration) migrateFormat(v *value, path string) {
+	if v == nil || v.kind != stringValue {
+		return
+	}
+	mg.path = path
+	format := legendLabel.ReplaceAllStringFunc(v.str, func(ref string) string {
+		m := legendLabel.FindStringSubmatchIndex(ref)
+		return ref[:m[2]] + mg.labelInText(ref[m[2]:m[3]]) + ref[m[3]:]
+	})
+	mg.replace(v, format)
+}
+
+// migrateLabelList migrates a comma-separated list of label names.
+func (mg *migration) migrateLabelList(v *value, path string) {
+	if v == nil || v.kind != stringValue {
+		return
+	}
+	mg.path = path
+	labels := strings.Split(v.str, ",")
+	for i, l := range labels {
+		labels[i] = mg.labelInQuery(l)
+	}
+	mg.replace(v, strings.Join(labels, ","))
+}
+
+// replace records the edits replacing the value of a string.
+func (mg *migration) replace(v *value, s string) {
+	if s == v.str {
+		return
+	}
+	for _, e := range diff.Words(v.str, s) {
+		mg.edits = append(mg.edits, diff.Edit{Start: v.offsets[e.Start], End: v.offsets[e.End], Text: escapeJSON(e.Text)})
+	}
+}
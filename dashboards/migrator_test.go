@@ -0,0 +1,202 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dashboards
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/otlptranslator"
+)
+
+// dashboard is a Grafana dashboard with a row, query and ad hoc variables,
+// an annotation and a Loki target.
+const dashboard = `{
+  "annotations": {
+    "list": [
+      {
+        "datasource": {"type": "prometheus", "uid": "prom"},
+        "expr": "changes(process_start_time_seconds[5m]) > 0",
+        "titleFormat": "Restart of {{service_name}}",
+        "tagKeys": "service_name,instance"
+      }
+    ]
+  },
+  "panels": [
+    {
+      "type": "row",
+      "title": "HTTP",
+      "panels": [
+        {
+          "title": "Latency",
+          "datasource": {"type": "prometheus", "uid": "$datasource"},
+          "targets": [
+            {
+              "expr": "histogram_quantile(0.9, sum by (le, http_route) (rate(http_server_request_duration_seconds_bucket{job=~\"$job\"}[$__rate_interval])))",
+              "legendFormat": "{{ http_route }}",
+              "refId": "A"
+            }
+          ]
+        }
+      ]
+    },
+    {
+      "title": "Logs",
+      "datasource": {"type": "loki", "uid": "loki"},
+      "targets": [{"expr": "{service_name=\"api\"} |= \"error\"", "refId": "A"}]
+    }
+  ],
+  "templating": {
+    "list": [
+      {
+        "name": "route",
+        "type": "query",
+        "datasource": {"type": "prometheus", "uid": "prom"},
+        "definition": "label_values(http_server_request_duration_seconds_count{job=\"$job\"}, http_route)",
+        "query": {"query": "label_values(http_server_request_duration_seconds_count{job=\"$job\"}, http_route)", "refId": "Var"}
+      },
+      {"name": "filters", "type": "adhoc", "filters": [{"key": "http_route", "operator": "=", "value": "/"}]}
+    ]
+  },
+  "title": "HTTP é server"
+}
+`
+
+func TestMigrator(t *testing.T) {
+	m, err := NewMigrator(MigratorOptions{
+		From:       otlptranslator.UnderscoreEscapingWithSuffixes,
+		To:         otlptranslator.NoUTF8EscapingWithSuffixes,
+		Metrics:    []otlptranslator.Metric{{Name: "http.server.request.duration", Unit: "s", Type: otlptranslator.MetricTypeHistogram}},
+		Attributes: []string{"http.route", "service.name"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := m.Migrate("http.json", []byte(dashboard))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := strings.NewReplacer(
+		`(le, http_route) (rate(http_server_request_duration_seconds_bucket{job=~\"$job\"}`,
+		`(le, \"http.route\") (rate({\"http.server.request.duration_seconds_bucket\", job=~\"$job\"}`,
+		`"{{ http_route }}"`, `"{{ http.route }}"`,
+		`{{service_name}}`, `{{service.name}}`,
+		`label_values(http_server_request_duration_seconds_count{job=\"$job\"}, http_route)`,
+		`label_values({\"http.server.request.duration_seconds_count\", job=\"$job\"}, http_route)`,
+	).Replace(dashboard)
+	if string(res.Content) != want {
+		t.Errorf("Migrate() content =\n%s\nwant\n%s\ndiff:\n%s", res.Content, want, res.Diff)
+	}
+	var ambiguities []string
+	for _, a := range res.Ambiguities {
+		ambiguities = append(ambiguities, a.String())
+	}
+	wantAmbiguities := []string{
+		"annotations.list[0].expr: process_start_time_seconds: the metric is unknown, and underscores of its name may stand for escaped characters (candidates: process_start_time_seconds)",
+		"annotations.list[0].tagKeys: service_name: Grafana does not quote label names here, so the label cannot be renamed to a name that needs quotes (candidates: service.name)",
+		"templating.list[0].query.query: http_route: Grafana does not quote label names here, so the label cannot be renamed to a name that needs quotes (candidates: http.route)",
+		"templating.list[0].definition: http_route: Grafana does not quote label names here, so the label cannot be renamed to a name that needs quotes (candidates: http.route)",
+		"templating.list[1].filters[0].key: http_route: Grafana does not quote label names here, so the label cannot be renamed to a name that needs quotes (candidates: http.route)",
+	}
+	if !reflect.DeepEqual(ambiguities, wantAmbiguities) {
+		t.Errorf("Migrate() ambiguities =\n%s\nwant\n%s", strings.Join(ambiguities, "\n"), strings.Join(wantAmbiguities, "\n"))
+	}
+}
+
+func TestMigrator_Variables(t *testing.T) {
+	m, err := NewMigrator(MigratorOptions{
+		From: otlptranslator.UnderscoreEscapingWithSuffixes,
+		To:   otlptranslator.UnderscoreEscapingWithoutSuffixes,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		name        string
+		in          string
+		want        string
+		ambiguities []string
+	}{
+		{
+			name: "label values without selector",
+			in:   `{"templating":{"list":[{"type":"query","query":"label_values(job)"}]}}`,
+			want: `{"templating":{"list":[{"type":"query","query":"label_values(job)"}]}}`,
+		},
+		{
+			name: "query result with variables",
+			in:   `{"templating":{"list":[{"type":"query","query":"query_result(topk(5, sum by ($by) (rate(requests_total[${range}]))))"}]}}`,
+			want: `{"templating":{"list":[{"type":"query","query":"query_result(topk(5, sum by ($by) (rate(requests[${range}]))))"}]}}`,
+		},
+		{
+			name: "PromQL query with escapes",
+			in:   `{"templating":{"list":[{"type":"query","query":"sum(errors_total{path=\"/api\"})"}]}}`,
+			want: `{"templating":{"list":[{"type":"query","query":"sum(errors{path=\"/api\"})"}]}}`,
+		},
+		{
+			name:        "metric regexes",
+			in:          `{"templating":{"list":[{"type":"query","query":"metrics(http_.*_total)"}]}}`,
+			want:        `{"templating":{"list":[{"type":"query","query":"metrics(http_.*_total)"}]}}`,
+			ambiguities: []string{"templating.list[0].query: http_.*_total: metric name regular expressions cannot be migrated"},
+		},
+		{
+			name: "wrapped dashboard",
+			in:   `{"dashboard":{"panels":[{"title":"Errors","targets":[{"expr":"rate(errors_total[5m])"}]}]},"meta":{"expr":"errors_total"}}`,
+			want: `{"dashboard":{"panels":[{"title":"Errors","targets":[{"expr":"rate(errors[5m])"}]}]},"meta":{"expr":"errors_total"}}`,
+		},
+		{
+			name:        "unparsable query",
+			in:          `{"panels":[{"title":"Errors","targets":[{"expr":"rate(errors_total{job=\"a)"}]}]}`,
+			want:        `{"panels":[{"title":"Errors","targets":[{"expr":"rate(errors_total{job=\"a)"}]}]}`,
+			ambiguities: []string{`panels[0].targets[0].expr, panel "Errors": the query cannot be migrated: promql: position 22: unterminated string`},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := m.Migrate("dashboard.json", []byte(tc.in))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(res.Content) != tc.want {
+				t.Errorf("Migrate() content =\n%s\nwant\n%s", res.Content, tc.want)
+			}
+			var ambiguities []string
+			for _, a := range res.Ambiguities {
+				ambiguities = append(ambiguities, a.String())
+			}
+			if !reflect.DeepEqual(ambiguities, tc.ambiguities) {
+				t.Errorf("Migrate() ambiguities =\n%s\nwant\n%s", strings.Join(ambiguities, "\n"), strings.Join(tc.ambiguities, "\n"))
+			}
+		})
+	}
+}
+
+func TestMigrator_Errors(t *testing.T) {
+	m, err := NewMigrator(MigratorOptions{From: otlptranslator.UnderscoreEscapingWithSuffixes, To: otlptranslator.NoTranslation})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		in  string
+		err string
+	}{
+		{in: `[]`, err: "dashboard.json: the dashboard must be a JSON object"},
+		{in: "{\n\"panels\": [}", err: "dashboard.json: line 2: unexpected character '}'"},
+		{in: `{"title": "a`, err: "dashboard.json: line 1: unterminated string"},
+		{in: `{"title": "a"} {}`, err: "dashboard.json: line 1: unexpected content after the document"},
+	} {
+		if _, err := m.Migrate("dashboard.json", []byte(tc.in)); err == nil || err.Error() != tc.err {
+			t.Errorf("Migrate(%s) error = %v, want %s", tc.in, err, tc.err)
+		}
+	}
+}
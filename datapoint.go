@@ -0,0 +1,116 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlptranslator
+
+// Attribute is a key-value pair attached to an OTLP resource, scope, data
+// point or exemplar.
+//
+// Values are kept in their string representation. Non-string OTLP values are
+// expected to be converted by the caller, following the OpenTelemetry to
+// Prometheus compatibility specification:
+// https://github.com/open-telemetry/opentelemetry-specification/blob/v1.38.0/specification/compatibility/prometheus_and_openmetrics.md#metric-attributes
+type Attribute struct {
+	Key   string
+	Value string
+}
+
+// DataPointFlags is a bit field of flags attached to OTLP data points.
+type DataPointFlags uint32
+
+// DataPointFlagNoRecordedValue marks a data point that carries no value, for
+// instance because the series it belongs to stopped being reported.
+const DataPointFlagNoRecordedValue DataPointFlags = 1
+
+// NoRecordedValue reports whether the DataPointFlagNoRecordedValue flag is
+// set. Such data points are translated to Prometheus staleness markers.
+func (f DataPointFlags) NoRecordedValue() bool {
+	return f&DataPointFlagNoRecordedValue != 0
+}
+
+// Exemplar is an OTLP exemplar: a single measurement attached to a data
+// point, optionally linked to the trace and span that recorded it.
+type Exemplar struct {
+	FilteredAttributes []Attribute
+	TimeUnixNano       uint64
+	Value              float64
+	TraceID            [16]byte
+	SpanID             [8]byte
+}
+
+// NumberDataPoint is a single OTLP gauge or sum data point. Integer values
+// are expected to be converted to float64 by the caller.
+type NumberDataPoint struct {
+	Attributes        []Attribute
+	StartTimeUnixNano uint64
+	TimeUnixNano      uint64
+	Value             float64
+	Exemplars         []Exemplar
+	Flags             DataPointFlags
+}
+
+// HistogramDataPoint is a single OTLP explicit bucket histogram data point.
+// BucketCounts has one more element than ExplicitBounds, the last one being
+// the overflow bucket.
+type HistogramDataPoint struct {
+	Attributes        []Attribute
+	StartTimeUnixNano uint64
+	TimeUnixNano      uint64
+	Count             uint64
+	Sum               float64
+	BucketCounts      []uint64
+	ExplicitBounds    []float64
+	Exemplars         []Exemplar
+	Flags             DataPointFlags
+}
+
+// ExponentialHistogramBuckets is a dense range of exponential histogram
+// buckets. BucketCounts[i] is the count of the bucket with index Offset+i.
+type ExponentialHistogramBuckets struct {
+	Offset       int32
+	BucketCounts []uint64
+}
+
+// ExponentialHistogramDataPoint is a single OTLP exponential histogram data
+// point.
+type ExponentialHistogramDataPoint struct {
+	Attributes        []Attribute
+	StartTimeUnixNano uint64
+	TimeUnixNano      uint64
+	Count             uint64
+	Sum               float64
+	Scale             int32
+	ZeroCount         uint64
+	ZeroThreshold     float64
+	Positive          ExponentialHistogramBuckets
+	Negative          ExponentialHistogramBuckets
+	Exemplars         []Exemplar
+	Flags             DataPointFlags
+}
+
+// ValueAtQuantile is a single quantile of an OTLP summary data point.
+type ValueAtQuantile struct {
+	Quantile float64
+	Value    float64
+}
+
+// SummaryDataPoint is a single OTLP summary data point.
+type SummaryDataPoint struct {
+	Attributes        []Attribute
+	StartTimeUnixNano uint64
+	TimeUnixNano      uint64
+	Count             uint64
+	Sum               float64
+	QuantileValues    []ValueAtQuantile
+	Flags             DataPointFlags
+}
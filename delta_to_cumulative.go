@@ -0,0 +1,417 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlptranslator
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrOutOfOrder is returned when a delta data point does not end after the
+	// last data point accumulated for the same series.
+	ErrOutOfOrder = errors.New("out of order delta data point")
+	// ErrOverlappingDelta is returned when a delta data point starts within the
+	// interval already accumulated for the same series.
+	ErrOverlappingDelta = errors.New("delta data point overlaps accumulated interval")
+	// ErrSeriesLimit is returned when a new series would exceed the maximum
+	// number of series tracked by a DeltaAccumulator.
+	ErrSeriesLimit = errors.New("delta accumulator series limit reached")
+)
+
+// minExponentialHistogramScale is the lowest scale allowed by OTLP.
+const minExponentialHistogramScale = -10
+
+// GapPolicy defines how a DeltaAccumulator handles a delta data point that
+// starts after the end of the previously accumulated one, meaning that the
+// deltas for the interval in between were lost.
+type GapPolicy int
+
+const (
+	// GapPolicyReset starts a new cumulative series at the start of the data
+	// point following the gap. Prometheus sees a counter reset, so rate() and
+	// increase() never count the lost deltas as zero.
+	GapPolicyReset GapPolicy = iota
+	// GapPolicyIgnore keeps accumulating across the gap, as if the missing
+	// deltas were all zero.
+	GapPolicyIgnore
+)
+
+// DeltaAccumulatorOptions configures a DeltaAccumulator.
+type DeltaAccumulatorOptions struct {
+	// TTL is the time after which a series that received no data point is
+	// forgotten by Expire. Zero disables expiry.
+	TTL time.Duration
+	// MaxSeries is the maximum number of series tracked at once. Zero means no
+	// limit.
+	MaxSeries int
+	// MaxExponentialHistogramBuckets is the maximum number of positive or
+	// negative buckets kept for an accumulated exponential histogram. When
+	// merging would exceed it, the histogram is downscaled until it fits. Zero
+	// means no limit.
+	MaxExponentialHistogramBuckets int
+	// GapPolicy defines how gaps between consecutive deltas are handled.
+	GapPolicy GapPolicy
+	// Now returns the current time. It defaults to time.Now.
+	Now func() time.Time
+}
+
+// DeltaAccumulator converts OTLP delta data points into cumulative ones, as
+// Prometheus only understands cumulative counters and histograms.
+//
+// Series are identified by their translated identity: the metric name built
+// by the MetricNamer and the label set built by the LabelNamer from the data
+// point attributes, merged with any extra labels passed by the caller. Two
+// OTLP streams translating to the same Prometheus series are therefore
+// accumulated together.
+//
+// For every series, the accumulator tracks the start of the cumulative
+// interval and the end of the last accepted delta. An incoming delta is
+// handled as follows:
+//   - If it does not end after the last accepted delta, it is rejected with ErrOutOfOrder.
+//   - If it starts before the start of the cumulative interval, the producer
+//     was reset: a new cumulative interval begins at its start time.
+//   - If it starts within the accumulated interval, it is rejected with ErrOverlappingDelta.
+//   - If it starts after the end of the last accepted delta, the gap is handled according to GapPolicy.
+//
+// A data point flagged with DataPointFlagNoRecordedValue ends the series: it
+// is returned unchanged, to be translated to a staleness marker, and the
+// series state is forgotten so that the next data point starts a new
+// cumulative interval.
+//
+// A histogram whose bucket boundaries change, or an exponential histogram
+// whose zero threshold changes, also begins a new cumulative interval.
+// Exponential histograms with different scales are merged at the lowest
+// scale.
+//
+// The returned data points are cumulative, so the metric they belong to must
+// be translated with its Temporality set to TemporalityCumulative.
+//
+// A DeltaAccumulator is safe for concurrent use.
+//
+// Example usage:
+//
+//	acc := NewDeltaAccumulator(
+//		NewMetricNamer("", UnderscoreEscapingWithSuffixes),
+//		LabelNamer{},
+//		DeltaAccumulatorOptions{TTL: 5 * time.Minute},
+//	)
+//	metric := Metric{Name: "http.requests", Type: MetricTypeMonotonicCounter, Temporality: TemporalityDelta}
+//	cumulative, err := acc.AddSum(metric, nil, deltaPoint)
+type DeltaAccumulator struct {
+	metricNamer MetricNamer
+	labelNamer  LabelNamer
+	opts        DeltaAccumulatorOptions
+
+	mtx    sync.Mutex
+	series map[string]*deltaSeries
+}
+
+type deltaSeries struct {
+	start    uint64
+	last     uint64
+	lastSeen time.Time
+
+	value   float64
+	hist    HistogramDataPoint
+	expHist ExponentialHistogramDataPoint
+}
+
+// NewDeltaAccumulator creates a DeltaAccumulator translating series
+// identities with the given namers.
+func NewDeltaAccumulator(metricNamer MetricNamer, labelNamer LabelNamer, opts DeltaAccumulatorOptions) *DeltaAccumulator {
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+	return &DeltaAccumulator{
+		metricNamer: metricNamer,
+		labelNamer:  labelNamer,
+		opts:        opts,
+		series:      map[string]*deltaSeries{},
+	}
+}
+
+// AddSum accumulates a delta sum data point and returns the resulting
+// cumulative data point. extra holds labels, such as the ones built from
+// resource attributes, that are part of the series identity in addition to
+// the data point attributes.
+func (a *DeltaAccumulator) AddSum(metric Metric, extra Labels, p NumberDataPoint) (NumberDataPoint, error) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	if p.Flags.NoRecordedValue() {
+		return p, a.forget(metric, extra, p.Attributes)
+	}
+	s, fresh, err := a.prepare(metric, extra, p.Attributes, p.StartTimeUnixNano, p.TimeUnixNano)
+	if err != nil {
+		return NumberDataPoint{}, err
+	}
+	if fresh {
+		s.value = 0
+	}
+	s.value += p.Value
+
+	p.StartTimeUnixNano = s.start
+	p.Value = s.value
+	return p, nil
+}
+
+// AddHistogram accumulates a delta explicit bucket histogram data point and
+// returns the resulting cumulative data point. See AddSum for the meaning of
+// extra.
+func (a *DeltaAccumulator) AddHistogram(metric Metric, extra Labels, p HistogramDataPoint) (HistogramDataPoint, error) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	if p.Flags.NoRecordedValue() {
+		return p, a.forget(metric, extra, p.Attributes)
+	}
+	s, fresh, err := a.prepare(metric, extra, p.Attributes, p.StartTimeUnixNano, p.TimeUnixNano)
+	if err != nil {
+		return HistogramDataPoint{}, err
+	}
+	if !fresh && (!slices.Equal(s.hist.ExplicitBounds, p.ExplicitBounds) || len(s.hist.BucketCounts) != len(p.BucketCounts)) {
+		s.start = startOf(p.StartTimeUnixNano, p.TimeUnixNano)
+		fresh = true
+	}
+	if fresh {
+		s.hist = HistogramDataPoint{
+			ExplicitBounds: slices.Clone(p.ExplicitBounds),
+			BucketCounts:   make([]uint64, len(p.BucketCounts)),
+		}
+	}
+	s.hist.Count += p.Count
+	s.hist.Sum += p.Sum
+	for i, c := range p.BucketCounts {
+		s.hist.BucketCounts[i] += c
+	}
+
+	p.StartTimeUnixNano = s.start
+	p.Count = s.hist.Count
+	p.Sum = s.hist.Sum
+	p.BucketCounts = slices.Clone(s.hist.BucketCounts)
+	p.ExplicitBounds = slices.Clone(s.hist.ExplicitBounds)
+	return p, nil
+}
+
+// AddExponentialHistogram accumulates a delta exponential histogram data
+// point and returns the resulting cumulative data point. See AddSum for the
+// meaning of extra.
+func (a *DeltaAccumulator) AddExponentialHistogram(metric Metric, extra Labels, p ExponentialHistogramDataPoint) (ExponentialHistogramDataPoint, error) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	if p.Flags.NoRecordedValue() {
+		return p, a.forget(metric, extra, p.Attributes)
+	}
+	s, fresh, err := a.prepare(metric, extra, p.Attributes, p.StartTimeUnixNano, p.TimeUnixNano)
+	if err != nil {
+		return ExponentialHistogramDataPoint{}, err
+	}
+	if !fresh && s.expHist.ZeroThreshold != p.ZeroThreshold {
+		s.start = startOf(p.StartTimeUnixNano, p.TimeUnixNano)
+		fresh = true
+	}
+	if fresh {
+		s.expHist = ExponentialHistogramDataPoint{
+			Scale:         p.Scale,
+			ZeroThreshold: p.ZeroThreshold,
+		}
+	}
+	mergeExponentialHistogram(&s.expHist, &p, a.opts.MaxExponentialHistogramBuckets)
+
+	p.StartTimeUnixNano = s.start
+	p.Count = s.expHist.Count
+	p.Sum = s.expHist.Sum
+	p.Scale = s.expHist.Scale
+	p.ZeroCount = s.expHist.ZeroCount
+	p.Positive = cloneExponentialBuckets(s.expHist.Positive)
+	p.Negative = cloneExponentialBuckets(s.expHist.Negative)
+	return p, nil
+}
+
+// Expire forgets the series that received no data point for longer than the
+// configured TTL, and returns how many were removed.
+func (a *DeltaAccumulator) Expire() int {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	return a.expire()
+}
+
+// Len returns the number of series currently tracked.
+func (a *DeltaAccumulator) Len() int {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	return len(a.series)
+}
+
+func (a *DeltaAccumulator) expire() int {
+	if a.opts.TTL <= 0 {
+		return 0
+	}
+	cutoff := a.opts.Now().Add(-a.opts.TTL)
+	removed := 0
+	for key, s := range a.series {
+		if s.lastSeen.Before(cutoff) {
+			delete(a.series, key)
+			removed++
+		}
+	}
+	return removed
+}
+
+// forget removes the state of the series the data point belongs to.
+func (a *DeltaAccumulator) forget(metric Metric, extra Labels, attributes []Attribute) error {
+	key, _, _, err := a.key(metric, extra, attributes)
+	if err != nil {
+		return err
+	}
+	delete(a.series, key)
+	return nil
+}
+
+// key returns the translated identity of the series the data point belongs
+// to.
+func (a *DeltaAccumulator) key(metric Metric, extra Labels, attributes []Attribute) (key, name string, ls Labels, err error) {
+	name, err = a.metricNamer.Build(metric)
+	if err != nil {
+		return "", "", nil, err
+	}
+	ls, err = a.labelNamer.BuildLabels(attributes)
+	if err != nil {
+		return "", "", nil, err
+	}
+	ls = ls.Merge(extra)
+	return seriesKey(name, ls), name, ls, nil
+}
+
+// prepare looks up, or creates, the state of the series the data point
+// belongs to, and validates the data point interval against it. When fresh
+// is true, the caller must discard the accumulated value before adding the
+// data point.
+func (a *DeltaAccumulator) prepare(metric Metric, extra Labels, attributes []Attribute, start, end uint64) (s *deltaSeries, fresh bool, err error) {
+	key, name, ls, err := a.key(metric, extra, attributes)
+	if err != nil {
+		return nil, false, err
+	}
+
+	s, ok := a.series[key]
+	if !ok {
+		if a.opts.MaxSeries > 0 && len(a.series) >= a.opts.MaxSeries && a.expire() == 0 {
+			return nil, false, fmt.Errorf("%w: dropping series %s%s", ErrSeriesLimit, name, ls)
+		}
+		s = &deltaSeries{start: startOf(start, end)}
+		a.series[key] = s
+		fresh = true
+	} else {
+		if start == 0 {
+			start = s.last
+		}
+		switch {
+		case end <= s.last:
+			return nil, false, fmt.Errorf("%w: series %s%s ends at %d, last accumulated delta ended at %d", ErrOutOfOrder, name, ls, end, s.last)
+		case start < s.start:
+			s.start = start
+			fresh = true
+		case start < s.last:
+			return nil, false, fmt.Errorf("%w: series %s%s starts at %d, last accumulated delta ended at %d", ErrOverlappingDelta, name, ls, start, s.last)
+		case start > s.last && a.opts.GapPolicy == GapPolicyReset:
+			s.start = start
+			fresh = true
+		}
+	}
+	s.last = end
+	s.lastSeen = a.opts.Now()
+	return s, fresh, nil
+}
+
+// startOf returns the start time of a data point, falling back to its
+// timestamp when the start time is unset.
+func startOf(start, end uint64) uint64 {
+	if start == 0 {
+		return end
+	}
+	return start
+}
+
+// mergeExponentialHistogram adds p into acc, bringing both to the lowest of
+// their scales. If maxBuckets is positive, acc is further downscaled until
+// neither bucket range is larger than maxBuckets.
+func mergeExponentialHistogram(acc, p *ExponentialHistogramDataPoint, maxBuckets int) {
+	scale := min(acc.Scale, p.Scale)
+	pos := addExponentialBuckets(
+		downscaleExponentialBuckets(acc.Positive, acc.Scale-scale),
+		downscaleExponentialBuckets(p.Positive, p.Scale-scale),
+	)
+	neg := addExponentialBuckets(
+		downscaleExponentialBuckets(acc.Negative, acc.Scale-scale),
+		downscaleExponentialBuckets(p.Negative, p.Scale-scale),
+	)
+	for maxBuckets > 0 && scale > minExponentialHistogramScale &&
+		(len(pos.BucketCounts) > maxBuckets || len(neg.BucketCounts) > maxBuckets) {
+		pos = downscaleExponentialBuckets(pos, 1)
+		neg = downscaleExponentialBuckets(neg, 1)
+		scale--
+	}
+
+	acc.Scale = scale
+	acc.Positive = pos
+	acc.Negative = neg
+	acc.Count += p.Count
+	acc.Sum += p.Sum
+	acc.ZeroCount += p.ZeroCount
+}
+
+// downscaleExponentialBuckets returns the buckets reduced by the given number
+// of scales. Each scale reduction merges pairs of adjacent buckets.
+func downscaleExponentialBuckets(b ExponentialHistogramBuckets, by int32) ExponentialHistogramBuckets {
+	if by <= 0 || len(b.BucketCounts) == 0 {
+		return b
+	}
+	offset := b.Offset >> by
+	last := (b.Offset + int32(len(b.BucketCounts)) - 1) >> by
+	counts := make([]uint64, last-offset+1)
+	for i, c := range b.BucketCounts {
+		counts[((b.Offset+int32(i))>>by)-offset] += c
+	}
+	return ExponentialHistogramBuckets{Offset: offset, BucketCounts: counts}
+}
+
+// addExponentialBuckets returns the sum of two bucket ranges of the same
+// scale.
+func addExponentialBuckets(a, b ExponentialHistogramBuckets) ExponentialHistogramBuckets {
+	if len(a.BucketCounts) == 0 {
+		return cloneExponentialBuckets(b)
+	}
+	if len(b.BucketCounts) == 0 {
+		return cloneExponentialBuckets(a)
+	}
+	offset := min(a.Offset, b.Offset)
+	end := max(a.Offset+int32(len(a.BucketCounts)), b.Offset+int32(len(b.BucketCounts)))
+	counts := make([]uint64, end-offset)
+	for i, c := range a.BucketCounts {
+		counts[a.Offset-offset+int32(i)] += c
+	}
+	for i, c := range b.BucketCounts {
+		counts[b.Offset-offset+int32(i)] += c
+	}
+	return ExponentialHistogramBuckets{Offset: offset, BucketCounts: counts}
+}
+
+func cloneExponentialBuckets(b ExponentialHistogramBuckets) ExponentialHistogramBuckets {
+	return ExponentialHistogramBuckets{Offset: b.Offset, BucketCounts: slices.Clone(b.BucketCounts)}
+}
@@ -0,0 +1,306 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlptranslator
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var deltaCounter = Metric{Name: "http.requests", Type: MetricTypeMonotonicCounter}
+
+func newTestAccumulator(opts DeltaAccumulatorOptions) *DeltaAccumulator {
+	return NewDeltaAccumulator(NewMetricNamer("", UnderscoreEscapingWithSuffixes), LabelNamer{}, opts)
+}
+
+func TestDeltaAccumulator_AddSum(t *testing.T) {
+	type delta struct {
+		start, end uint64
+		value      float64
+	}
+	tests := []struct {
+		name      string
+		gapPolicy GapPolicy
+		deltas    []delta
+		wantStart uint64
+		wantValue float64
+		wantError error
+	}{
+		{
+			name:      "contiguous deltas",
+			deltas:    []delta{{1, 2, 1}, {2, 3, 2}, {3, 4, 3}},
+			wantStart: 1,
+			wantValue: 6,
+		},
+		{
+			name:      "unset start time is contiguous",
+			deltas:    []delta{{1, 2, 1}, {0, 3, 2}},
+			wantStart: 1,
+			wantValue: 3,
+		},
+		{
+			name:      "duplicate delta is out of order",
+			deltas:    []delta{{1, 2, 1}, {1, 2, 1}},
+			wantError: ErrOutOfOrder,
+		},
+		{
+			name:      "older delta is out of order",
+			deltas:    []delta{{2, 3, 1}, {1, 2, 1}},
+			wantError: ErrOutOfOrder,
+		},
+		{
+			name:      "overlapping delta",
+			deltas:    []delta{{1, 3, 1}, {2, 4, 1}},
+			wantError: ErrOverlappingDelta,
+		},
+		{
+			name:      "start time reset",
+			deltas:    []delta{{5, 6, 1}, {6, 7, 1}, {1, 8, 4}},
+			wantStart: 1,
+			wantValue: 4,
+		},
+		{
+			name:      "gap resets by default",
+			deltas:    []delta{{1, 2, 1}, {3, 4, 2}},
+			wantStart: 3,
+			wantValue: 2,
+		},
+		{
+			name:      "gap ignored",
+			gapPolicy: GapPolicyIgnore,
+			deltas:    []delta{{1, 2, 1}, {3, 4, 2}},
+			wantStart: 1,
+			wantValue: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			acc := newTestAccumulator(DeltaAccumulatorOptions{GapPolicy: tt.gapPolicy})
+			var (
+				got NumberDataPoint
+				err error
+			)
+			for _, d := range tt.deltas {
+				got, err = acc.AddSum(deltaCounter, nil, NumberDataPoint{
+					StartTimeUnixNano: d.start,
+					TimeUnixNano:      d.end,
+					Value:             d.value,
+				})
+				if err != nil {
+					break
+				}
+			}
+			if tt.wantError != nil {
+				if !errors.Is(err, tt.wantError) {
+					t.Fatalf("AddSum() error = %v, want %v", err, tt.wantError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("AddSum() returned an error: %s", err)
+			}
+			if got.StartTimeUnixNano != tt.wantStart || got.Value != tt.wantValue {
+				t.Errorf("AddSum() = {start: %d, value: %g}, want {start: %d, value: %g}", got.StartTimeUnixNano, got.Value, tt.wantStart, tt.wantValue)
+			}
+		})
+	}
+}
+
+func TestDeltaAccumulator_SeriesIdentity(t *testing.T) {
+	acc := newTestAccumulator(DeltaAccumulatorOptions{})
+
+	// Both attribute sets translate to the same label set, and both metric
+	// names to the same metric name, so they are accumulated together.
+	_, err := acc.AddSum(deltaCounter, nil, NumberDataPoint{
+		Attributes:        []Attribute{{Key: "http.method", Value: "GET"}},
+		StartTimeUnixNano: 1, TimeUnixNano: 2, Value: 1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := acc.AddSum(Metric{Name: "http_requests", Type: MetricTypeMonotonicCounter}, nil, NumberDataPoint{
+		Attributes:        []Attribute{{Key: "http_method", Value: "GET"}},
+		StartTimeUnixNano: 2, TimeUnixNano: 3, Value: 1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Value != 2 {
+		t.Errorf("AddSum() value = %g, want 2", got.Value)
+	}
+
+	// Extra labels are part of the identity.
+	got, err = acc.AddSum(deltaCounter, Labels{{Name: "job", Value: "api"}}, NumberDataPoint{
+		Attributes:        []Attribute{{Key: "http.method", Value: "GET"}},
+		StartTimeUnixNano: 1, TimeUnixNano: 2, Value: 1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Value != 1 {
+		t.Errorf("AddSum() value = %g, want 1", got.Value)
+	}
+	if acc.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", acc.Len())
+	}
+}
+
+func TestDeltaAccumulator_AddHistogram(t *testing.T) {
+	acc := newTestAccumulator(DeltaAccumulatorOptions{})
+	metric := Metric{Name: "http.duration", Unit: "s", Type: MetricTypeHistogram}
+
+	_, err := acc.AddHistogram(metric, nil, HistogramDataPoint{
+		StartTimeUnixNano: 1, TimeUnixNano: 2,
+		Count: 3, Sum: 1.5, BucketCounts: []uint64{1, 2, 0}, ExplicitBounds: []float64{0.1, 1},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := acc.AddHistogram(metric, nil, HistogramDataPoint{
+		StartTimeUnixNano: 2, TimeUnixNano: 3,
+		Count: 2, Sum: 3, BucketCounts: []uint64{0, 1, 1}, ExplicitBounds: []float64{0.1, 1},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := HistogramDataPoint{
+		StartTimeUnixNano: 1, TimeUnixNano: 3,
+		Count: 5, Sum: 4.5, BucketCounts: []uint64{1, 3, 1}, ExplicitBounds: []float64{0.1, 1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("AddHistogram() = %+v, want %+v", got, want)
+	}
+
+	// Changing the bucket boundaries starts a new cumulative interval.
+	got, err = acc.AddHistogram(metric, nil, HistogramDataPoint{
+		StartTimeUnixNano: 3, TimeUnixNano: 4,
+		Count: 1, Sum: 0.5, BucketCounts: []uint64{1, 0}, ExplicitBounds: []float64{1},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = HistogramDataPoint{
+		StartTimeUnixNano: 3, TimeUnixNano: 4,
+		Count: 1, Sum: 0.5, BucketCounts: []uint64{1, 0}, ExplicitBounds: []float64{1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("AddHistogram() = %+v, want %+v", got, want)
+	}
+}
+
+func TestDeltaAccumulator_AddExponentialHistogram(t *testing.T) {
+	metric := Metric{Name: "http.duration", Unit: "s", Type: MetricTypeExponentialHistogram}
+
+	t.Run("different scales", func(t *testing.T) {
+		acc := newTestAccumulator(DeltaAccumulatorOptions{})
+		_, err := acc.AddExponentialHistogram(metric, nil, ExponentialHistogramDataPoint{
+			StartTimeUnixNano: 1, TimeUnixNano: 2,
+			Count: 4, Sum: 10, Scale: 1, ZeroCount: 1,
+			Positive: ExponentialHistogramBuckets{Offset: 1, BucketCounts: []uint64{1, 1, 1}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := acc.AddExponentialHistogram(metric, nil, ExponentialHistogramDataPoint{
+			StartTimeUnixNano: 2, TimeUnixNano: 3,
+			Count: 2, Sum: -3, Scale: 0,
+			Positive: ExponentialHistogramBuckets{Offset: 0, BucketCounts: []uint64{1}},
+			Negative: ExponentialHistogramBuckets{Offset: 2, BucketCounts: []uint64{1}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := ExponentialHistogramDataPoint{
+			StartTimeUnixNano: 1, TimeUnixNano: 3,
+			Count: 6, Sum: 7, Scale: 0, ZeroCount: 1,
+			// Scale 1 indexes 1, 2 and 3 map to scale 0 indexes 0, 1 and 1.
+			Positive: ExponentialHistogramBuckets{Offset: 0, BucketCounts: []uint64{2, 2}},
+			Negative: ExponentialHistogramBuckets{Offset: 2, BucketCounts: []uint64{1}},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("AddExponentialHistogram() = %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("bucket limit", func(t *testing.T) {
+		acc := newTestAccumulator(DeltaAccumulatorOptions{MaxExponentialHistogramBuckets: 2})
+		_, err := acc.AddExponentialHistogram(metric, nil, ExponentialHistogramDataPoint{
+			StartTimeUnixNano: 1, TimeUnixNano: 2,
+			Count: 1, Scale: 2,
+			Positive: ExponentialHistogramBuckets{Offset: 0, BucketCounts: []uint64{1}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := acc.AddExponentialHistogram(metric, nil, ExponentialHistogramDataPoint{
+			StartTimeUnixNano: 2, TimeUnixNano: 3,
+			Count: 1, Scale: 2,
+			Positive: ExponentialHistogramBuckets{Offset: 7, BucketCounts: []uint64{1}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Scale != 0 {
+			t.Errorf("AddExponentialHistogram() scale = %d, want 0", got.Scale)
+		}
+		want := ExponentialHistogramBuckets{Offset: 0, BucketCounts: []uint64{1, 1}}
+		if !reflect.DeepEqual(got.Positive, want) {
+			t.Errorf("AddExponentialHistogram() positive buckets = %+v, want %+v", got.Positive, want)
+		}
+	})
+}
+
+func TestDeltaAccumulator_Limits(t *testing.T) {
+	now := time.Unix(0, 0)
+	acc := newTestAccumulator(DeltaAccumulatorOptions{
+		TTL:       time.Minute,
+		MaxSeries: 1,
+		Now:       func() time.Time { return now },
+	})
+	add := func(method string) error {
+		_, err := acc.AddSum(deltaCounter, nil, NumberDataPoint{
+			Attributes:        []Attribute{{Key: "method", Value: method}},
+			StartTimeUnixNano: 1, TimeUnixNano: 2, Value: 1,
+		})
+		return err
+	}
+
+	if err := add("GET"); err != nil {
+		t.Fatal(err)
+	}
+	if err := add("POST"); !errors.Is(err, ErrSeriesLimit) {
+		t.Fatalf("AddSum() error = %v, want %v", err, ErrSeriesLimit)
+	}
+
+	// Once the first series is idle for longer than the TTL, it is expired to
+	// make room for the new one.
+	now = now.Add(2 * time.Minute)
+	if err := add("POST"); err != nil {
+		t.Fatal(err)
+	}
+	if acc.Len() != 1 {
+		t.Errorf("Len() = %d, want 1", acc.Len())
+	}
+
+	now = now.Add(2 * time.Minute)
+	if got := acc.Expire(); got != 1 {
+		t.Errorf("Expire() = %d, want 1", got)
+	}
+	if acc.Len() != 0 {
+		t.Errorf("Len() = %d, want 0", acc.Len())
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlptranslator
+
+import (
+	"encoding/hex"
+	"slices"
+)
+
+// SampleExemplar is a Prometheus exemplar built from an OTLP exemplar.
+type SampleExemplar struct {
+	// Labels holds the labels built from the filtered attributes of the
+	// exemplar, plus the trace_id and span_id labels when the exemplar is
+	// linked to a span.
+	Labels Labels
+	Value  float64
+	// Timestamp is the exemplar timestamp in milliseconds since the Unix
+	// epoch, zero when unknown.
+	Timestamp int64
+}
+
+// buildExemplars translates OTLP exemplars into Prometheus ones, sorted by
+// value. Trace and span IDs are hex-encoded; all-zero IDs are left out.
+func (b *SampleBuilder) buildExemplars(exemplars []Exemplar) ([]SampleExemplar, error) {
+	if len(exemplars) == 0 {
+		return nil, nil
+	}
+	res := make([]SampleExemplar, 0, len(exemplars))
+	for _, e := range exemplars {
+		ls, err := b.LabelNamer.BuildLabels(e.FilteredAttributes)
+		if err != nil {
+			return nil, err
+		}
+		var ids Labels
+		if e.TraceID != [16]byte{} {
+			ids = append(ids, Label{Name: ExemplarTraceIDKey, Value: hex.EncodeToString(e.TraceID[:])})
+		}
+		if e.SpanID != [8]byte{} {
+			ids = append(ids, Label{Name: ExemplarSpanIDKey, Value: hex.EncodeToString(e.SpanID[:])})
+		}
+		res = append(res, SampleExemplar{
+			Labels:    ls.Merge(NewLabels(ids...)),
+			Value:     e.Value,
+			Timestamp: nanosToMillis(e.TimeUnixNano),
+		})
+	}
+	slices.SortStableFunc(res, func(a, b SampleExemplar) int {
+		switch {
+		case a.Value < b.Value:
+			return -1
+		case a.Value > b.Value:
+			return 1
+		default:
+			return 0
+		}
+	})
+	return res, nil
+}
+
+// bucketExemplars splits exemplars, sorted by value, among cumulative buckets:
+// each bucket gets the exemplars greater than the previous upper bound and
+// lower than or equal to its own.
+func bucketExemplars(exemplars []SampleExemplar, buckets []ClassicBucket) [][]SampleExemplar {
+	if len(exemplars) == 0 {
+		return nil
+	}
+	res := make([][]SampleExemplar, len(buckets))
+	i := 0
+	for bi, bucket := range buckets {
+		start := i
+		for i < len(exemplars) && exemplars[i].Value <= bucket.UpperBound {
+			i++
+		}
+		if i > start {
+			res[bi] = exemplars[start:i]
+		}
+	}
+	return res
+}
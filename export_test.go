@@ -0,0 +1,22 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlptranslator
+
+// CanFastPathLabel exposes canFastPathLabel to the fuzz targets.
+var CanFastPathLabel = canFastPathLabel
+
+// BuildEscaped exposes buildEscaped to the fuzz targets.
+func (ln *LabelNamer) BuildEscaped(label string) (string, error) {
+	return ln.buildEscaped(label)
+}
@@ -0,0 +1,22 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package exposition renders metrics translated by otlptranslator in the
+// Prometheus exposition formats, and reads them back.
+//
+// Main components:
+//   - TextWriter: Writes the Prometheus text format
+//   - OpenMetricsWriter: Writes the OpenMetrics 1.0 text format
+//   - Parser: Reads metric families from the Prometheus text format and OpenMetrics
+//   - Family: A metric family read from an exposition
+package exposition
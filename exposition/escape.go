@@ -0,0 +1,95 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exposition
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/otlptranslator"
+	"github.com/prometheus/otlptranslator/internal/legacy"
+)
+
+var valueEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+
+// writeMetricName writes a metric name, quoted if it is not a valid classic
+// metric name.
+func writeMetricName(b *strings.Builder, name string) {
+	if legacy.IsMetricName(name) {
+		b.WriteString(name)
+		return
+	}
+	writeQuoted(b, name)
+}
+
+// writeQuoted writes s as a double-quoted, escaped string.
+func writeQuoted(b *strings.Builder, s string) {
+	b.WriteByte('"')
+	valueEscaper.WriteString(b, s) //nolint:errcheck // strings.Builder never fails.
+	b.WriteByte('"')
+}
+
+// writeSeries writes a series name and its labels, followed by a space. Names
+// that are not valid under the classic scheme are moved inside the braces, as
+// the first, quoted, element.
+func writeSeries(b *strings.Builder, name string, ls otlptranslator.Labels) {
+	legacyName := legacy.IsMetricName(name)
+	if legacyName {
+		b.WriteString(name)
+	}
+	if !legacyName || len(ls) > 0 {
+		b.WriteByte('{')
+		if !legacyName {
+			writeQuoted(b, name)
+			if len(ls) > 0 {
+				b.WriteByte(',')
+			}
+		}
+		writeLabelPairs(b, ls)
+		b.WriteByte('}')
+	}
+	b.WriteByte(' ')
+}
+
+// writeLabelPairs writes comma-separated label pairs, quoting the label names
+// that are not valid under the classic scheme.
+func writeLabelPairs(b *strings.Builder, ls otlptranslator.Labels) {
+	for i, l := range ls {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		if legacy.IsLabelName(l.Name) {
+			b.WriteString(l.Name)
+		} else {
+			writeQuoted(b, l.Name)
+		}
+		b.WriteByte('=')
+		writeQuoted(b, l.Value)
+	}
+}
+
+// formatFloat formats a sample value.
+func formatFloat(f float64) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "+Inf"
+	case math.IsInf(f, -1):
+		return "-Inf"
+	case math.IsNaN(f):
+		return "NaN"
+	default:
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	}
+}
@@ -0,0 +1,36 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exposition
+
+import "github.com/prometheus/otlptranslator"
+
+// Family is a metric family of a Prometheus or OpenMetrics exposition, as
+// read from a scraped endpoint.
+type Family struct {
+	// Name is the name of the family, as written in its metadata lines.
+	// OpenMetrics counter families are named without the `_total` suffix of
+	// their samples, while Prometheus text ones usually include it.
+	Name string
+	// Type is the type of the family. OpenMetrics info and stateset families
+	// use the "info" and "stateset" types.
+	Type otlptranslator.PrometheusType
+	// Unit is the unit of the family, from its `# UNIT` line.
+	Unit string
+	// Help is the description of the family, unescaped.
+	Help string
+	// Samples holds the samples of the family, including structural ones such
+	// as `_bucket`, `_sum`, `_count` and `_created` samples, in exposition
+	// order.
+	Samples []otlptranslator.Sample
+}
@@ -0,0 +1,345 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exposition
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/prometheus/otlptranslator"
+)
+
+const (
+	// OpenMetricsContentType is the content type of the OpenMetrics 1.0 text
+	// format.
+	OpenMetricsContentType = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+	// OpenMetricsUTF8ContentType is the content type of the OpenMetrics 1.0
+	// text format with quoted UTF-8 metric and label names.
+	OpenMetricsUTF8ContentType = "application/openmetrics-text; version=1.0.0; charset=utf-8; escaping=allow-utf-8"
+
+	// maxExemplarLabelsLength is the maximum combined length, in runes, of the
+	// label names and values of an OpenMetrics exemplar.
+	maxExemplarLabelsLength = 128
+)
+
+// OpenMetricsWriter renders translated OTLP samples in the OpenMetrics 1.0
+// text format.
+//
+// Families are named after MetricNamer.Build, without the `_total` suffix for
+// counters, whose samples always end with `_total`: when the MetricNamer does
+// not add suffixes, `_total` is appended to the sample names. Delta
+// histograms are written as gauge histograms, with `_gsum` and `_gcount`
+// samples.
+//
+// Families are described by the metric descriptions. The `# UNIT` line holds
+// the unit as translated by UnitNamer.Build. As
+// OpenMetrics requires the family name to end with the unit, the line is
+// omitted when the MetricNamer did not add it, as with the NoTranslation
+// strategy, rather than producing an invalid exposition.
+//
+// `_created` samples are kept for counters, histograms and summaries, and
+// samples holding a StaleNaN are skipped. The `_total` samples of counters
+// and the `_bucket` samples of histograms are written with their most recent
+// exemplar. Exemplars whose labels exceed the 128 characters allowed by
+// OpenMetrics only keep their trace_id and span_id labels.
+//
+// Example usage:
+//
+//	w := exposition.NewOpenMetricsWriter(otlptranslator.NewMetricNamer("", otlptranslator.UnderscoreEscapingWithSuffixes))
+//	if err := w.Add(metric, samples...); err != nil {
+//		// handle err
+//	}
+//	_, err := w.WriteTo(os.Stdout)
+type OpenMetricsWriter struct {
+	namer    otlptranslator.MetricNamer
+	metadata otlptranslator.MetadataBuilder
+	families map[string]*family
+	// sampleNames maps the name of every sample a family can have to the
+	// family, to detect families whose samples would clash.
+	sampleNames map[string]string
+}
+
+// NewOpenMetricsWriter creates an OpenMetricsWriter naming families with the
+// given MetricNamer.
+func NewOpenMetricsWriter(namer otlptranslator.MetricNamer) *OpenMetricsWriter {
+	return &OpenMetricsWriter{
+		namer:       namer,
+		metadata:    newMetadataBuilder(namer),
+		families:    map[string]*family{},
+		sampleNames: map[string]string{},
+	}
+}
+
+// ContentType returns the HTTP content type of the written exposition.
+func (w *OpenMetricsWriter) ContentType() string {
+	if w.namer.UTF8Allowed {
+		return OpenMetricsUTF8ContentType
+	}
+	return OpenMetricsContentType
+}
+
+// Add adds the samples of a metric, as built by otlptranslator.SampleBuilder,
+// to the family of the metric, described by the metric description. It
+// returns an error if a sample does not belong to the family, if the family
+// was already added with a different type or unit, or if the samples of the
+// family would clash with the ones of another family.
+//
+// The unit of the metric is dropped, without error, when the family name does
+// not end with it, as OpenMetrics requires: this is the case of any metric
+// with a unit when the MetricNamer adds no suffixes. Use a strategy adding
+// suffixes to keep the `# UNIT` lines.
+func (w *OpenMetricsWriter) Add(metric otlptranslator.Metric, samples ...otlptranslator.Sample) error {
+	md, err := w.metadata.Build(metric)
+	if err != nil {
+		return err
+	}
+	created, err := w.namer.BuildCreated(metric)
+	if err != nil {
+		return err
+	}
+	built, typ := md.FamilyName, md.Type
+	name := built
+	if typ == otlptranslator.PrometheusTypeCounter {
+		name = strings.TrimSuffix(built, "_total")
+	}
+	if name == "" {
+		return fmt.Errorf("metric %q results in an empty OpenMetrics family name", metric.Name)
+	}
+	if !strings.HasSuffix(name, "_"+md.Unit) {
+		md.Unit = ""
+	}
+
+	f, isNew, err := addFamily(w.families, name, md)
+	if err != nil {
+		return err
+	}
+	if isNew {
+		if err := w.reserveSampleNames(f); err != nil {
+			delete(w.families, name)
+			return err
+		}
+	} else if f.metadata.Unit != md.Unit {
+		return fmt.Errorf("metric family %q added with unit %q, then %q", name, f.metadata.Unit, md.Unit)
+	}
+
+	for _, s := range samples {
+		if otlptranslator.IsStaleNaN(s.Value) {
+			continue
+		}
+		var suffix string
+		if s.Name == created {
+			suffix = createdSuffix
+		} else {
+			var ok bool
+			if suffix, ok = strings.CutPrefix(s.Name, built); !ok {
+				return fmt.Errorf("sample %q does not belong to metric family %q", s.Name, name)
+			}
+		}
+		suffix, ok := openMetricsSuffix(typ, suffix)
+		if !ok {
+			return fmt.Errorf("sample %q does not belong to metric family %q", s.Name, name)
+		}
+		if suffix == createdSuffix && !hasCreatedSeries(typ) {
+			continue
+		}
+		s.Name = name + suffix
+		f.samples = append(f.samples, s)
+	}
+	return nil
+}
+
+// WriteTo writes all families to out, followed by `# EOF`.
+func (w *OpenMetricsWriter) WriteTo(out io.Writer) (int64, error) {
+	var b strings.Builder
+	for _, f := range sortedFamilies(w.families) {
+		writeMetadata(&b, "TYPE", f.name, string(f.metadata.Type))
+		if f.metadata.Unit != "" {
+			writeMetadata(&b, "UNIT", f.name, f.metadata.Unit)
+		}
+		if f.metadata.Help != "" {
+			writeMetadata(&b, "HELP", f.name, valueEscaper.Replace(f.metadata.Help))
+		}
+		for _, s := range f.samples {
+			writeSeries(&b, s.Name, s.Labels)
+			b.WriteString(formatFloat(s.Value))
+			if s.Timestamp != 0 {
+				b.WriteByte(' ')
+				b.WriteString(formatSeconds(s.Timestamp))
+			}
+			if hasExemplars(f.metadata.Type, s.Name, f.name) {
+				writeExemplar(&b, s.Exemplars)
+			}
+			b.WriteByte('\n')
+		}
+	}
+	b.WriteString("# EOF\n")
+	n, err := io.WriteString(out, b.String())
+	return int64(n), err
+}
+
+// reserveSampleNames records the names of all the samples the family can
+// have, and returns an error if one of them is already taken by another
+// family.
+func (w *OpenMetricsWriter) reserveSampleNames(f *family) error {
+	var names []string
+	for _, suffix := range openMetricsSuffixes(f.metadata.Type) {
+		name := f.name + suffix
+		if other, ok := w.sampleNames[name]; ok {
+			return fmt.Errorf("metric family %q clashes with metric family %q on sample %q", f.name, other, name)
+		}
+		names = append(names, name)
+	}
+	for _, name := range names {
+		w.sampleNames[name] = f.name
+	}
+	return nil
+}
+
+const (
+	totalSuffix   = "_total"
+	createdSuffix = "_created"
+)
+
+// openMetricsSuffixes returns the suffixes of the samples of a family of the
+// given type.
+func openMetricsSuffixes(typ otlptranslator.PrometheusType) []string {
+	switch typ {
+	case otlptranslator.PrometheusTypeCounter:
+		return []string{totalSuffix, createdSuffix}
+	case otlptranslator.PrometheusTypeHistogram:
+		return []string{"_bucket", "_sum", "_count", createdSuffix}
+	case otlptranslator.PrometheusTypeGaugeHistogram:
+		return []string{"_bucket", "_gsum", "_gcount"}
+	case otlptranslator.PrometheusTypeSummary:
+		return []string{"", "_sum", "_count", createdSuffix}
+	default:
+		return []string{""}
+	}
+}
+
+// openMetricsSuffix maps the suffix of a sample, relative to the name built
+// by the MetricNamer, to its OpenMetrics suffix. It returns false if a family
+// of the given type cannot have such samples.
+func openMetricsSuffix(typ otlptranslator.PrometheusType, suffix string) (string, bool) {
+	switch {
+	case typ == otlptranslator.PrometheusTypeCounter && suffix == "":
+		// The built name already ends with `_total`, unless the MetricNamer
+		// does not add suffixes.
+		return totalSuffix, true
+	case typ == otlptranslator.PrometheusTypeGaugeHistogram && suffix == "_sum":
+		return "_gsum", true
+	case typ == otlptranslator.PrometheusTypeGaugeHistogram && suffix == "_count":
+		return "_gcount", true
+	case suffix == createdSuffix:
+		// Dropped by the caller for types without created series.
+		return suffix, true
+	case slices.Contains(openMetricsSuffixes(typ), suffix) && suffix != totalSuffix:
+		return suffix, true
+	default:
+		return "", false
+	}
+}
+
+// hasCreatedSeries reports whether families of the given type have a
+// `_created` series in OpenMetrics.
+func hasCreatedSeries(typ otlptranslator.PrometheusType) bool {
+	switch typ {
+	case otlptranslator.PrometheusTypeCounter, otlptranslator.PrometheusTypeHistogram, otlptranslator.PrometheusTypeSummary:
+		return true
+	default:
+		return false
+	}
+}
+
+// hasExemplars reports whether the sample of the given name, in the family of
+// the given type and name, can have an exemplar in OpenMetrics.
+func hasExemplars(typ otlptranslator.PrometheusType, sample, family string) bool {
+	switch typ {
+	case otlptranslator.PrometheusTypeCounter:
+		return sample == family+totalSuffix
+	case otlptranslator.PrometheusTypeHistogram, otlptranslator.PrometheusTypeGaugeHistogram:
+		return sample == family+"_bucket"
+	default:
+		return false
+	}
+}
+
+// writeMetadata writes a `# TYPE`, `# UNIT` or `# HELP` line.
+func writeMetadata(b *strings.Builder, kind, name, value string) {
+	b.WriteString("# ")
+	b.WriteString(kind)
+	b.WriteByte(' ')
+	writeMetricName(b, name)
+	b.WriteByte(' ')
+	b.WriteString(value)
+	b.WriteByte('\n')
+}
+
+// writeExemplar writes the most recent of the given exemplars, if any.
+func writeExemplar(b *strings.Builder, exemplars []otlptranslator.SampleExemplar) {
+	if len(exemplars) == 0 {
+		return
+	}
+	e := exemplars[0]
+	for _, other := range exemplars[1:] {
+		if other.Timestamp >= e.Timestamp {
+			e = other
+		}
+	}
+	ls, ok := exemplarLabels(e.Labels)
+	if !ok {
+		return
+	}
+	b.WriteString(" # {")
+	writeLabelPairs(b, ls)
+	b.WriteString("} ")
+	b.WriteString(formatFloat(e.Value))
+	if e.Timestamp != 0 {
+		b.WriteByte(' ')
+		b.WriteString(formatSeconds(e.Timestamp))
+	}
+}
+
+// exemplarLabels returns the labels of an exemplar, only keeping the trace
+// and span IDs if they exceed the OpenMetrics limit. It returns false if even
+// those exceed it.
+func exemplarLabels(ls otlptranslator.Labels) (otlptranslator.Labels, bool) {
+	if labelsLength(ls) <= maxExemplarLabelsLength {
+		return ls, true
+	}
+	var ids otlptranslator.Labels
+	for _, l := range ls {
+		if l.Name == otlptranslator.ExemplarTraceIDKey || l.Name == otlptranslator.ExemplarSpanIDKey {
+			ids = append(ids, l)
+		}
+	}
+	return ids, labelsLength(ids) <= maxExemplarLabelsLength
+}
+
+func labelsLength(ls otlptranslator.Labels) int {
+	var n int
+	for _, l := range ls {
+		n += utf8.RuneCountInString(l.Name) + utf8.RuneCountInString(l.Value)
+	}
+	return n
+}
+
+// formatSeconds formats a timestamp in milliseconds as OpenMetrics seconds.
+func formatSeconds(ms int64) string {
+	return strconv.FormatFloat(float64(ms)/1000, 'f', -1, 64)
+}
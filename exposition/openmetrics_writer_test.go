@@ -0,0 +1,201 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exposition
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/otlptranslator"
+)
+
+func TestOpenMetricsWriter(t *testing.T) {
+	counter := otlptranslator.Metric{Name: "http.requests", Type: otlptranslator.MetricTypeMonotonicCounter, Description: "Number of HTTP requests."}
+	histogram := otlptranslator.Metric{Name: "http.duration", Unit: "s", Type: otlptranslator.MetricTypeHistogram, Description: `Duration of "HTTP" requests.`}
+	deltaHistogram := otlptranslator.Metric{Name: "batch.size", Unit: "By", Type: otlptranslator.MetricTypeHistogram, Temporality: otlptranslator.TemporalityDelta}
+	exemplar := otlptranslator.Exemplar{
+		TimeUnixNano: 1_500_000_000,
+		Value:        0.3,
+		TraceID:      [16]byte{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+		SpanID:       [8]byte{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+	}
+
+	tests := []struct {
+		name     string
+		strategy otlptranslator.TranslationStrategyOption
+		want     string
+	}{
+		{
+			name:     "escaped names",
+			strategy: otlptranslator.UnderscoreEscapingWithSuffixes,
+			want: `# TYPE batch_size_bytes gaugehistogram
+# UNIT batch_size_bytes bytes
+batch_size_bytes_bucket{le="+Inf"} 3 2
+batch_size_bytes_gsum 300 2
+batch_size_bytes_gcount 3 2
+# TYPE http_duration_seconds histogram
+# UNIT http_duration_seconds seconds
+# HELP http_duration_seconds Duration of \"HTTP\" requests.
+http_duration_seconds_bucket{le="0.5"} 1 2 # {span_id="00f067aa0ba902b7",trace_id="4bf92f3577b34da6a3ce929d0e0e4736"} 0.3 1.5
+http_duration_seconds_bucket{le="+Inf"} 2 2
+http_duration_seconds_sum 1.5 2
+http_duration_seconds_count 2 2
+http_duration_seconds_created 1 2
+# TYPE http_requests counter
+# HELP http_requests Number of HTTP requests.
+http_requests_total 42 2 # {span_id="00f067aa0ba902b7",trace_id="4bf92f3577b34da6a3ce929d0e0e4736"} 0.3 1.5
+http_requests_created 1 2
+# EOF
+`,
+		},
+		{
+			name:     "no translation",
+			strategy: otlptranslator.NoTranslation,
+			want: `# TYPE "batch.size" gaugehistogram
+{"batch.size_bucket",le="+Inf"} 3 2
+{"batch.size_gsum"} 300 2
+{"batch.size_gcount"} 3 2
+# TYPE "http.duration" histogram
+# HELP "http.duration" Duration of \"HTTP\" requests.
+{"http.duration_bucket",le="0.5"} 1 2 # {span_id="00f067aa0ba902b7",trace_id="4bf92f3577b34da6a3ce929d0e0e4736"} 0.3 1.5
+{"http.duration_bucket",le="+Inf"} 2 2
+{"http.duration_sum"} 1.5 2
+{"http.duration_count"} 2 2
+{"http.duration_created"} 1 2
+# TYPE "http.requests" counter
+# HELP "http.requests" Number of HTTP requests.
+{"http.requests_total"} 42 2 # {span_id="00f067aa0ba902b7",trace_id="4bf92f3577b34da6a3ce929d0e0e4736"} 0.3 1.5
+{"http.requests_created"} 1 2
+# EOF
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			namer := otlptranslator.NewMetricNamer("", tt.strategy)
+			builder := otlptranslator.SampleBuilder{
+				MetricNamer:   namer,
+				LabelNamer:    otlptranslator.LabelNamer{UTF8Allowed: namer.UTF8Allowed},
+				CreatedSeries: true,
+			}
+			w := NewOpenMetricsWriter(namer)
+
+			samples, err := builder.BuildNumber(counter, nil, otlptranslator.NumberDataPoint{
+				StartTimeUnixNano: 1_000_000_000,
+				TimeUnixNano:      2_000_000_000,
+				Value:             42,
+				Exemplars:         []otlptranslator.Exemplar{exemplar},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := w.Add(counter, samples...); err != nil {
+				t.Fatal(err)
+			}
+
+			samples, err = builder.BuildHistogram(histogram, nil, otlptranslator.HistogramDataPoint{
+				StartTimeUnixNano: 1_000_000_000,
+				TimeUnixNano:      2_000_000_000,
+				Count:             2,
+				Sum:               1.5,
+				BucketCounts:      []uint64{1, 1},
+				ExplicitBounds:    []float64{0.5},
+				Exemplars:         []otlptranslator.Exemplar{exemplar},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := w.Add(histogram, samples...); err != nil {
+				t.Fatal(err)
+			}
+
+			samples, err = builder.BuildHistogram(deltaHistogram, nil, otlptranslator.HistogramDataPoint{
+				StartTimeUnixNano: 1_000_000_000,
+				TimeUnixNano:      2_000_000_000,
+				Count:             3,
+				Sum:               300,
+				BucketCounts:      []uint64{3},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := w.Add(deltaHistogram, samples...); err != nil {
+				t.Fatal(err)
+			}
+
+			var out strings.Builder
+			if _, err := w.WriteTo(&out); err != nil {
+				t.Fatal(err)
+			}
+			if out.String() != tt.want {
+				t.Errorf("WriteTo() =\n%s\nwant\n%s", out.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestOpenMetricsWriter_UnitWithoutSuffix(t *testing.T) {
+	gauge := otlptranslator.Metric{Name: "memory.usage", Unit: "By", Type: otlptranslator.MetricTypeGauge}
+	tests := []struct {
+		strategy otlptranslator.TranslationStrategyOption
+		want     string
+	}{
+		{
+			strategy: otlptranslator.UnderscoreEscapingWithSuffixes,
+			want:     "# TYPE memory_usage_bytes gauge\n# UNIT memory_usage_bytes bytes\nmemory_usage_bytes 1\n# EOF\n",
+		},
+		{
+			// The family name does not end with the unit, which is dropped.
+			strategy: otlptranslator.UnderscoreEscapingWithoutSuffixes,
+			want:     "# TYPE memory_usage gauge\nmemory_usage 1\n# EOF\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.strategy), func(t *testing.T) {
+			namer := otlptranslator.NewMetricNamer("", tt.strategy)
+			name, err := namer.Build(gauge)
+			if err != nil {
+				t.Fatal(err)
+			}
+			w := NewOpenMetricsWriter(namer)
+			if err := w.Add(gauge, otlptranslator.Sample{Name: name, Value: 1}); err != nil {
+				t.Fatal(err)
+			}
+			var out strings.Builder
+			if _, err := w.WriteTo(&out); err != nil {
+				t.Fatal(err)
+			}
+			if out.String() != tt.want {
+				t.Errorf("WriteTo() =\n%s\nwant\n%s", out.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestOpenMetricsWriter_Errors(t *testing.T) {
+	namer := otlptranslator.NewMetricNamer("", otlptranslator.UnderscoreEscapingWithSuffixes)
+	w := NewOpenMetricsWriter(namer)
+
+	if err := w.Add(otlptranslator.Metric{Name: "jobs", Type: otlptranslator.MetricTypeMonotonicCounter}); err != nil {
+		t.Fatal(err)
+	}
+	err := w.Add(otlptranslator.Metric{Name: "jobs_created", Type: otlptranslator.MetricTypeGauge})
+	if err == nil || err.Error() != `metric family "jobs_created" clashes with metric family "jobs" on sample "jobs_created"` {
+		t.Errorf("Add() error = %v", err)
+	}
+	err = w.Add(otlptranslator.Metric{Name: "jobs", Type: otlptranslator.MetricTypeMonotonicCounter}, otlptranslator.Sample{Name: "jobs_count"})
+	if err == nil || err.Error() != `sample "jobs_count" does not belong to metric family "jobs"` {
+		t.Errorf("Add() error = %v", err)
+	}
+}
@@ -0,0 +1,603 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exposition
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/prometheus/otlptranslator"
+	"github.com/prometheus/otlptranslator/internal/legacy"
+)
+
+// Parser reads metric families from a Prometheus text or OpenMetrics 1.0
+// exposition, one family at a time, without holding the whole exposition in
+// memory.
+//
+// Metric and label names can be quoted UTF-8 strings, metric names being
+// then written as the first element inside the braces. Samples are grouped
+// into the family announced by the preceding metadata lines when their name
+// is the family name followed by a suffix of its type, such as `_bucket` for
+// histograms. Other samples start a new family of unknown type, named after
+// the sample. The "untyped" type of the text format is read as
+// otlptranslator.PrometheusTypeUnknown.
+//
+// In OpenMetrics, timestamps are in seconds and are converted to
+// milliseconds, exemplars are read, and `_created` samples are kept while
+// also setting the created timestamp of the other samples of their series.
+// The exposition must end with `# EOF`, and cannot hold blank lines nor
+// comments other than metadata.
+//
+// Example usage:
+//
+//	p := exposition.NewOpenMetricsParser(resp.Body)
+//	for {
+//		f, err := p.Next()
+//		if errors.Is(err, io.EOF) {
+//			break
+//		}
+//		if err != nil {
+//			// handle err
+//		}
+//		families = append(families, f)
+//	}
+type Parser struct {
+	r           *bufio.Reader
+	openMetrics bool
+
+	line int
+	// pending holds a line read but belonging to the next family.
+	pending    string
+	hasPending bool
+	cur        *Family
+	// metadata tracks the metadata lines seen for the current family.
+	metadata map[string]bool
+	eof      bool
+	done     bool
+}
+
+// NewTextParser creates a Parser reading the Prometheus text format.
+func NewTextParser(r io.Reader) *Parser {
+	return &Parser{r: bufio.NewReader(r)}
+}
+
+// NewOpenMetricsParser creates a Parser reading the OpenMetrics 1.0 text
+// format.
+func NewOpenMetricsParser(r io.Reader) *Parser {
+	return &Parser{r: bufio.NewReader(r), openMetrics: true}
+}
+
+// NewParser creates a Parser for the format of the given HTTP content type,
+// reading OpenMetrics for application/openmetrics-text and the Prometheus
+// text format otherwise.
+func NewParser(r io.Reader, contentType string) *Parser {
+	if strings.HasPrefix(strings.TrimSpace(contentType), "application/openmetrics-text") {
+		return NewOpenMetricsParser(r)
+	}
+	return NewTextParser(r)
+}
+
+// Next returns the next family of the exposition. It returns io.EOF once all
+// families were read.
+func (p *Parser) Next() (Family, error) {
+	if p.done {
+		return Family{}, io.EOF
+	}
+	for {
+		line, err := p.readLine()
+		if errors.Is(err, io.EOF) {
+			p.done = true
+			if p.openMetrics && !p.eof {
+				return Family{}, p.errorf("missing # EOF")
+			}
+			if f, ok := p.flush(); ok {
+				return f, nil
+			}
+			return Family{}, io.EOF
+		}
+		if err != nil {
+			p.done = true
+			return Family{}, err
+		}
+
+		startsFamily, err := p.parseLine(line)
+		if err != nil {
+			p.done = true
+			return Family{}, err
+		}
+		if startsFamily {
+			// The line is parsed again once the current family is returned.
+			p.pending, p.hasPending = line, true
+			f, _ := p.flush()
+			return f, nil
+		}
+	}
+}
+
+// readLine returns the next line, without its line feed.
+func (p *Parser) readLine() (string, error) {
+	if p.hasPending {
+		p.hasPending = false
+		return p.pending, nil
+	}
+	line, err := p.r.ReadString('\n')
+	if err != nil && (!errors.Is(err, io.EOF) || line == "") {
+		return "", err
+	}
+	p.line++
+	if p.eof {
+		return "", p.errorf("content after # EOF")
+	}
+	if p.openMetrics && !strings.HasSuffix(line, "\n") {
+		return "", p.errorf("missing line feed")
+	}
+	if !utf8.ValidString(line) {
+		return "", p.errorf("invalid UTF-8")
+	}
+	return strings.TrimSuffix(line, "\n"), nil
+}
+
+// flush returns the current family, if any, and resets it.
+func (p *Parser) flush() (Family, bool) {
+	if p.cur == nil {
+		return Family{}, false
+	}
+	f := *p.cur
+	p.cur, p.metadata = nil, nil
+	if p.openMetrics {
+		setCreatedTimestamps(&f)
+	}
+	return f, true
+}
+
+func (p *Parser) errorf(format string, args ...any) error {
+	return fmt.Errorf("exposition: line %d: %s", p.line, fmt.Sprintf(format, args...))
+}
+
+// parseLine parses a line into the current family. It returns true, without
+// parsing it, if the line starts another family while the current one is not
+// empty.
+func (p *Parser) parseLine(line string) (bool, error) {
+	switch {
+	case line == "" || strings.TrimSpace(line) == "":
+		if p.openMetrics {
+			return false, p.errorf("unexpected blank line")
+		}
+		return false, nil
+	case line == "# EOF" && p.openMetrics:
+		p.eof = true
+		return false, nil
+	case strings.HasPrefix(line, "#"):
+		return p.parseComment(line)
+	default:
+		return p.parseSample(line)
+	}
+}
+
+// parseComment parses a metadata line, ignoring other comments in the
+// Prometheus text format.
+func (p *Parser) parseComment(line string) (bool, error) {
+	rest := strings.TrimLeft(line[1:], " \t")
+	kind, rest, _ := strings.Cut(rest, " ")
+	if kind != "HELP" && kind != "TYPE" && kind != "UNIT" || rest == "" {
+		if p.openMetrics {
+			return false, p.errorf("invalid comment %q", line)
+		}
+		return false, nil
+	}
+	s := &scanner{s: strings.TrimLeft(rest, " \t")}
+	name, err := s.metricName()
+	if err != nil {
+		return false, p.errorf("%s: %v", kind, err)
+	}
+	value := ""
+	if s.s != "" {
+		if s.s[0] != ' ' && s.s[0] != '\t' {
+			return false, p.errorf("%s: unexpected %q after the metric name", kind, s.s)
+		}
+		value = strings.TrimLeft(s.s, " \t")
+		if !p.openMetrics && kind != "HELP" {
+			value = strings.TrimRight(value, " \t")
+		}
+	}
+
+	if p.cur != nil && p.cur.Name != name {
+		return true, nil
+	}
+	if p.cur == nil {
+		p.cur = &Family{Name: name, Type: otlptranslator.PrometheusTypeUnknown}
+		p.metadata = map[string]bool{}
+	}
+	if p.metadata[kind] {
+		return false, p.errorf("duplicate %s line for %q", kind, name)
+	}
+	if len(p.cur.Samples) > 0 {
+		return false, p.errorf("%s line for %q after its samples", kind, name)
+	}
+	p.metadata[kind] = true
+
+	switch kind {
+	case "HELP":
+		p.cur.Help = unescape(value, p.openMetrics)
+	case "UNIT":
+		p.cur.Unit = value
+	case "TYPE":
+		typ, ok := parseType(value, p.openMetrics)
+		if !ok {
+			return false, p.errorf("invalid type %q", value)
+		}
+		p.cur.Type = typ
+	}
+	return false, nil
+}
+
+// parseType parses the type of a TYPE line.
+func parseType(s string, openMetrics bool) (otlptranslator.PrometheusType, bool) {
+	switch typ := otlptranslator.PrometheusType(s); typ {
+	case otlptranslator.PrometheusTypeCounter, otlptranslator.PrometheusTypeGauge,
+		otlptranslator.PrometheusTypeHistogram, otlptranslator.PrometheusTypeSummary:
+		return typ, true
+	case otlptranslator.PrometheusTypeUnknown, otlptranslator.PrometheusTypeGaugeHistogram, "info", "stateset":
+		return typ, openMetrics
+	case "untyped":
+		return otlptranslator.PrometheusTypeUnknown, !openMetrics
+	default:
+		return "", false
+	}
+}
+
+// parseSample parses a sample line into the current family.
+func (p *Parser) parseSample(line string) (bool, error) {
+	sample, err := p.sample(line)
+	if err != nil {
+		return false, err
+	}
+	if p.cur != nil && sampleOf(p.cur, sample.Name) {
+		p.cur.Samples = append(p.cur.Samples, sample)
+		return false, nil
+	}
+	if p.cur != nil {
+		return true, nil
+	}
+	p.cur = &Family{Name: sample.Name, Type: otlptranslator.PrometheusTypeUnknown, Samples: []otlptranslator.Sample{sample}}
+	p.metadata = map[string]bool{}
+	return false, nil
+}
+
+// sampleOf reports whether a sample of the given name belongs to a family.
+func sampleOf(f *Family, name string) bool {
+	suffix, ok := strings.CutPrefix(name, f.Name)
+	if !ok {
+		return false
+	}
+	switch f.Type {
+	case otlptranslator.PrometheusTypeCounter:
+		return suffix == "" || suffix == totalSuffix || suffix == createdSuffix
+	case otlptranslator.PrometheusTypeHistogram:
+		return suffix == "_bucket" || suffix == "_sum" || suffix == "_count" || suffix == createdSuffix
+	case otlptranslator.PrometheusTypeGaugeHistogram:
+		return suffix == "_bucket" || suffix == "_gsum" || suffix == "_gcount"
+	case otlptranslator.PrometheusTypeSummary:
+		return suffix == "" || suffix == "_sum" || suffix == "_count" || suffix == createdSuffix
+	case "info":
+		return suffix == "_info"
+	default:
+		return suffix == ""
+	}
+}
+
+// sample parses a sample line.
+func (p *Parser) sample(line string) (otlptranslator.Sample, error) {
+	s := &scanner{s: line}
+	var (
+		sample otlptranslator.Sample
+		err    error
+	)
+	if !strings.HasPrefix(line, "{") {
+		if sample.Name, err = s.metricName(); err != nil {
+			return sample, p.errorf("%v", err)
+		}
+	}
+	if s.consume('{') {
+		if sample.Labels, err = s.labels(&sample.Name); err != nil {
+			return sample, p.errorf("%v", err)
+		}
+	}
+	if sample.Name == "" {
+		return sample, p.errorf("missing metric name")
+	}
+
+	fields := strings.Fields(s.s)
+	exemplar := -1
+	for i, f := range fields {
+		if f == "#" {
+			exemplar = i
+			break
+		}
+	}
+	values := fields
+	if exemplar >= 0 {
+		values = fields[:exemplar]
+	}
+	if len(values) == 0 || len(values) > 2 || s.s != "" && s.s[0] != ' ' && s.s[0] != '\t' {
+		return sample, p.errorf("invalid sample %q", line)
+	}
+	if sample.Value, err = strconv.ParseFloat(values[0], 64); err != nil {
+		return sample, p.errorf("invalid value %q", values[0])
+	}
+	if len(values) == 2 {
+		if sample.Timestamp, err = p.timestamp(values[1]); err != nil {
+			return sample, p.errorf("invalid timestamp %q", values[1])
+		}
+	}
+	if exemplar >= 0 {
+		if !p.openMetrics {
+			return sample, p.errorf("exemplars are only supported in OpenMetrics")
+		}
+		_, rest, _ := strings.Cut(s.s, "#")
+		e, err := p.exemplar(strings.TrimLeft(rest, " "))
+		if err != nil {
+			return sample, err
+		}
+		sample.Exemplars = []otlptranslator.SampleExemplar{e}
+	}
+	return sample, nil
+}
+
+// exemplar parses the exemplar of a sample, after the `#`.
+func (p *Parser) exemplar(line string) (otlptranslator.SampleExemplar, error) {
+	var e otlptranslator.SampleExemplar
+	s := &scanner{s: line}
+	if !s.consume('{') {
+		return e, p.errorf("invalid exemplar %q", line)
+	}
+	var name string
+	ls, err := s.labels(&name)
+	if err != nil || name != "" {
+		return e, p.errorf("invalid exemplar labels %q", line)
+	}
+	e.Labels = ls
+	fields := strings.Fields(s.s)
+	if len(fields) == 0 || len(fields) > 2 {
+		return e, p.errorf("invalid exemplar %q", line)
+	}
+	if e.Value, err = strconv.ParseFloat(fields[0], 64); err != nil {
+		return e, p.errorf("invalid exemplar value %q", fields[0])
+	}
+	if len(fields) == 2 {
+		if e.Timestamp, err = p.timestamp(fields[1]); err != nil {
+			return e, p.errorf("invalid exemplar timestamp %q", fields[1])
+		}
+	}
+	return e, nil
+}
+
+// timestamp parses a timestamp into milliseconds: the Prometheus text format
+// uses integer milliseconds, and OpenMetrics seconds.
+func (p *Parser) timestamp(s string) (int64, error) {
+	if !p.openMetrics {
+		return strconv.ParseInt(s, 10, 64)
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0, errors.New("invalid timestamp")
+	}
+	return int64(math.Round(v * 1000)), nil
+}
+
+// setCreatedTimestamps sets the created timestamp of the samples of every
+// series holding a `_created` sample.
+func setCreatedTimestamps(f *Family) {
+	created := map[string]int64{}
+	for _, s := range f.Samples {
+		if strings.HasSuffix(s.Name, createdSuffix) && s.Name != f.Name {
+			created[s.Labels.String()] = int64(math.Round(s.Value * 1000))
+		}
+	}
+	if len(created) == 0 {
+		return
+	}
+	for i, s := range f.Samples {
+		if strings.HasSuffix(s.Name, createdSuffix) {
+			continue
+		}
+		ls := s.Labels
+		if ls.Has(otlptranslator.BucketLabel) && strings.HasSuffix(s.Name, "_bucket") {
+			ls = withoutLabel(ls, otlptranslator.BucketLabel)
+		} else if ls.Has(otlptranslator.QuantileLabel) && s.Name == f.Name {
+			ls = withoutLabel(ls, otlptranslator.QuantileLabel)
+		}
+		if ts, ok := created[ls.String()]; ok {
+			f.Samples[i].CreatedTimestamp = ts
+		}
+	}
+}
+
+func withoutLabel(ls otlptranslator.Labels, name string) otlptranslator.Labels {
+	res := make(otlptranslator.Labels, 0, len(ls))
+	for _, l := range ls {
+		if l.Name != name {
+			res = append(res, l)
+		}
+	}
+	return res
+}
+
+// unescape reverses the escaping of help texts: `\\` and `\n`, plus `\"` in
+// OpenMetrics. Other backslashes are kept.
+func unescape(s string, openMetrics bool) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		switch s[i+1] {
+		case '\\':
+			b.WriteByte('\\')
+		case 'n':
+			b.WriteByte('\n')
+		case '"':
+			if !openMetrics {
+				b.WriteString(`\"`)
+				break
+			}
+			b.WriteByte('"')
+		default:
+			b.WriteByte('\\')
+			b.WriteByte(s[i+1])
+		}
+		i++
+	}
+	return b.String()
+}
+
+// scanner consumes the tokens of a line.
+type scanner struct {
+	s string
+}
+
+func (s *scanner) consume(c byte) bool {
+	if s.s != "" && s.s[0] == c {
+		s.s = s.s[1:]
+		return true
+	}
+	return false
+}
+
+func (s *scanner) skipSpaces() {
+	s.s = strings.TrimLeft(s.s, " \t")
+}
+
+// metricName consumes a metric name, either classic or quoted.
+func (s *scanner) metricName() (string, error) {
+	if strings.HasPrefix(s.s, `"`) {
+		return s.quoted()
+	}
+	i := 0
+	for i < len(s.s) && (legacy.IsLabelRune(rune(s.s[i]), i) || s.s[i] == ':') {
+		i++
+	}
+	if i == 0 {
+		return "", fmt.Errorf("invalid metric name in %q", s.s)
+	}
+	name := s.s[:i]
+	s.s = s.s[i:]
+	return name, nil
+}
+
+// quoted consumes a double-quoted string, unescaping `\\`, `\"` and `\n`.
+func (s *scanner) quoted() (string, error) {
+	var b strings.Builder
+	for i := 1; i < len(s.s); i++ {
+		switch c := s.s[i]; c {
+		case '"':
+			s.s = s.s[i+1:]
+			return b.String(), nil
+		case '\\':
+			if i+1 == len(s.s) {
+				return "", errors.New("unterminated quoted string")
+			}
+			i++
+			switch s.s[i] {
+			case '\\', '"':
+				b.WriteByte(s.s[i])
+			case 'n':
+				b.WriteByte('\n')
+			default:
+				return "", fmt.Errorf("invalid escape sequence \\%c", s.s[i])
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", errors.New("unterminated quoted string")
+}
+
+// labels consumes a label set, after its opening brace. A quoted string
+// without value sets the metric name, if it is not already set.
+func (s *scanner) labels(name *string) (otlptranslator.Labels, error) {
+	var ls otlptranslator.Labels
+	for {
+		s.skipSpaces()
+		if s.consume('}') {
+			if len(ls) == 0 {
+				return nil, nil
+			}
+			return otlptranslator.NewLabels(ls...), nil
+		}
+		var (
+			label string
+			err   error
+		)
+		quoted := strings.HasPrefix(s.s, `"`)
+		if quoted {
+			label, err = s.quoted()
+		} else {
+			label, err = s.labelName()
+		}
+		if err != nil {
+			return nil, err
+		}
+		s.skipSpaces()
+		switch {
+		case s.consume('='):
+			s.skipSpaces()
+			if !strings.HasPrefix(s.s, `"`) {
+				return nil, fmt.Errorf("missing value for label %q", label)
+			}
+			value, err := s.quoted()
+			if err != nil {
+				return nil, err
+			}
+			for _, l := range ls {
+				if l.Name == label {
+					return nil, fmt.Errorf("duplicate label %q", label)
+				}
+			}
+			ls = append(ls, otlptranslator.Label{Name: label, Value: value})
+		case quoted && *name == "" && len(ls) == 0:
+			*name = label
+		default:
+			return nil, fmt.Errorf("missing value for label %q", label)
+		}
+		s.skipSpaces()
+		if !s.consume(',') && !strings.HasPrefix(s.s, "}") {
+			return nil, fmt.Errorf("expected , or } in label set, got %q", s.s)
+		}
+	}
+}
+
+// labelName consumes a classic label name.
+func (s *scanner) labelName() (string, error) {
+	i := 0
+	for i < len(s.s) && legacy.IsLabelRune(rune(s.s[i]), i) {
+		i++
+	}
+	if i == 0 {
+		return "", fmt.Errorf("invalid label name in %q", s.s)
+	}
+	name := s.s[:i]
+	s.s = s.s[i:]
+	return name, nil
+}
@@ -0,0 +1,309 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exposition
+
+import (
+	"errors"
+	"io"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/otlptranslator"
+)
+
+// parseAll reads all families of an exposition.
+func parseAll(p *Parser) ([]Family, error) {
+	var families []Family
+	for {
+		f, err := p.Next()
+		if errors.Is(err, io.EOF) {
+			return families, nil
+		}
+		if err != nil {
+			return families, err
+		}
+		families = append(families, f)
+	}
+}
+
+func TestParser_OpenMetrics(t *testing.T) {
+	in := `# TYPE "http.requests" counter
+# HELP "http.requests" Number of \"HTTP\" requests.\nPer method.
+{"http.requests_total",method="GET"} 42 2 # {trace_id="4bf92f3577b34da6a3ce929d0e0e4736"} 0.3 1.5
+{"http.requests_created",method="GET"} 1.25 2
+# TYPE http_duration_seconds histogram
+# UNIT http_duration_seconds seconds
+http_duration_seconds_bucket{le="0.5",path="/a,b"} 1
+http_duration_seconds_bucket{le="+Inf",path="/a,b"} 2
+http_duration_seconds_sum{path="/a,b"} 1.5
+http_duration_seconds_count{path="/a,b"} 2
+http_duration_seconds_created{path="/a,b"} 1
+# TYPE target info
+target_info{"service.name"="api"} 1
+# TYPE queue_size gauge
+queue_size NaN
+# EOF
+`
+	got, err := parseAll(NewOpenMetricsParser(strings.NewReader(in)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	get := otlptranslator.Labels{{Name: "method", Value: "GET"}}
+	path := otlptranslator.Labels{{Name: "path", Value: "/a,b"}}
+	bucket := func(le string) otlptranslator.Labels {
+		return otlptranslator.NewLabels(otlptranslator.Label{Name: "le", Value: le}, path[0])
+	}
+	want := []Family{
+		{
+			Name: "http.requests",
+			Type: otlptranslator.PrometheusTypeCounter,
+			Help: "Number of \"HTTP\" requests.\nPer method.",
+			Samples: []otlptranslator.Sample{
+				{Name: "http.requests_total", Labels: get, Value: 42, Timestamp: 2000, CreatedTimestamp: 1250, Exemplars: []otlptranslator.SampleExemplar{{
+					Labels:    otlptranslator.Labels{{Name: "trace_id", Value: "4bf92f3577b34da6a3ce929d0e0e4736"}},
+					Value:     0.3,
+					Timestamp: 1500,
+				}}},
+				{Name: "http.requests_created", Labels: get, Value: 1.25, Timestamp: 2000},
+			},
+		},
+		{
+			Name: "http_duration_seconds",
+			Type: otlptranslator.PrometheusTypeHistogram,
+			Unit: "seconds",
+			Samples: []otlptranslator.Sample{
+				{Name: "http_duration_seconds_bucket", Labels: bucket("0.5"), Value: 1, CreatedTimestamp: 1000},
+				{Name: "http_duration_seconds_bucket", Labels: bucket("+Inf"), Value: 2, CreatedTimestamp: 1000},
+				{Name: "http_duration_seconds_sum", Labels: path, Value: 1.5, CreatedTimestamp: 1000},
+				{Name: "http_duration_seconds_count", Labels: path, Value: 2, CreatedTimestamp: 1000},
+				{Name: "http_duration_seconds_created", Labels: path, Value: 1},
+			},
+		},
+		{
+			Name:    "target",
+			Type:    "info",
+			Samples: []otlptranslator.Sample{{Name: "target_info", Labels: otlptranslator.Labels{{Name: "service.name", Value: "api"}}, Value: 1}},
+		},
+		{
+			Name:    "queue_size",
+			Type:    otlptranslator.PrometheusTypeGauge,
+			Samples: []otlptranslator.Sample{{Name: "queue_size", Value: math.NaN()}},
+		},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d families, want %d: %+v", len(got), len(want), got)
+	}
+	// NaN values cannot be compared.
+	if !math.IsNaN(got[3].Samples[0].Value) {
+		t.Errorf("queue_size = %v, want NaN", got[3].Samples[0].Value)
+	}
+	got[3].Samples[0].Value, want[3].Samples[0].Value = 0, 0
+	for i := range want {
+		if !reflect.DeepEqual(got[i], want[i]) {
+			t.Errorf("family %d:\ngot  %+v\nwant %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestParser_Text(t *testing.T) {
+	in := `# A comment.
+# HELP http_requests_total Number of \"HTTP\" requests.
+# TYPE http_requests_total counter
+http_requests_total{method="GET"} 42 2000
+http_requests_total{ method = "POST" , } 1
+
+# TYPE rpc_duration_seconds summary
+rpc_duration_seconds{quantile="0.5"} 0.1
+rpc_duration_seconds_sum 2
+rpc_duration_seconds_count 10
+# TYPE legacy untyped
+legacy -Inf
+orphan{"my.label"="x"} +Inf
+{"my.metric"} 1`
+	got, err := parseAll(NewParser(strings.NewReader(in), TextContentType))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Family{
+		{
+			Name: "http_requests_total",
+			Type: otlptranslator.PrometheusTypeCounter,
+			Help: `Number of \"HTTP\" requests.`,
+			Samples: []otlptranslator.Sample{
+				{Name: "http_requests_total", Labels: otlptranslator.Labels{{Name: "method", Value: "GET"}}, Value: 42, Timestamp: 2000},
+				{Name: "http_requests_total", Labels: otlptranslator.Labels{{Name: "method", Value: "POST"}}, Value: 1},
+			},
+		},
+		{
+			Name: "rpc_duration_seconds",
+			Type: otlptranslator.PrometheusTypeSummary,
+			Samples: []otlptranslator.Sample{
+				{Name: "rpc_duration_seconds", Labels: otlptranslator.Labels{{Name: "quantile", Value: "0.5"}}, Value: 0.1},
+				{Name: "rpc_duration_seconds_sum", Value: 2},
+				{Name: "rpc_duration_seconds_count", Value: 10},
+			},
+		},
+		{
+			Name:    "legacy",
+			Type:    otlptranslator.PrometheusTypeUnknown,
+			Samples: []otlptranslator.Sample{{Name: "legacy", Value: math.Inf(-1)}},
+		},
+		{
+			Name:    "orphan",
+			Type:    otlptranslator.PrometheusTypeUnknown,
+			Samples: []otlptranslator.Sample{{Name: "orphan", Labels: otlptranslator.Labels{{Name: "my.label", Value: "x"}}, Value: math.Inf(1)}},
+		},
+		{
+			Name:    "my.metric",
+			Type:    otlptranslator.PrometheusTypeUnknown,
+			Samples: []otlptranslator.Sample{{Name: "my.metric", Value: 1}},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got  %+v\nwant %+v", got, want)
+	}
+}
+
+// TestParser_RoundTrip checks that the writers' output parses back into the
+// written samples.
+func TestParser_RoundTrip(t *testing.T) {
+	builder := otlptranslator.SampleBuilder{CreatedSeries: true}
+	counter := otlptranslator.Metric{Name: "http.requests", Type: otlptranslator.MetricTypeMonotonicCounter, Description: "Requests,\nby \"method\"."}
+	histogram := otlptranslator.Metric{Name: "http.duration", Unit: "s", Type: otlptranslator.MetricTypeHistogram}
+
+	for _, strategy := range []otlptranslator.TranslationStrategyOption{otlptranslator.UnderscoreEscapingWithSuffixes, otlptranslator.NoTranslation} {
+		builder.MetricNamer = otlptranslator.NewMetricNamer("", strategy)
+		counterSamples, err := builder.BuildNumber(counter, nil, otlptranslator.NumberDataPoint{
+			Attributes:        []otlptranslator.Attribute{{Key: "http.method", Value: "GET"}},
+			StartTimeUnixNano: 1_000_000_000,
+			TimeUnixNano:      2_000_000_000,
+			Value:             42,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		histogramSamples, err := builder.BuildHistogram(histogram, nil, otlptranslator.HistogramDataPoint{
+			StartTimeUnixNano: 1_000_000_000,
+			TimeUnixNano:      2_000_000_000,
+			Count:             3,
+			Sum:               1.5,
+			BucketCounts:      []uint64{1, 2},
+			ExplicitBounds:    []float64{0.5},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, w := range []interface {
+			Add(otlptranslator.Metric, ...otlptranslator.Sample) error
+			WriteTo(io.Writer) (int64, error)
+			ContentType() string
+		}{NewTextWriter(builder.MetricNamer), NewOpenMetricsWriter(builder.MetricNamer)} {
+			if err := w.Add(counter, counterSamples...); err != nil {
+				t.Fatal(err)
+			}
+			if err := w.Add(histogram, histogramSamples...); err != nil {
+				t.Fatal(err)
+			}
+			var b strings.Builder
+			if _, err := w.WriteTo(&b); err != nil {
+				t.Fatal(err)
+			}
+			families, err := parseAll(NewParser(strings.NewReader(b.String()), w.ContentType()))
+			if err != nil {
+				t.Fatalf("%s: %v\n%s", w.ContentType(), err, b.String())
+			}
+			if len(families) != 2 {
+				t.Fatalf("%s: got %d families:\n%s", w.ContentType(), len(families), b.String())
+			}
+			openMetrics := strings.HasPrefix(w.ContentType(), "application/openmetrics-text")
+			for _, f := range families {
+				want := histogramSamples
+				if f.Type == otlptranslator.PrometheusTypeCounter {
+					want = counterSamples
+					if f.Help != counter.Description {
+						t.Errorf("%s: help = %q", w.ContentType(), f.Help)
+					}
+				}
+				var got []otlptranslator.Sample
+				for _, s := range f.Samples {
+					if strings.HasSuffix(s.Name, "_created") {
+						continue
+					}
+					if openMetrics && s.CreatedTimestamp != 1000 {
+						t.Errorf("%s: %s has created timestamp %d", w.ContentType(), s.Name, s.CreatedTimestamp)
+					}
+					got = append(got, otlptranslator.Sample{Name: s.Name, Labels: s.Labels, Value: s.Value, Timestamp: s.Timestamp})
+				}
+				var expected []otlptranslator.Sample
+				for _, s := range want {
+					if strings.HasSuffix(s.Name, "_created") {
+						continue
+					}
+					if openMetrics && f.Type == otlptranslator.PrometheusTypeCounter && !strings.HasSuffix(s.Name, "_total") {
+						// OpenMetrics counter samples always end with _total.
+						s.Name += "_total"
+					}
+					if len(s.Labels) == 0 {
+						s.Labels = nil
+					}
+					expected = append(expected, otlptranslator.Sample{Name: s.Name, Labels: s.Labels, Value: s.Value, Timestamp: s.Timestamp})
+				}
+				if !reflect.DeepEqual(got, expected) {
+					t.Errorf("%s %s:\ngot  %+v\nwant %+v", w.ContentType(), f.Name, got, expected)
+				}
+			}
+		}
+	}
+}
+
+func TestParser_Errors(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		openMetrics bool
+		in          string
+		wantErr     string
+	}{
+		{name: "missing EOF", openMetrics: true, in: "up 1\n", wantErr: "exposition: line 1: missing # EOF"},
+		{name: "content after EOF", openMetrics: true, in: "# EOF\nup 1\n", wantErr: "exposition: line 2: content after # EOF"},
+		{name: "blank line", openMetrics: true, in: "up 1\n\n# EOF\n", wantErr: "exposition: line 2: unexpected blank line"},
+		{name: "comment", openMetrics: true, in: "# hello\n# EOF\n", wantErr: `exposition: line 1: invalid comment "# hello"`},
+		{name: "untyped in OpenMetrics", openMetrics: true, in: "# TYPE up untyped\n", wantErr: `exposition: line 1: invalid type "untyped"`},
+		{name: "invalid type", in: "# TYPE up info\n", wantErr: `exposition: line 1: invalid type "info"`},
+		{name: "duplicate TYPE", in: "# TYPE up gauge\n# TYPE up gauge\n", wantErr: `exposition: line 2: duplicate TYPE line for "up"`},
+		{name: "metadata after samples", in: "# TYPE up gauge\nup 1\n# HELP up Help.\n", wantErr: `exposition: line 3: HELP line for "up" after its samples`},
+		{name: "invalid value", in: "up one\n", wantErr: `exposition: line 1: invalid value "one"`},
+		{name: "invalid timestamp", in: "up 1 1.5\n", wantErr: `exposition: line 1: invalid timestamp "1.5"`},
+		{name: "missing value", in: "up\n", wantErr: `exposition: line 1: invalid sample "up"`},
+		{name: "unterminated label value", in: `up{a="1} 1`, wantErr: "exposition: line 1: unterminated quoted string"},
+		{name: "missing label value", in: `up{a} 1`, wantErr: `exposition: line 1: missing value for label "a"`},
+		{name: "duplicate label", in: `up{a="1",a="2"} 1`, wantErr: `exposition: line 1: duplicate label "a"`},
+		{name: "missing name", in: `{a="1"} 1`, wantErr: "exposition: line 1: missing metric name"},
+		{name: "exemplar in text", in: `up 1 # {a="1"} 1`, wantErr: "exposition: line 1: exemplars are only supported in OpenMetrics"},
+		{name: "invalid exemplar", openMetrics: true, in: "up_total 1 # 1\n", wantErr: `exposition: line 1: invalid exemplar "1"`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p := NewTextParser(strings.NewReader(tc.in))
+			if tc.openMetrics {
+				p = NewOpenMetricsParser(strings.NewReader(tc.in))
+			}
+			_, err := parseAll(p)
+			if err == nil || err.Error() != tc.wantErr {
+				t.Errorf("error = %v, want %s", err, tc.wantErr)
+			}
+		})
+	}
+}
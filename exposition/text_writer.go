@@ -0,0 +1,215 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exposition
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/otlptranslator"
+)
+
+const (
+	// TextContentType is the content type of the Prometheus text format.
+	TextContentType = "text/plain; version=0.0.4; charset=utf-8"
+	// TextUTF8ContentType is the content type of the Prometheus text format
+	// with quoted UTF-8 metric and label names.
+	TextUTF8ContentType = "text/plain; version=1.0.0; charset=utf-8; escaping=allow-utf-8"
+)
+
+// family is a metric family being collected by a writer.
+type family struct {
+	name     string
+	metadata otlptranslator.Metadata
+	samples  []otlptranslator.Sample
+}
+
+// TextWriter renders translated OTLP samples in the Prometheus text
+// exposition format.
+//
+// Samples are grouped into metric families named after MetricNamer.Build.
+// Families are written sorted by name, each with its `# HELP` line, unless
+// the help is empty, and its `# TYPE` line. Samples are written in the order
+// they were added. When the MetricNamer allows UTF-8, as with the
+// NoTranslation and NoUTF8EscapingWithSuffixes strategies, metric and label
+// names that are not valid under the classic scheme are quoted.
+//
+// The text format has no created series nor staleness markers: `_created`
+// samples and samples holding a StaleNaN are skipped.
+//
+// Example usage:
+//
+//	w := exposition.NewTextWriter(otlptranslator.NewMetricNamer("", otlptranslator.UnderscoreEscapingWithSuffixes))
+//	if err := w.Add(metric, samples...); err != nil {
+//		// handle err
+//	}
+//	_, err := w.WriteTo(os.Stdout)
+type TextWriter struct {
+	namer    otlptranslator.MetricNamer
+	metadata otlptranslator.MetadataBuilder
+	families map[string]*family
+}
+
+// NewTextWriter creates a TextWriter naming families with the given
+// MetricNamer.
+func NewTextWriter(namer otlptranslator.MetricNamer) *TextWriter {
+	return &TextWriter{
+		namer:    namer,
+		metadata: newMetadataBuilder(namer),
+		families: map[string]*family{},
+	}
+}
+
+// ContentType returns the HTTP content type of the written exposition.
+func (w *TextWriter) ContentType() string {
+	if w.namer.UTF8Allowed {
+		return TextUTF8ContentType
+	}
+	return TextContentType
+}
+
+// Add adds the samples of a metric, as built by otlptranslator.SampleBuilder,
+// to the family named after the metric, described by the metric description.
+// It returns an error if a sample does not belong to the family, or if the
+// family was already added with a different type.
+func (w *TextWriter) Add(metric otlptranslator.Metric, samples ...otlptranslator.Sample) error {
+	md, err := w.metadata.Build(metric)
+	if err != nil {
+		return err
+	}
+	f, _, err := addFamily(w.families, md.FamilyName, md)
+	if err != nil {
+		return err
+	}
+	created, err := w.namer.BuildCreated(metric)
+	if err != nil {
+		return err
+	}
+	for _, s := range samples {
+		switch {
+		case s.Name == created:
+			continue
+		case !belongsToFamily(f, s.Name):
+			return fmt.Errorf("sample %q does not belong to metric family %q", s.Name, f.name)
+		case otlptranslator.IsStaleNaN(s.Value):
+			continue
+		}
+		f.samples = append(f.samples, s)
+	}
+	return nil
+}
+
+// WriteTo writes all families to out.
+func (w *TextWriter) WriteTo(out io.Writer) (int64, error) {
+	var b strings.Builder
+	for _, f := range sortedFamilies(w.families) {
+		if f.metadata.Help != "" {
+			b.WriteString("# HELP ")
+			writeMetricName(&b, f.name)
+			b.WriteByte(' ')
+			b.WriteString(f.metadata.EscapedHelp())
+			b.WriteByte('\n')
+		}
+		b.WriteString("# TYPE ")
+		writeMetricName(&b, f.name)
+		b.WriteByte(' ')
+		b.WriteString(textType(f.metadata.Type))
+		b.WriteByte('\n')
+		for _, s := range f.samples {
+			writeSeries(&b, s.Name, s.Labels)
+			b.WriteString(formatFloat(s.Value))
+			if s.Timestamp != 0 {
+				b.WriteByte(' ')
+				b.WriteString(strconv.FormatInt(s.Timestamp, 10))
+			}
+			b.WriteByte('\n')
+		}
+	}
+	n, err := io.WriteString(out, b.String())
+	return int64(n), err
+}
+
+// newMetadataBuilder returns a MetadataBuilder translating units the same way
+// as names.
+func newMetadataBuilder(namer otlptranslator.MetricNamer) otlptranslator.MetadataBuilder {
+	return otlptranslator.MetadataBuilder{
+		MetricNamer: namer,
+		UnitNamer:   otlptranslator.UnitNamer{UTF8Allowed: namer.UTF8Allowed, Behavior: namer.Behavior},
+	}
+}
+
+// addFamily returns the family of the given name, creating it if needed, and
+// whether it was created. The help of an existing family is only set if it
+// was empty.
+func addFamily(families map[string]*family, name string, md otlptranslator.Metadata) (*family, bool, error) {
+	f, ok := families[name]
+	if !ok {
+		f = &family{name: name, metadata: md}
+		families[name] = f
+		return f, true, nil
+	}
+	if f.metadata.Type != md.Type {
+		return nil, false, fmt.Errorf("metric family %q added with type %s, then %s", name, f.metadata.Type, md.Type)
+	}
+	if f.metadata.Help == "" {
+		f.metadata.Help = md.Help
+	}
+	return f, false, nil
+}
+
+// belongsToFamily reports whether a sample with the given name is part of
+// the family in the Prometheus text format.
+func belongsToFamily(f *family, name string) bool {
+	if name == f.name {
+		return true
+	}
+	suffix, ok := strings.CutPrefix(name, f.name)
+	if !ok {
+		return false
+	}
+	switch f.metadata.Type {
+	case otlptranslator.PrometheusTypeHistogram, otlptranslator.PrometheusTypeGaugeHistogram:
+		return suffix == "_bucket" || suffix == "_sum" || suffix == "_count"
+	case otlptranslator.PrometheusTypeSummary:
+		return suffix == "_sum" || suffix == "_count"
+	default:
+		return false
+	}
+}
+
+func sortedFamilies(families map[string]*family) []*family {
+	res := make([]*family, 0, len(families))
+	for _, f := range families {
+		res = append(res, f)
+	}
+	slices.SortFunc(res, func(a, b *family) int {
+		return strings.Compare(a.name, b.name)
+	})
+	return res
+}
+
+// textType returns the type of a family in the Prometheus text format, which
+// has no gauge histograms.
+func textType(typ otlptranslator.PrometheusType) string {
+	switch typ {
+	case otlptranslator.PrometheusTypeCounter, otlptranslator.PrometheusTypeGauge,
+		otlptranslator.PrometheusTypeHistogram, otlptranslator.PrometheusTypeSummary:
+		return string(typ)
+	default:
+		return "untyped"
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exposition
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/otlptranslator"
+)
+
+func TestTextWriter(t *testing.T) {
+	counter := otlptranslator.Metric{Name: "http.requests", Type: otlptranslator.MetricTypeMonotonicCounter, Description: "Number of HTTP requests,\nby method \\ path."}
+	histogram := otlptranslator.Metric{Name: "http.duration", Unit: "s", Type: otlptranslator.MetricTypeHistogram, Description: "Duration of HTTP requests."}
+	gauge := otlptranslator.Metric{Name: "queue.size", Type: otlptranslator.MetricTypeGauge}
+
+	tests := []struct {
+		name     string
+		strategy otlptranslator.TranslationStrategyOption
+		want     string
+	}{
+		{
+			name:     "escaped names",
+			strategy: otlptranslator.UnderscoreEscapingWithSuffixes,
+			want: `# HELP http_duration_seconds Duration of HTTP requests.
+# TYPE http_duration_seconds histogram
+http_duration_seconds_bucket{http_method="GET",le="0.5"} 1 2000
+http_duration_seconds_bucket{http_method="GET",le="+Inf"} 2 2000
+http_duration_seconds_sum{http_method="GET"} 1.5 2000
+http_duration_seconds_count{http_method="GET"} 2 2000
+# HELP http_requests_total Number of HTTP requests,\nby method \\ path.
+# TYPE http_requests_total counter
+http_requests_total{http_method="GET",path="C:\\dir \"quoted\"\n"} 42 2000
+# TYPE queue_size gauge
+queue_size +Inf 2000
+`,
+		},
+		{
+			name:     "quoted UTF-8 names",
+			strategy: otlptranslator.NoUTF8EscapingWithSuffixes,
+			want: `# HELP "http.duration_seconds" Duration of HTTP requests.
+# TYPE "http.duration_seconds" histogram
+{"http.duration_seconds_bucket","http.method"="GET",le="0.5"} 1 2000
+{"http.duration_seconds_bucket","http.method"="GET",le="+Inf"} 2 2000
+{"http.duration_seconds_sum","http.method"="GET"} 1.5 2000
+{"http.duration_seconds_count","http.method"="GET"} 2 2000
+# HELP "http.requests_total" Number of HTTP requests,\nby method \\ path.
+# TYPE "http.requests_total" counter
+{"http.requests_total","http.method"="GET",path="C:\\dir \"quoted\"\n"} 42 2000
+# TYPE "queue.size" gauge
+{"queue.size"} +Inf 2000
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			namer := otlptranslator.NewMetricNamer("", tt.strategy)
+			builder := otlptranslator.SampleBuilder{
+				MetricNamer:   namer,
+				LabelNamer:    otlptranslator.LabelNamer{UTF8Allowed: namer.UTF8Allowed},
+				CreatedSeries: true,
+			}
+			w := NewTextWriter(namer)
+
+			samples, err := builder.BuildNumber(counter, nil, otlptranslator.NumberDataPoint{
+				Attributes: []otlptranslator.Attribute{
+					{Key: "http.method", Value: "GET"},
+					{Key: "path", Value: "C:\\dir \"quoted\"\n"},
+				},
+				StartTimeUnixNano: 1_000_000_000,
+				TimeUnixNano:      2_000_000_000,
+				Value:             42,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := w.Add(counter, samples...); err != nil {
+				t.Fatal(err)
+			}
+
+			samples, err = builder.BuildHistogram(histogram, nil, otlptranslator.HistogramDataPoint{
+				Attributes:     []otlptranslator.Attribute{{Key: "http.method", Value: "GET"}},
+				TimeUnixNano:   2_000_000_000,
+				Count:          2,
+				Sum:            1.5,
+				BucketCounts:   []uint64{1, 1},
+				ExplicitBounds: []float64{0.5},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := w.Add(histogram, samples...); err != nil {
+				t.Fatal(err)
+			}
+
+			samples, err = builder.BuildNumber(gauge, nil, otlptranslator.NumberDataPoint{TimeUnixNano: 2_000_000_000, Value: math.Inf(1)})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := w.Add(gauge, samples...); err != nil {
+				t.Fatal(err)
+			}
+			stale, err := builder.BuildNumber(gauge, otlptranslator.Labels{{Name: "stale", Value: "true"}}, otlptranslator.NumberDataPoint{
+				TimeUnixNano: 2_000_000_000,
+				Flags:        otlptranslator.DataPointFlagNoRecordedValue,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := w.Add(gauge, stale...); err != nil {
+				t.Fatal(err)
+			}
+
+			var out strings.Builder
+			if _, err := w.WriteTo(&out); err != nil {
+				t.Fatal(err)
+			}
+			if out.String() != tt.want {
+				t.Errorf("WriteTo() =\n%s\nwant\n%s", out.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestTextWriter_Errors(t *testing.T) {
+	namer := otlptranslator.NewMetricNamer("", otlptranslator.UnderscoreEscapingWithSuffixes)
+	w := NewTextWriter(namer)
+	gauge := otlptranslator.Metric{Name: "foo", Type: otlptranslator.MetricTypeGauge}
+
+	err := w.Add(gauge, otlptranslator.Sample{Name: "bar"})
+	if err == nil || err.Error() != `sample "bar" does not belong to metric family "foo"` {
+		t.Errorf("Add() error = %v", err)
+	}
+	err = w.Add(gauge, otlptranslator.Sample{Name: "foo_sum"})
+	if err == nil {
+		t.Error("Add() accepted a _sum sample for a gauge")
+	}
+	err = w.Add(otlptranslator.Metric{Name: "foo", Type: otlptranslator.MetricTypeSummary})
+	if err == nil || err.Error() != `metric family "foo" added with type gauge, then summary` {
+		t.Errorf("Add() error = %v", err)
+	}
+}
@@ -0,0 +1,168 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlptranslator_test
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/prometheus/otlptranslator"
+	"github.com/prometheus/otlptranslator/conformance"
+	"github.com/prometheus/otlptranslator/internal/legacy"
+)
+
+var fuzzStrategies = []otlptranslator.TranslationStrategyOption{
+	otlptranslator.UnderscoreEscapingWithSuffixes,
+	otlptranslator.UnderscoreEscapingWithoutSuffixes,
+	otlptranslator.NoUTF8EscapingWithSuffixes,
+	otlptranslator.NoTranslation,
+}
+
+// behaviorNamer is the Namer of MetricNamer and LabelNamer pinned to a
+// behavior version, unlike conformance.Reference, which has the default rules.
+type behaviorNamer otlptranslator.BehaviorVersion
+
+func (b behaviorNamer) MetricName(metric otlptranslator.Metric, namespace string, strategy otlptranslator.TranslationStrategyOption) (string, error) {
+	namer := otlptranslator.NewMetricNamer(namespace, strategy)
+	namer.Behavior = otlptranslator.BehaviorVersion(b)
+	return namer.Build(metric)
+}
+
+func (b behaviorNamer) LabelName(attribute string, strategy otlptranslator.TranslationStrategyOption) (string, error) {
+	namer := otlptranslator.LabelNamer{UTF8Allowed: !strategy.ShouldEscape(), Behavior: otlptranslator.BehaviorVersion(b)}
+	return namer.Build(attribute)
+}
+
+// FuzzMetricNamer_Build checks the properties of conformance.CheckMetricName
+// under the BehaviorV2 rules. The default rules only translate metric names
+// deterministically, to valid names when the namespace is valid: translating
+// a translated name can change it again, as "_total" becomes "_total_total".
+func FuzzMetricNamer_Build(f *testing.F) {
+	cases, err := conformance.MetricCases()
+	if err != nil {
+		f.Fatal(err)
+	}
+	for _, c := range cases {
+		metric, _ := c.Metric.Convert()
+		f.Add(metric.Name, metric.Unit, uint8(metric.Type), uint8(metric.Temporality), c.Namespace, uint8(strategyIndex(c.Strategy)))
+	}
+	f.Fuzz(func(t *testing.T, name, unit string, typ, temporality uint8, namespace string, strategy uint8) {
+		if !utf8.ValidString(name) || !utf8.ValidString(unit) || !utf8.ValidString(namespace) {
+			return
+		}
+		metric := otlptranslator.Metric{
+			Name:        name,
+			Unit:        unit,
+			Type:        otlptranslator.MetricType(typ % uint8(otlptranslator.MetricTypeSummary+1)),
+			Temporality: otlptranslator.Temporality(temporality % uint8(otlptranslator.TemporalityCumulative+1)),
+		}
+		s := fuzzStrategies[int(strategy)%len(fuzzStrategies)]
+		if err := conformance.CheckMetricName(behaviorNamer(otlptranslator.BehaviorV2), metric, namespace, s); err != nil {
+			t.Error(err)
+		}
+
+		namer := otlptranslator.NewMetricNamer(namespace, s)
+		got, err := namer.Build(metric)
+		if twice, err2 := namer.Build(metric); twice != got || (err == nil) != (err2 == nil) {
+			t.Errorf("%+v.Build(%+v) is not deterministic: %q, %v, then %q, %v", namer, metric, got, err, twice, err2)
+		}
+		switch {
+		case err != nil:
+		case !utf8.ValidString(got):
+			t.Errorf("%+v.Build(%+v) = %q, which is not valid UTF-8", namer, metric, got)
+		case !namer.UTF8Allowed && (namespace == "" || legacy.IsMetricName(namespace)) && !legacy.IsMetricName(got):
+			t.Errorf("%+v.Build(%+v) = %q, which is not a valid legacy Prometheus name", namer, metric, got)
+		}
+	})
+}
+
+// FuzzLabelNamer_Build checks the properties of conformance.CheckLabelName,
+// under the default and the BehaviorV2 rules, and that the fast path of labels returned unchanged agrees with the full
+// escaping, under all options.
+func FuzzLabelNamer_Build(f *testing.F) {
+	cases, err := conformance.LabelCases()
+	if err != nil {
+		f.Fatal(err)
+	}
+	for _, c := range cases {
+		f.Add(c.Attribute, uint8(strategyIndex(c.Strategy)), false, false)
+	}
+	f.Add("__reserved__", uint8(0), true, true)
+	f.Add("_label", uint8(0), false, true)
+	f.Fuzz(func(t *testing.T, attribute string, strategy uint8, preserveMultipleUnderscores, underscoreLabelSanitization bool) {
+		s := fuzzStrategies[int(strategy)%len(fuzzStrategies)]
+		for _, namer := range []conformance.Namer{conformance.Reference, behaviorNamer(otlptranslator.BehaviorV2)} {
+			if err := conformance.CheckLabelName(namer, attribute, s); err != nil {
+				t.Error(err)
+			}
+		}
+
+		if attribute == "" || !otlptranslator.CanFastPathLabel(attribute, preserveMultipleUnderscores, underscoreLabelSanitization) {
+			return
+		}
+		namer := otlptranslator.LabelNamer{
+			PreserveMultipleUnderscores: preserveMultipleUnderscores,
+			UnderscoreLabelSanitization: underscoreLabelSanitization,
+		}
+		if got, err := namer.BuildEscaped(attribute); err != nil || got != attribute {
+			t.Errorf("%+v: label %q takes the fast path, but escaping returns %q, %v", namer, attribute, got, err)
+		}
+	})
+}
+
+// FuzzUnitNamer_Build checks that escaped unit names are valid metric name
+// suffixes, without consecutive underscores under the BehaviorV2 rules, which
+// trim the underscores of UTF-8 unit names too. Unlike metric names, unit
+// names are not built again: the UCUM units they are built from are not
+// Prometheus units, as "s" becomes "seconds".
+func FuzzUnitNamer_Build(f *testing.F) {
+	for _, unit := range []string{"", "1", "s", "ms", "By/s", "1/s", "{request}", "{packet}/s", "%", "Cel", "m/s2", " By / min ", "_s_", "a/b/c"} {
+		f.Add(unit, false)
+		f.Add(unit, true)
+	}
+	f.Fuzz(func(t *testing.T, unit string, utf8Allowed bool) {
+		if !utf8.ValidString(unit) {
+			return
+		}
+		for _, behavior := range []otlptranslator.BehaviorVersion{"", otlptranslator.BehaviorV2} {
+			namer := otlptranslator.UnitNamer{UTF8Allowed: utf8Allowed, Behavior: behavior}
+			v2 := behavior == otlptranslator.BehaviorV2
+			got := namer.Build(unit)
+			if (!utf8Allowed || v2) && (strings.HasPrefix(got, "_") || strings.HasSuffix(got, "_")) {
+				t.Errorf("%+v.Build(%q) = %q, which has leading or trailing underscores", namer, unit, got)
+			}
+			if !utf8Allowed && strings.ContainsFunc(got, func(r rune) bool {
+				return (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') && (r < '0' || r > '9') && r != '_' && r != ':'
+			}) {
+				t.Errorf("%+v.Build(%q) = %q, which is not a valid metric name suffix", namer, unit, got)
+			}
+			if !utf8Allowed && v2 && strings.Contains(got, "__") {
+				t.Errorf("%+v.Build(%q) = %q, which has consecutive underscores", namer, unit, got)
+			}
+			if twice := namer.Build(unit); twice != got {
+				t.Errorf("%+v.Build(%q) is not deterministic: %q, then %q", namer, unit, got, twice)
+			}
+		}
+	})
+}
+
+func strategyIndex(s otlptranslator.TranslationStrategyOption) int {
+	for i, strategy := range fuzzStrategies {
+		if strategy == s {
+			return i
+		}
+	}
+	return 0
+}
@@ -0,0 +1,285 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package diff computes the differences between two texts, either as a
+// unified diff of their lines, in the format read by patch and git apply, or
+// as the edits of their words.
+//
+// Differences are computed with the Myers algorithm, after trimming the
+// elements common to the start and end of both texts, which is enough for the
+// small edits of migrated files.
+package diff
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// contextLines is the number of unchanged lines around every change.
+const contextLines = 3
+
+type opKind byte
+
+const (
+	opEqual  opKind = ' '
+	opDelete opKind = '-'
+	opInsert opKind = '+'
+)
+
+// op is an element of an edit script, with the indexes of its element in the
+// old and new sequences.
+type op struct {
+	kind opKind
+	a, b int
+}
+
+// Unified returns the unified diff turning a into b, labeled with their
+// names, or an empty string if they are equal.
+func Unified(nameA, nameB, a, b string) string {
+	if a == b {
+		return ""
+	}
+	linesA, linesB := splitLines(a), splitLines(b)
+	ops := diffSeq(linesA, linesB)
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", nameA, nameB)
+	for start := 0; start < len(ops); {
+		// Find the next change and the end of its hunk.
+		first := start
+		for first < len(ops) && ops[first].kind == opEqual {
+			first++
+		}
+		if first == len(ops) {
+			break
+		}
+		hunkStart := max(first-contextLines, start)
+		end := first
+		for end < len(ops) {
+			if ops[end].kind != opEqual {
+				end++
+				continue
+			}
+			run := end
+			for run < len(ops) && ops[run].kind == opEqual {
+				run++
+			}
+			if run == len(ops) || run-end > 2*contextLines {
+				end = min(end+contextLines, run)
+				break
+			}
+			end = run
+		}
+		writeHunk(&out, linesA, linesB, ops, hunkStart, end)
+		start = end
+	}
+	return out.String()
+}
+
+// writeHunk writes the hunk made of ops[start:end].
+func writeHunk(out *strings.Builder, a, b []string, ops []op, start, end int) {
+	lineA, lineB := 1, 1
+	for _, o := range ops[:start] {
+		if o.kind != opInsert {
+			lineA++
+		}
+		if o.kind != opDelete {
+			lineB++
+		}
+	}
+	var countA, countB int
+	for _, o := range ops[start:end] {
+		if o.kind != opInsert {
+			countA++
+		}
+		if o.kind != opDelete {
+			countB++
+		}
+	}
+	// Empty ranges start at the line before them.
+	if countA == 0 {
+		lineA--
+	}
+	if countB == 0 {
+		lineB--
+	}
+	fmt.Fprintf(out, "@@ -%s +%s @@\n", hunkRange(lineA, countA), hunkRange(lineB, countB))
+	for _, o := range ops[start:end] {
+		line := b[o.b]
+		if o.kind == opDelete {
+			line = a[o.a]
+		}
+		out.WriteByte(byte(o.kind))
+		out.WriteString(line)
+		if !strings.HasSuffix(line, "\n") {
+			out.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+}
+
+func hunkRange(line, count int) string {
+	if count == 1 {
+		return fmt.Sprint(line)
+	}
+	return fmt.Sprintf("%d,%d", line, count)
+}
+
+// splitLines splits s into lines, keeping their line feeds.
+func splitLines(s string) []string {
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// Edit replaces the bytes in [Start, End) of the old text with Text.
+type Edit struct {
+	Start, End int
+	Text       string
+}
+
+// Words returns the edits turning a into b, sorted and not overlapping. The
+// texts are compared word by word, words being runs of letters, digits,
+// underscores, colons and dots, so that edits replace whole names.
+func Words(a, b string) []Edit {
+	wordsA, wordsB := splitWords(a), splitWords(b)
+	var edits []Edit
+	offsetA := 0
+	var cur *Edit
+	for _, o := range diffSeq(wordsA, wordsB) {
+		if o.kind == opEqual {
+			offsetA += len(wordsA[o.a])
+			cur = nil
+			continue
+		}
+		if cur == nil {
+			edits = append(edits, Edit{Start: offsetA, End: offsetA})
+			cur = &edits[len(edits)-1]
+		}
+		if o.kind == opDelete {
+			offsetA += len(wordsA[o.a])
+			cur.End = offsetA
+		} else {
+			cur.Text += wordsB[o.b]
+		}
+	}
+	return edits
+}
+
+// splitWords splits s into words and single other bytes.
+func splitWords(s string) []string {
+	var words []string
+	for i := 0; i < len(s); {
+		j := i + 1
+		if isWordByte(s[i]) {
+			for j < len(s) && isWordByte(s[j]) {
+				j++
+			}
+		}
+		words = append(words, s[i:j])
+		i = j
+	}
+	return words
+}
+
+func isWordByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == ':' || c == '.' || c >= 0x80
+}
+
+// diffSeq returns the shortest edit script turning a into b.
+func diffSeq(a, b []string) []op {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	ops := make([]op, 0, len(a)+len(b))
+	for i := range prefix {
+		ops = append(ops, op{opEqual, i, i})
+	}
+	for _, o := range myers(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]) {
+		ops = append(ops, op{o.kind, o.a + prefix, o.b + prefix})
+	}
+	for i := suffix; i > 0; i-- {
+		ops = append(ops, op{opEqual, len(a) - i, len(b) - i})
+	}
+	return ops
+}
+
+// myers implements the Myers diff algorithm, keeping the furthest reaching
+// paths of every step to backtrack the edit script.
+func myers(a, b []string) []op {
+	n, m := len(a), len(b)
+	offset := n + m
+	v := make([]int, 2*offset+2)
+	var trace [][]int
+	for d := 0; d <= n+m; d++ {
+		trace = append(trace, append([]int(nil), v...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || k != d && v[offset+k-1] < v[offset+k+1] {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return backtrack(n, m, trace, offset)
+			}
+		}
+	}
+	return nil
+}
+
+func backtrack(n, m int, trace [][]int, offset int) []op {
+	var ops []op
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		k := x - y
+		var prevK int
+		if k == -d || k != d && v[offset+k-1] < v[offset+k+1] {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := v[offset+prevK]
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			x--
+			y--
+			ops = append(ops, op{opEqual, x, y})
+		}
+		if d > 0 {
+			if x == prevX {
+				y--
+				ops = append(ops, op{opInsert, x, y})
+			} else {
+				x--
+				ops = append(ops, op{opDelete, x, y})
+			}
+		}
+	}
+	slices.Reverse(ops)
+	return ops
+}
@@ -0,0 +1,114 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUnified(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		a, b string
+		want string
+	}{
+		{
+			name: "equal",
+			a:    "a\nb\n",
+			b:    "a\nb\n",
+		},
+		{
+			name: "single change with context",
+			a:    "1\n2\n3\n4\n5\n6\n7\n8\n",
+			b:    "1\n2\n3\n4\nfive\n6\n7\n8\n",
+			want: "--- a\n+++ b\n@@ -2,7 +2,7 @@\n 2\n 3\n 4\n-5\n+five\n 6\n 7\n 8\n",
+		},
+		{
+			name: "separate hunks",
+			a:    "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n",
+			b:    "one\n2\n3\n4\n5\n6\n7\n8\n9\nten\n",
+			want: "--- a\n+++ b\n@@ -1,4 +1,4 @@\n-1\n+one\n 2\n 3\n 4\n@@ -7,4 +7,4 @@\n 7\n 8\n 9\n-10\n+ten\n",
+		},
+		{
+			name: "insertion and deletion",
+			a:    "a\nb\nc\n",
+			b:    "a\nc\nd\n",
+			want: "--- a\n+++ b\n@@ -1,3 +1,3 @@\n a\n-b\n c\n+d\n",
+		},
+		{
+			name: "from empty",
+			a:    "",
+			b:    "a\n",
+			want: "--- a\n+++ b\n@@ -0,0 +1 @@\n+a\n",
+		},
+		{
+			name: "missing final line feed",
+			a:    "a\nb",
+			b:    "a\nc",
+			want: "--- a\n+++ b\n@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+c\n\\ No newline at end of file\n",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Unified("a", "b", tc.a, tc.b); got != tc.want {
+				t.Errorf("Unified() =\n%s\nwant\n%s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestWords(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		a, b string
+		want []Edit
+	}{
+		{
+			name: "equal",
+			a:    "sum(rate(foo_total[5m]))",
+			b:    "sum(rate(foo_total[5m]))",
+		},
+		{
+			name: "replaced names",
+			a:    `sum by (http_route) (rate(requests_total[5m]))`,
+			b:    `sum by ("http.route") (rate(requests[5m]))`,
+			want: []Edit{
+				{Start: 8, End: 18, Text: `"http.route"`},
+				{Start: 26, End: 40, Text: "requests"},
+			},
+		},
+		{
+			name: "insertion",
+			a:    "foo",
+			b:    "{foo}",
+			want: []Edit{{Start: 0, End: 0, Text: "{"}, {Start: 3, End: 3, Text: "}"}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := Words(tc.a, tc.b)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("Words() = %+v, want %+v", got, tc.want)
+			}
+			// Applying the edits must give b.
+			res, last := "", 0
+			for _, e := range got {
+				res += tc.a[last:e.Start] + e.Text
+				last = e.End
+			}
+			if res += tc.a[last:]; res != tc.b {
+				t.Errorf("applied edits = %q, want %q", res, tc.b)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package legacy checks names against the classic Prometheus name scheme,
+// which names are escaped to unless UTF-8 is allowed.
+package legacy
+
+// IsMetricName reports whether name is valid under the classic Prometheus
+// metric name scheme, [a-zA-Z_:][a-zA-Z0-9_:]*.
+func IsMetricName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		if !IsLabelRune(r, i) && r != ':' {
+			return false
+		}
+	}
+	return true
+}
+
+// IsLabelName reports whether name is valid under the classic Prometheus
+// label name scheme, [a-zA-Z_][a-zA-Z0-9_]*.
+func IsLabelName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		if !IsLabelRune(r, i) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsLabelRune reports whether r is valid at byte index i of a classic
+// Prometheus label name.
+func IsLabelRune(r rune, i int) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || r == '_' || (r >= '0' && r <= '9' && i > 0)
+}
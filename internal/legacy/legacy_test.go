@@ -0,0 +1,41 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package legacy
+
+import "testing"
+
+func TestIsMetricName(t *testing.T) {
+	tests := []struct {
+		name       string
+		wantMetric bool
+		wantLabel  bool
+	}{
+		{name: "", wantMetric: false, wantLabel: false},
+		{name: "http_requests_total", wantMetric: true, wantLabel: true},
+		{name: "_0", wantMetric: true, wantLabel: true},
+		{name: "job:requests:rate5m", wantMetric: true, wantLabel: false},
+		{name: ":", wantMetric: true, wantLabel: false},
+		{name: "0abc", wantMetric: false, wantLabel: false},
+		{name: "http.requests", wantMetric: false, wantLabel: false},
+		{name: "température", wantMetric: false, wantLabel: false},
+	}
+	for _, tt := range tests {
+		if got := IsMetricName(tt.name); got != tt.wantMetric {
+			t.Errorf("IsMetricName(%q) = %t, want %t", tt.name, got, tt.wantMetric)
+		}
+		if got := IsLabelName(tt.name); got != tt.wantLabel {
+			t.Errorf("IsLabelName(%q) = %t, want %t", tt.name, got, tt.wantLabel)
+		}
+	}
+}
@@ -0,0 +1,288 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package migrate maps the metric and label names built by a translation
+// strategy to the ones built by another, for the migrators of rule files and
+// dashboards.
+package migrate
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/prometheus/otlptranslator"
+)
+
+// Options configures a Mapper.
+type Options struct {
+	From, To   otlptranslator.TranslationStrategyOption
+	Namespace  string
+	Metrics    []otlptranslator.Metric
+	Attributes []string
+}
+
+// Ambiguity explains why a name could not be mapped with certainty.
+type Ambiguity struct {
+	// Candidates are the possible new names, if known.
+	Candidates []string
+	Reason     string
+}
+
+// Mapper maps the names built by a translation strategy to the ones built
+// by another.
+//
+// Names of known metrics and attributes are mapped exactly. Other names are
+// reverse translated: the type and unit of a metric are guessed from its
+// suffixes, assuming OpenTelemetry names do not hold suffixes themselves, and
+// its name is built again with the new strategy. Names holding a colon, as
+// the ones of recording rules, are left unchanged.
+//
+// Names are ambiguous when several known metrics or attributes map to
+// different names, when suffixes must be added to unknown metrics, whose
+// type and unit cannot be guessed, and when escaping is disabled: underscores
+// of unknown names may then stand for escaped characters, such as dots.
+type Mapper struct {
+	from, to               otlptranslator.TranslationStrategyOption
+	oldMetrics, newMetrics otlptranslator.MetricNamer
+	oldLabels, newLabels   otlptranslator.LabelNamer
+	metrics                map[string][]string
+	labels                 map[string][]string
+}
+
+// seriesSuffixes are the suffixes of the series of histograms, summaries
+// and counters, along with the metric types having them. Summaries are named
+// like histograms.
+var seriesSuffixes = []struct {
+	suffix string
+	types  []otlptranslator.MetricType
+}{
+	{"_bucket", []otlptranslator.MetricType{otlptranslator.MetricTypeHistogram}},
+	{"_sum", []otlptranslator.MetricType{otlptranslator.MetricTypeHistogram}},
+	{"_count", []otlptranslator.MetricType{otlptranslator.MetricTypeHistogram}},
+	{"_created", []otlptranslator.MetricType{otlptranslator.MetricTypeMonotonicCounter, otlptranslator.MetricTypeHistogram}},
+	{"", []otlptranslator.MetricType{otlptranslator.MetricTypeMonotonicCounter, otlptranslator.MetricTypeGauge}},
+}
+
+// wellKnownLabels are labels added by Prometheus rather than translated from
+// attributes.
+var wellKnownLabels = []string{
+	"job", "instance",
+	otlptranslator.BucketLabel, otlptranslator.QuantileLabel,
+	otlptranslator.ScopeNameLabelKey, otlptranslator.ScopeVersionLabelKey,
+}
+
+// NewMapper creates a Mapper. It returns an error if a strategy is unknown,
+// or if a known metric or attribute cannot be translated.
+func NewMapper(opts Options) (*Mapper, error) {
+	for _, s := range []otlptranslator.TranslationStrategyOption{opts.From, opts.To} {
+		switch s {
+		case otlptranslator.UnderscoreEscapingWithSuffixes, otlptranslator.UnderscoreEscapingWithoutSuffixes,
+			otlptranslator.NoUTF8EscapingWithSuffixes, otlptranslator.NoTranslation:
+		default:
+			return nil, fmt.Errorf("unknown translation strategy %q", s)
+		}
+	}
+	m := &Mapper{
+		from:       opts.From,
+		to:         opts.To,
+		oldMetrics: otlptranslator.NewMetricNamer(opts.Namespace, opts.From),
+		newMetrics: otlptranslator.NewMetricNamer(opts.Namespace, opts.To),
+		oldLabels:  otlptranslator.LabelNamer{UTF8Allowed: !opts.From.ShouldEscape()},
+		newLabels:  otlptranslator.LabelNamer{UTF8Allowed: !opts.To.ShouldEscape()},
+		metrics:    map[string][]string{},
+		labels:     map[string][]string{},
+	}
+	for _, metric := range opts.Metrics {
+		for _, suffix := range metricSuffixes(metric.Type) {
+			oldName, err := buildName(m.oldMetrics, metric, suffix)
+			if err != nil {
+				return nil, fmt.Errorf("metric %q: %w", metric.Name, err)
+			}
+			newName, err := buildName(m.newMetrics, metric, suffix)
+			if err != nil {
+				return nil, fmt.Errorf("metric %q: %w", metric.Name, err)
+			}
+			if !slices.Contains(m.metrics[oldName], newName) {
+				m.metrics[oldName] = append(m.metrics[oldName], newName)
+			}
+		}
+	}
+	for _, attr := range opts.Attributes {
+		oldName, err := m.oldLabels.Build(attr)
+		if err != nil {
+			return nil, fmt.Errorf("attribute %q: %w", attr, err)
+		}
+		newName, err := m.newLabels.Build(attr)
+		if err != nil {
+			return nil, fmt.Errorf("attribute %q: %w", attr, err)
+		}
+		if !slices.Contains(m.labels[oldName], newName) {
+			m.labels[oldName] = append(m.labels[oldName], newName)
+		}
+	}
+	return m, nil
+}
+
+// metricSuffixes returns the suffixes of the series of a metric type, the
+// empty one standing for the metric itself.
+func metricSuffixes(t otlptranslator.MetricType) []string {
+	switch t.Kind() {
+	case otlptranslator.MetricKindHistogram:
+		return []string{"", "_bucket", "_sum", "_count", "_created"}
+	case otlptranslator.MetricKindExponentialHistogram, otlptranslator.MetricKindSummary:
+		return []string{"", "_sum", "_count", "_created"}
+	case otlptranslator.MetricKindSum:
+		return []string{"", "_created"}
+	default:
+		return []string{""}
+	}
+}
+
+// buildName builds the name of a series of a metric.
+func buildName(namer otlptranslator.MetricNamer, metric otlptranslator.Metric, suffix string) (string, error) {
+	switch suffix {
+	case "":
+		return namer.Build(metric)
+	case "_created":
+		return namer.BuildCreated(metric)
+	default:
+		name, err := namer.Build(metric)
+		return name + suffix, err
+	}
+}
+
+// escapingDisabled reports whether the migration disables escaping, in which
+// case underscores of the old names may stand for other characters.
+func (m *Mapper) escapingDisabled() bool {
+	return m.from.ShouldEscape() && !m.to.ShouldEscape()
+}
+
+// reverseMetric guesses the name of the metric an old name was built from,
+// and returns it along with the name the new strategy gives to the metric.
+func (m *Mapper) reverseMetric(name string) (string, string, bool) {
+	if !m.from.ShouldAddSuffixes() {
+		// Without suffixes, the old name is the metric name.
+		metric := otlptranslator.Metric{Name: m.trimNamespace(name), Type: otlptranslator.MetricTypeGauge}
+		newName, err := m.newMetrics.Build(metric)
+		return metric.Name, newName, err == nil && !m.to.ShouldAddSuffixes()
+	}
+	for _, s := range seriesSuffixes {
+		base, ok := strings.CutSuffix(name, s.suffix)
+		if !ok || base == "" {
+			continue
+		}
+		for _, metric := range m.guessMetrics(base, s.suffix, s.types) {
+			if oldName, err := buildName(m.oldMetrics, metric, s.suffix); err != nil || oldName != name {
+				continue
+			}
+			if newName, err := buildName(m.newMetrics, metric, s.suffix); err == nil {
+				return metric.Name, newName, true
+			}
+		}
+	}
+	return "", "", false
+}
+
+// guessMetrics returns the metrics of the given types an old name with
+// suffixes may have been built from, the most likely first.
+func (m *Mapper) guessMetrics(base, suffix string, types []otlptranslator.MetricType) []otlptranslator.Metric {
+	var metrics []otlptranslator.Metric
+	base = m.trimNamespace(base)
+	for _, t := range types {
+		name := base
+		if t == otlptranslator.MetricTypeMonotonicCounter && suffix != "_created" {
+			var ok bool
+			if name, ok = strings.CutSuffix(name, "_total"); !ok {
+				continue
+			}
+		}
+		if before, unit, ok := otlptranslator.CutUnitSuffix(name); ok {
+			unitNamer := otlptranslator.UnitNamer{}
+			metrics = append(metrics, otlptranslator.Metric{Name: before, Unit: unitNamer.Parse(unit), Type: t})
+		}
+		metrics = append(metrics, otlptranslator.Metric{Name: name, Type: t})
+	}
+	return metrics
+}
+
+// trimNamespace removes the namespace prefix of a name.
+func (m *Mapper) trimNamespace(name string) string {
+	if m.oldMetrics.Namespace == "" {
+		return name
+	}
+	return strings.TrimPrefix(name, m.oldMetrics.Namespace+"_")
+}
+
+// MetricName maps a metric name. Ambiguous names having a single candidate
+// are mapped to it, and other ones are left unchanged.
+func (m *Mapper) MetricName(name string) (string, *Ambiguity) {
+	if strings.Contains(name, ":") {
+		return name, nil
+	}
+	if names, ok := m.metrics[name]; ok {
+		if len(names) > 1 {
+			return name, &Ambiguity{Candidates: names, Reason: "known metrics translated to this name have different new names"}
+		}
+		return names[0], nil
+	}
+	metricName, newName, ok := m.reverseMetric(name)
+	switch {
+	case !ok && !m.from.ShouldAddSuffixes() && m.to.ShouldAddSuffixes():
+		return name, &Ambiguity{Reason: "the metric is unknown, and its type and unit are needed to add suffixes"}
+	case !ok:
+		return name, &Ambiguity{Reason: "the metric is unknown, and its name cannot be reverse translated"}
+	case m.escapingDisabled() && strings.Contains(metricName, "_"):
+		return newName, &Ambiguity{Candidates: []string{newName}, Reason: "the metric is unknown, and underscores of its name may stand for escaped characters"}
+	}
+	return newName, nil
+}
+
+// LabelName maps a label name. Ambiguous names are left unchanged.
+func (m *Mapper) LabelName(name string) (string, *Ambiguity) {
+	if names, ok := m.KnownLabel(name); ok {
+		if len(names) > 1 {
+			return name, &Ambiguity{Candidates: names, Reason: "known attributes translated to this label have different new names"}
+		}
+		return names[0], nil
+	}
+	if m.escapingDisabled() && strings.Contains(name, "_") && !slices.Contains(wellKnownLabels, name) {
+		return name, &Ambiguity{Reason: "the attribute is unknown, and underscores of its name may stand for escaped characters"}
+	}
+	newName, err := m.newLabels.Build(name)
+	if err != nil {
+		return name, &Ambiguity{Reason: err.Error()}
+	}
+	return newName, nil
+}
+
+// KnownLabel returns the new names of a label translated from known
+// attributes, and whether there is any.
+func (m *Mapper) KnownLabel(name string) ([]string, bool) {
+	names, ok := m.labels[name]
+	return names, ok
+}
+
+// IsLegacyLabelName reports whether name is valid under the classic
+// Prometheus label name scheme, [a-zA-Z_][a-zA-Z0-9_]*.
+func IsLegacyLabelName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r == '_' || r >= '0' && r <= '9' && i > 0) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,129 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migrate
+
+import (
+	"testing"
+
+	"github.com/prometheus/otlptranslator"
+)
+
+func TestMapper_MetricName(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		opts      Options
+		in        string
+		want      string
+		ambiguous bool
+	}{
+		{
+			name: "counter",
+			opts: Options{From: otlptranslator.UnderscoreEscapingWithSuffixes, To: otlptranslator.UnderscoreEscapingWithoutSuffixes},
+			in:   "requests_total",
+			want: "requests",
+		},
+		{
+			name: "histogram bucket with unit",
+			opts: Options{From: otlptranslator.UnderscoreEscapingWithSuffixes, To: otlptranslator.UnderscoreEscapingWithoutSuffixes},
+			in:   "latency_seconds_bucket",
+			want: "latency_bucket",
+		},
+		{
+			name: "created series of a counter",
+			opts: Options{From: otlptranslator.UnderscoreEscapingWithSuffixes, To: otlptranslator.UnderscoreEscapingWithoutSuffixes},
+			in:   "requests_created",
+			want: "requests_created",
+		},
+		{
+			name: "ratio",
+			opts: Options{From: otlptranslator.UnderscoreEscapingWithSuffixes, To: otlptranslator.NoTranslation},
+			in:   "utilization_ratio",
+			want: "utilization",
+		},
+		{
+			name: "namespace",
+			opts: Options{From: otlptranslator.UnderscoreEscapingWithSuffixes, To: otlptranslator.UnderscoreEscapingWithoutSuffixes, Namespace: "app"},
+			in:   "app_memory_bytes",
+			want: "app_memory",
+		},
+		{
+			name: "recording rule",
+			opts: Options{From: otlptranslator.UnderscoreEscapingWithSuffixes, To: otlptranslator.NoTranslation},
+			in:   "job:requests_total:rate5m",
+			want: "job:requests_total:rate5m",
+		},
+		{
+			name: "known metric",
+			opts: Options{
+				From:    otlptranslator.UnderscoreEscapingWithSuffixes,
+				To:      otlptranslator.NoUTF8EscapingWithSuffixes,
+				Metrics: []otlptranslator.Metric{{Name: "http.server.active_requests", Type: otlptranslator.MetricTypeNonMonotonicCounter}},
+			},
+			in:   "http_server_active_requests",
+			want: "http.server.active_requests",
+		},
+		{
+			name:      "escaped characters",
+			opts:      Options{From: otlptranslator.UnderscoreEscapingWithSuffixes, To: otlptranslator.NoUTF8EscapingWithSuffixes},
+			in:        "http_server_active_requests",
+			want:      "http_server_active_requests",
+			ambiguous: true,
+		},
+		{
+			name:      "suffixes of unknown metrics",
+			opts:      Options{From: otlptranslator.NoTranslation, To: otlptranslator.UnderscoreEscapingWithSuffixes},
+			in:        "requests",
+			want:      "requests",
+			ambiguous: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			m, err := NewMapper(tc.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, a := m.MetricName(tc.in)
+			if got != tc.want || (a != nil) != tc.ambiguous {
+				t.Errorf("MetricName(%q) = %q, %+v, want %q, ambiguous %t", tc.in, got, a, tc.want, tc.ambiguous)
+			}
+		})
+	}
+}
+
+func TestMapper_LabelName(t *testing.T) {
+	m, err := NewMapper(Options{
+		From:       otlptranslator.UnderscoreEscapingWithSuffixes,
+		To:         otlptranslator.NoTranslation,
+		Attributes: []string{"http.route", "http_route", "service.name"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		in        string
+		want      string
+		ambiguous bool
+	}{
+		{in: "service_name", want: "service.name"},
+		{in: "http_route", want: "http_route", ambiguous: true},
+		{in: "otel_scope_name", want: "otel_scope_name"},
+		{in: "pod_name", want: "pod_name", ambiguous: true},
+		{in: "job", want: "job"},
+	} {
+		got, a := m.LabelName(tc.in)
+		if got != tc.want || (a != nil) != tc.ambiguous {
+			t.Errorf("LabelName(%q) = %q, %+v, want %q, ambiguous %t", tc.in, got, a, tc.want, tc.ambiguous)
+		}
+	}
+}
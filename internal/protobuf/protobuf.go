@@ -0,0 +1,289 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package protobuf implements the subset of the protobuf wire format needed
+// to encode Prometheus remote-write requests and decode OTLP requests,
+// without depending on a protobuf runtime.
+//
+// Encoding functions append to a byte slice, the same way strconv.Append*
+// functions do. Field-level helpers follow proto3 semantics and omit fields
+// holding their zero value.
+package protobuf
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Type is a protobuf wire type.
+type Type int8
+
+// Wire types. Groups are not supported.
+const (
+	// VarintType is used for int32, int64, uint32, uint64, sint32, sint64,
+	// bool and enum fields.
+	VarintType Type = 0
+	// Fixed64Type is used for fixed64, sfixed64 and double fields.
+	Fixed64Type Type = 1
+	// BytesType is used for string, bytes, embedded message and packed
+	// repeated fields.
+	BytesType Type = 2
+	// Fixed32Type is used for fixed32, sfixed32 and float fields.
+	Fixed32Type Type = 5
+)
+
+var (
+	// ErrTruncated is returned when the input ends in the middle of a field.
+	ErrTruncated = errors.New("protobuf: truncated input")
+	// ErrOverflow is returned for varints longer than 64 bits.
+	ErrOverflow = errors.New("protobuf: varint overflows 64 bits")
+)
+
+// AppendVarint appends v as a base-128 varint.
+func AppendVarint(b []byte, v uint64) []byte {
+	for v >= 0x80 {
+		b = append(b, byte(v)|0x80)
+		v >>= 7
+	}
+	return append(b, byte(v))
+}
+
+// AppendTag appends the key of a field.
+func AppendTag(b []byte, num int, typ Type) []byte {
+	return AppendVarint(b, uint64(num)<<3|uint64(typ))
+}
+
+// AppendFixed64 appends v as a little-endian 64-bit value.
+func AppendFixed64(b []byte, v uint64) []byte {
+	return append(b,
+		byte(v), byte(v>>8), byte(v>>16), byte(v>>24),
+		byte(v>>32), byte(v>>40), byte(v>>48), byte(v>>56))
+}
+
+// AppendBytes appends v prefixed with its length.
+func AppendBytes(b, v []byte) []byte {
+	b = AppendVarint(b, uint64(len(v)))
+	return append(b, v...)
+}
+
+// AppendString appends s prefixed with its length.
+func AppendString(b []byte, s string) []byte {
+	b = AppendVarint(b, uint64(len(s)))
+	return append(b, s...)
+}
+
+// EncodeZigZag maps a signed integer to an unsigned one, as done for sint32
+// and sint64 fields.
+func EncodeZigZag(v int64) uint64 {
+	return uint64(v<<1) ^ uint64(v>>63)
+}
+
+// DecodeZigZag reverses EncodeZigZag.
+func DecodeZigZag(v uint64) int64 {
+	return int64(v>>1) ^ -int64(v&1)
+}
+
+// AppendVarintField appends a varint field, unless v is zero. Signed int32 and
+// int64 values are encoded by converting them to uint64.
+func AppendVarintField(b []byte, num int, v uint64) []byte {
+	if v == 0 {
+		return b
+	}
+	b = AppendTag(b, num, VarintType)
+	return AppendVarint(b, v)
+}
+
+// AppendDoubleField appends a double field, unless f is positive zero.
+func AppendDoubleField(b []byte, num int, f float64) []byte {
+	bits := math.Float64bits(f)
+	if bits == 0 {
+		return b
+	}
+	b = AppendTag(b, num, Fixed64Type)
+	return AppendFixed64(b, bits)
+}
+
+// AppendStringField appends a string field, unless s is empty.
+func AppendStringField(b []byte, num int, s string) []byte {
+	if s == "" {
+		return b
+	}
+	b = AppendTag(b, num, BytesType)
+	return AppendString(b, s)
+}
+
+// AppendMessageField appends an embedded message field, even if msg is empty.
+func AppendMessageField(b []byte, num int, msg []byte) []byte {
+	b = AppendTag(b, num, BytesType)
+	return AppendBytes(b, msg)
+}
+
+// AppendPackedVarintsField appends a packed repeated varint field, unless vs
+// is empty.
+func AppendPackedVarintsField(b []byte, num int, vs []uint64) []byte {
+	if len(vs) == 0 {
+		return b
+	}
+	var packed []byte
+	for _, v := range vs {
+		packed = AppendVarint(packed, v)
+	}
+	return AppendMessageField(b, num, packed)
+}
+
+// Field is a decoded field. Depending on the wire type, its value is held by
+// Varint, Fixed or Bytes. Bytes aliases the decoded input.
+type Field struct {
+	Num    int
+	Type   Type
+	Varint uint64
+	Fixed  uint64
+	Bytes  []byte
+}
+
+// Double returns the value of a fixed64 field as a float64.
+func (f Field) Double() float64 {
+	return math.Float64frombits(f.Fixed)
+}
+
+// ConsumeVarint decodes a varint at the start of b, and returns it with the
+// number of bytes read.
+func ConsumeVarint(b []byte) (uint64, int, error) {
+	var v uint64
+	for i := 0; i < len(b); i++ {
+		if i == 10 {
+			return 0, 0, ErrOverflow
+		}
+		c := b[i]
+		if i == 9 && c > 1 {
+			return 0, 0, ErrOverflow
+		}
+		v |= uint64(c&0x7f) << (7 * i)
+		if c < 0x80 {
+			return v, i + 1, nil
+		}
+	}
+	return 0, 0, ErrTruncated
+}
+
+// ConsumeField decodes the field at the start of b, and returns it with the
+// number of bytes read.
+func ConsumeField(b []byte) (Field, int, error) {
+	key, n, err := ConsumeVarint(b)
+	if err != nil {
+		return Field{}, 0, err
+	}
+	f := Field{Num: int(key >> 3), Type: Type(key & 7)}
+	if key>>3 == 0 || key>>3 > math.MaxInt32 {
+		return Field{}, 0, fmt.Errorf("protobuf: invalid field number %d", key>>3)
+	}
+	b = b[n:]
+	switch f.Type {
+	case VarintType:
+		v, m, err := ConsumeVarint(b)
+		if err != nil {
+			return Field{}, 0, err
+		}
+		f.Varint = v
+		return f, n + m, nil
+	case Fixed64Type:
+		if len(b) < 8 {
+			return Field{}, 0, ErrTruncated
+		}
+		for i := 7; i >= 0; i-- {
+			f.Fixed = f.Fixed<<8 | uint64(b[i])
+		}
+		return f, n + 8, nil
+	case Fixed32Type:
+		if len(b) < 4 {
+			return Field{}, 0, ErrTruncated
+		}
+		for i := 3; i >= 0; i-- {
+			f.Fixed = f.Fixed<<8 | uint64(b[i])
+		}
+		return f, n + 4, nil
+	case BytesType:
+		l, m, err := ConsumeVarint(b)
+		if err != nil {
+			return Field{}, 0, err
+		}
+		if l > uint64(len(b)-m) {
+			return Field{}, 0, ErrTruncated
+		}
+		f.Bytes = b[m : m+int(l)]
+		return f, n + m + int(l), nil
+	default:
+		return Field{}, 0, fmt.Errorf("protobuf: unsupported wire type %d for field %d", f.Type, f.Num)
+	}
+}
+
+// ForEachField calls fn for every field of the message b, stopping at the
+// first error.
+func ForEachField(b []byte, fn func(Field) error) error {
+	for len(b) > 0 {
+		f, n, err := ConsumeField(b)
+		if err != nil {
+			return err
+		}
+		if err := fn(f); err != nil {
+			return err
+		}
+		b = b[n:]
+	}
+	return nil
+}
+
+// AppendVarints appends to vs the values of a repeated varint field, which can
+// be either packed or not.
+func AppendVarints(vs []uint64, f Field) ([]uint64, error) {
+	switch f.Type {
+	case VarintType:
+		return append(vs, f.Varint), nil
+	case BytesType:
+		for b := f.Bytes; len(b) > 0; {
+			v, n, err := ConsumeVarint(b)
+			if err != nil {
+				return nil, err
+			}
+			vs = append(vs, v)
+			b = b[n:]
+		}
+		return vs, nil
+	default:
+		return nil, fmt.Errorf("protobuf: wire type %d for repeated varint field %d", f.Type, f.Num)
+	}
+}
+
+// AppendFixed64s appends to vs the values of a repeated fixed64 or double
+// field, which can be either packed or not.
+func AppendFixed64s(vs []uint64, f Field) ([]uint64, error) {
+	switch f.Type {
+	case Fixed64Type:
+		return append(vs, f.Fixed), nil
+	case BytesType:
+		if len(f.Bytes)%8 != 0 {
+			return nil, ErrTruncated
+		}
+		for b := f.Bytes; len(b) > 0; b = b[8:] {
+			var v uint64
+			for i := 7; i >= 0; i-- {
+				v = v<<8 | uint64(b[i])
+			}
+			vs = append(vs, v)
+		}
+		return vs, nil
+	default:
+		return nil, fmt.Errorf("protobuf: wire type %d for repeated fixed64 field %d", f.Type, f.Num)
+	}
+}
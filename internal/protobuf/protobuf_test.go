@@ -0,0 +1,116 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protobuf
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestRoundTrip(t *testing.T) {
+	var b []byte
+	b = AppendVarintField(b, 1, 150)
+	b = AppendVarintField(b, 2, 0) // Omitted.
+	b = AppendDoubleField(b, 3, 1.5)
+	b = AppendStringField(b, 4, "testing")
+	b = AppendPackedVarintsField(b, 5, []uint64{3, 270, 86942})
+	b = AppendMessageField(b, 6, nil)
+	b = AppendVarintField(b, 7, EncodeZigZag(-2))
+	b = AppendVarintField(b, 8, uint64(math.MaxUint64))
+
+	// Examples from https://protobuf.dev/programming-guides/encoding/.
+	want := []byte{
+		0x08, 0x96, 0x01,
+		0x19, 0, 0, 0, 0, 0, 0, 0xf8, 0x3f,
+		0x22, 0x07, 't', 'e', 's', 't', 'i', 'n', 'g',
+		0x2a, 0x06, 0x03, 0x8e, 0x02, 0x9e, 0xa7, 0x05,
+		0x32, 0x00,
+		0x38, 0x03,
+		0x40, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01,
+	}
+	if !reflect.DeepEqual(b, want) {
+		t.Fatalf("encoded % x, want % x", b, want)
+	}
+
+	var (
+		fields []int
+		packed []uint64
+	)
+	err := ForEachField(b, func(f Field) error {
+		fields = append(fields, f.Num)
+		var err error
+		switch f.Num {
+		case 1:
+			if f.Varint != 150 {
+				t.Errorf("field 1 = %d", f.Varint)
+			}
+		case 3:
+			if f.Double() != 1.5 {
+				t.Errorf("field 3 = %g", f.Double())
+			}
+		case 4:
+			if string(f.Bytes) != "testing" {
+				t.Errorf("field 4 = %q", f.Bytes)
+			}
+		case 5:
+			packed, err = AppendVarints(packed, f)
+		case 7:
+			if DecodeZigZag(f.Varint) != -2 {
+				t.Errorf("field 7 = %d", DecodeZigZag(f.Varint))
+			}
+		case 8:
+			if f.Varint != math.MaxUint64 {
+				t.Errorf("field 8 = %d", f.Varint)
+			}
+		}
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{1, 3, 4, 5, 6, 7, 8}; !reflect.DeepEqual(fields, want) {
+		t.Errorf("fields = %v, want %v", fields, want)
+	}
+	if want := []uint64{3, 270, 86942}; !reflect.DeepEqual(packed, want) {
+		t.Errorf("packed = %v, want %v", packed, want)
+	}
+}
+
+func TestConsumeField_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []byte
+		want  error
+	}{
+		{name: "truncated varint", input: []byte{0x08, 0x96}, want: ErrTruncated},
+		{name: "truncated fixed64", input: []byte{0x19, 0, 0}, want: ErrTruncated},
+		{name: "truncated bytes", input: []byte{0x22, 0x07, 't'}, want: ErrTruncated},
+		{name: "overflow", input: []byte{0x08, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x02}, want: ErrOverflow},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := ConsumeField(tt.input); !errors.Is(err, tt.want) {
+				t.Errorf("ConsumeField() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+	if _, _, err := ConsumeField([]byte{0x0b}); err == nil {
+		t.Error("ConsumeField() accepted a group")
+	}
+	if _, _, err := ConsumeField([]byte{0x00}); err == nil {
+		t.Error("ConsumeField() accepted field number 0")
+	}
+}
@@ -0,0 +1,223 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package snappy implements the Snappy block format, used to compress
+// remote-write requests, and the Snappy framing format:
+// https://github.com/google/snappy/blob/main/format_description.txt
+// https://github.com/google/snappy/blob/main/framing_format.txt
+//
+// The encoder is a simple greedy one: it favors a small, dependency-free
+// implementation over the compression ratio.
+package snappy
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+)
+
+const (
+	// maxBlockSize is the size of the blocks the input is split into, so that
+	// copy offsets always fit in two bytes.
+	maxBlockSize = 65536
+	// minMatchBlockSize is the size under which blocks are written as a single
+	// literal.
+	minMatchBlockSize = 17
+	tableBits         = 14
+
+	tagLiteral = 0x00
+	tagCopy1   = 0x01
+	tagCopy2   = 0x02
+	tagCopy4   = 0x03
+)
+
+// ErrCorrupt is returned when decoding invalid Snappy data.
+var ErrCorrupt = errors.New("snappy: corrupt input")
+
+// Encode returns the Snappy block encoding of src.
+func Encode(src []byte) []byte {
+	dst := binary.AppendUvarint(make([]byte, 0, len(src)+len(src)/6+16), uint64(len(src)))
+	for len(src) > 0 {
+		block := src[:min(len(src), maxBlockSize)]
+		dst = encodeBlock(dst, block)
+		src = src[len(block):]
+	}
+	return dst
+}
+
+func encodeBlock(dst, src []byte) []byte {
+	if len(src) < minMatchBlockSize {
+		return appendLiteral(dst, src)
+	}
+	// table holds the position, plus one, of the last occurrence of every
+	// hashed 4-byte sequence.
+	var table [1 << tableBits]int32
+	lit := 0
+	for s := 0; s+4 <= len(src); {
+		cur := binary.LittleEndian.Uint32(src[s:])
+		h := (cur * 0x1e35a7bd) >> (32 - tableBits)
+		candidate := int(table[h]) - 1
+		table[h] = int32(s + 1)
+		if candidate < 0 || binary.LittleEndian.Uint32(src[candidate:]) != cur {
+			s++
+			continue
+		}
+		dst = appendLiteral(dst, src[lit:s])
+		start := s
+		s, candidate = s+4, candidate+4
+		for s < len(src) && src[s] == src[candidate] {
+			s++
+			candidate++
+		}
+		dst = appendCopy(dst, s-candidate, s-start)
+		lit = s
+	}
+	return appendLiteral(dst, src[lit:])
+}
+
+func appendLiteral(dst, lit []byte) []byte {
+	n := len(lit) - 1
+	switch {
+	case n < 0:
+		return dst
+	case n < 60:
+		dst = append(dst, byte(n)<<2|tagLiteral)
+	case n < 1<<8:
+		dst = append(dst, 60<<2|tagLiteral, byte(n))
+	default:
+		dst = append(dst, 61<<2|tagLiteral, byte(n), byte(n>>8))
+	}
+	return append(dst, lit...)
+}
+
+// appendCopy appends copy elements with two-byte offsets, each copying at
+// most 64 bytes.
+func appendCopy(dst []byte, offset, length int) []byte {
+	for length > 0 {
+		n := min(length, 64)
+		dst = append(dst, byte(n-1)<<2|tagCopy2, byte(offset), byte(offset>>8))
+		length -= n
+	}
+	return dst
+}
+
+// Decode returns the decoded form of the Snappy block src.
+func Decode(src []byte) ([]byte, error) {
+	n, read := binary.Uvarint(src)
+	if read <= 0 || n > uint64(len(src))*255 {
+		return nil, ErrCorrupt
+	}
+	src = src[read:]
+	dst := make([]byte, 0, n)
+	for len(src) > 0 {
+		tag := src[0]
+		var length, offset int
+		switch tag & 3 {
+		case tagLiteral:
+			length = int(tag >> 2)
+			src = src[1:]
+			if length >= 60 {
+				extra := length - 59
+				if len(src) < extra {
+					return nil, ErrCorrupt
+				}
+				length = 0
+				for i := extra - 1; i >= 0; i-- {
+					length = length<<8 | int(src[i])
+				}
+				src = src[extra:]
+			}
+			length++
+			if length > len(src) || len(dst)+length > int(n) {
+				return nil, ErrCorrupt
+			}
+			dst = append(dst, src[:length]...)
+			src = src[length:]
+			continue
+		case tagCopy1:
+			if len(src) < 2 {
+				return nil, ErrCorrupt
+			}
+			length = 4 + int(tag>>2)&7
+			offset = int(tag&0xe0)<<3 | int(src[1])
+			src = src[2:]
+		case tagCopy2:
+			if len(src) < 3 {
+				return nil, ErrCorrupt
+			}
+			length = 1 + int(tag>>2)
+			offset = int(binary.LittleEndian.Uint16(src[1:]))
+			src = src[3:]
+		case tagCopy4:
+			if len(src) < 5 {
+				return nil, ErrCorrupt
+			}
+			length = 1 + int(tag>>2)
+			offset = int(binary.LittleEndian.Uint32(src[1:]))
+			src = src[5:]
+		}
+		if offset <= 0 || offset > len(dst) || len(dst)+length > int(n) {
+			return nil, ErrCorrupt
+		}
+		// Copies may overlap with the bytes they produce.
+		for range length {
+			dst = append(dst, dst[len(dst)-offset])
+		}
+	}
+	if len(dst) != int(n) {
+		return nil, ErrCorrupt
+	}
+	return dst, nil
+}
+
+const (
+	chunkCompressed   = 0x00
+	chunkUncompressed = 0x01
+	chunkStreamID     = 0xff
+)
+
+var (
+	streamID   = []byte("sNaPpY")
+	crc32Table = crc32.MakeTable(crc32.Castagnoli)
+)
+
+// EncodeFramed returns the Snappy framing format encoding of src: a stream
+// identifier followed by compressed, or uncompressed if smaller, chunks of at
+// most 64KiB of src.
+func EncodeFramed(src []byte) []byte {
+	dst := appendChunkHeader(nil, chunkStreamID, len(streamID))
+	dst = append(dst, streamID...)
+	for len(src) > 0 {
+		chunk := src[:min(len(src), maxBlockSize)]
+		src = src[len(chunk):]
+
+		typ, body := byte(chunkCompressed), Encode(chunk)
+		if len(body) >= len(chunk) {
+			typ, body = chunkUncompressed, chunk
+		}
+		dst = appendChunkHeader(dst, typ, 4+len(body))
+		dst = binary.LittleEndian.AppendUint32(dst, maskedCRC(chunk))
+		dst = append(dst, body...)
+	}
+	return dst
+}
+
+func appendChunkHeader(dst []byte, typ byte, length int) []byte {
+	return append(dst, typ, byte(length), byte(length>>8), byte(length>>16))
+}
+
+// maskedCRC returns the masked CRC-32C checksum of b, as stored in chunks.
+func maskedCRC(b []byte) uint32 {
+	c := crc32.Checksum(b, crc32Table)
+	return (c>>15 | c<<17) + 0xa282ead8
+}
@@ -0,0 +1,102 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snappy
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestEncode(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "empty", input: "", want: "00"},
+		{name: "short literal", input: "hello", want: "051068656c6c6f"},
+		{
+			name:  "copy",
+			input: strings.Repeat("abcd", 8),
+			// Length 32, literal "abcd", then a copy of 28 bytes at offset 4.
+			want: "200c61626364" + "6e0400",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Encode([]byte(tt.input))
+			if hex.EncodeToString(got) != tt.want {
+				t.Errorf("Encode() = %x, want %s", got, tt.want)
+			}
+			decoded, err := Decode(got)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(decoded) != tt.input {
+				t.Errorf("Decode() = %q, want %q", decoded, tt.input)
+			}
+		})
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, size := range []int{16, 17, 100, 65535, 65536, 65537, 300_000} {
+		input := make([]byte, size)
+		// Mix compressible and random data.
+		for i := range input {
+			if i%1000 < 500 {
+				input[i] = byte(i % 7)
+			} else {
+				input[i] = byte(r.Intn(256))
+			}
+		}
+		decoded, err := Decode(Encode(input))
+		if err != nil {
+			t.Fatalf("size %d: %s", size, err)
+		}
+		if !bytes.Equal(decoded, input) {
+			t.Fatalf("size %d: round trip mismatch", size)
+		}
+	}
+}
+
+func TestDecode_Corrupt(t *testing.T) {
+	for _, input := range []string{
+		"",
+		"05",             // Missing literal.
+		"0510616263",     // Truncated literal.
+		"060461620e0500", // Copy with an offset beyond the output.
+		"0a0c616263640e0400",
+	} {
+		b, _ := hex.DecodeString(input)
+		if _, err := Decode(b); !errors.Is(err, ErrCorrupt) {
+			t.Errorf("Decode(%s) error = %v, want ErrCorrupt", input, err)
+		}
+	}
+}
+
+func TestEncodeFramed(t *testing.T) {
+	got := EncodeFramed([]byte("hello"))
+	// Stream identifier, then an uncompressed chunk as "hello" does not
+	// compress: type 0x01, length 9, masked CRC-32C, data.
+	want := "ff060000734e61507059" + "01090000" + "bb1f1c19" + "68656c6c6f"
+	if hex.EncodeToString(got) != want {
+		t.Errorf("EncodeFramed() = %x, want %s", got, want)
+	}
+}
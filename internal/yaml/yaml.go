@@ -0,0 +1,847 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package yaml parses the subset of YAML used by configuration files, such
+// as Prometheus rule files, keeping the position of every node in the source
+// so that the files can be edited in place.
+package yaml
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Kind is the kind of a YAML node.
+type Kind int
+
+// Kinds of nodes.
+const (
+	NullNode Kind = iota
+	ScalarNode
+	MappingNode
+	SequenceNode
+	// OpaqueNode is a node using flow collections, anchors, aliases or tags,
+	// which are skipped and left unchanged.
+	OpaqueNode
+)
+
+// Style is the style of a YAML scalar.
+type Style int
+
+// Styles of scalars.
+const (
+	PlainStyle Style = iota
+	SingleQuotedStyle
+	DoubleQuotedStyle
+	LiteralStyle
+	FoldedStyle
+)
+
+// Node is a YAML node, along with its position in the source.
+type Node struct {
+	Kind Kind
+	// Start and End are the offsets of the node in the source.
+	Start, End int
+
+	// Scalars.
+	Style Style
+	Value string
+	// Offsets holds the source offset of every byte of the value, plus the
+	// offset of its end, so that edits of the value can be applied to the
+	// source.
+	Offsets []int
+
+	// Mappings.
+	Keys, Values []*Node
+
+	// Sequences.
+	Items []*Node
+}
+
+// Get returns the value of a mapping key, or nil.
+func (n *Node) Get(key string) *Node {
+	if n == nil || n.Kind != MappingNode {
+		return nil
+	}
+	for i, k := range n.Keys {
+		if k.Kind == ScalarNode && k.Value == key {
+			return n.Values[i]
+		}
+	}
+	return nil
+}
+
+// Scalar returns the value of a scalar node, or an empty string.
+func (n *Node) Scalar() string {
+	if n == nil || n.Kind != ScalarNode {
+		return ""
+	}
+	return n.Value
+}
+
+// yamlParser parses block mappings and sequences, and plain, quoted, literal
+// and folded scalars. Flow collections, anchors, aliases and tags are kept as
+// opaque nodes. Only one document is supported.
+type yamlParser struct {
+	src        []byte
+	pos        int
+	lineStarts []int
+}
+
+// Parse parses a YAML document, and returns its root node.
+func Parse(src []byte) (*Node, error) {
+	p := &yamlParser{src: src, lineStarts: []int{0}}
+	for i, c := range src {
+		if c == '\n' {
+			p.lineStarts = append(p.lineStarts, i+1)
+		}
+	}
+	if err := p.skipToContent(); err != nil {
+		return nil, err
+	}
+	if p.atDocumentMarker("---") {
+		p.pos += 3
+		if !p.atLineEnd() {
+			return nil, p.errorf(p.pos, "content after the document start marker is not supported")
+		}
+		if err := p.skipToContent(); err != nil {
+			return nil, err
+		}
+	}
+	root := &Node{Kind: NullNode, Start: p.pos, End: p.pos}
+	if !p.eof() && !p.atDocumentMarker("...") {
+		var err error
+		if root, err = p.parseNode(-1, false); err != nil {
+			return nil, err
+		}
+		if err := p.skipToContent(); err != nil {
+			return nil, err
+		}
+	}
+	if p.atDocumentMarker("...") {
+		p.pos += 3
+		if err := p.skipToContent(); err != nil {
+			return nil, err
+		}
+	}
+	switch {
+	case p.eof():
+		return root, nil
+	case p.atDocumentMarker("---"):
+		return nil, p.errorf(p.pos, "multiple documents are not supported")
+	default:
+		return nil, p.errorf(p.pos, "unexpected content")
+	}
+}
+
+// line returns the 1-based line number of a source offset.
+func (p *yamlParser) line(pos int) int {
+	return sort.Search(len(p.lineStarts), func(i int) bool { return p.lineStarts[i] > pos })
+}
+
+// column returns the 0-based column of a source offset.
+func (p *yamlParser) column(pos int) int {
+	return pos - p.lineStarts[p.line(pos)-1]
+}
+
+func (p *yamlParser) errorf(pos int, format string, args ...any) error {
+	return fmt.Errorf("line %d: %s", p.line(pos), fmt.Sprintf(format, args...))
+}
+
+func (p *yamlParser) eof() bool {
+	return p.pos >= len(p.src)
+}
+
+func (p *yamlParser) peekAt(pos int) byte {
+	if pos >= len(p.src) {
+		return 0
+	}
+	return p.src[pos]
+}
+
+// isBlank reports whether c separates tokens: a space, a tab, a line feed or
+// the end of the source.
+func isBlank(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == 0
+}
+
+// skipSpaces skips the spaces and tabs of the current line.
+func (p *yamlParser) skipSpaces() {
+	for !p.eof() && (p.src[p.pos] == ' ' || p.src[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+// atLineEnd skips spaces, and reports whether the rest of the line is empty
+// or a comment.
+func (p *yamlParser) atLineEnd() bool {
+	p.skipSpaces()
+	c := p.peekAt(p.pos)
+	return c == 0 || c == '\n' || c == '#'
+}
+
+// skipToContent skips whitespace, comments and empty lines, up to the next
+// token.
+func (p *yamlParser) skipToContent() error {
+	for !p.eof() {
+		switch p.src[p.pos] {
+		case ' ':
+			p.pos++
+		case '\t':
+			if p.onlySpacesBefore(p.pos) && !p.emptyLine(p.pos) {
+				return p.errorf(p.pos, "tabs are not allowed in indentation")
+			}
+			p.pos++
+		case '\n':
+			p.pos++
+		case '#':
+			for !p.eof() && p.src[p.pos] != '\n' {
+				p.pos++
+			}
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+// onlySpacesBefore reports whether the line of pos only holds whitespace
+// before it.
+func (p *yamlParser) onlySpacesBefore(pos int) bool {
+	for i := p.lineStarts[p.line(pos)-1]; i < pos; i++ {
+		if p.src[i] != ' ' && p.src[i] != '\t' {
+			return false
+		}
+	}
+	return true
+}
+
+// emptyLine reports whether the line of pos only holds whitespace or a
+// comment from pos.
+func (p *yamlParser) emptyLine(pos int) bool {
+	for ; pos < len(p.src) && p.src[pos] != '\n'; pos++ {
+		switch p.src[pos] {
+		case ' ', '\t':
+		case '#':
+			return true
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// atDocumentMarker reports whether a document marker starts at the current
+// position.
+func (p *yamlParser) atDocumentMarker(marker string) bool {
+	return p.column(p.pos) == 0 && strings.HasPrefix(string(p.src[p.pos:min(p.pos+3, len(p.src))]), marker) && isBlank(p.peekAt(p.pos+3))
+}
+
+// atSequenceEntry reports whether a sequence entry starts at the current
+// position.
+func (p *yamlParser) atSequenceEntry() bool {
+	return p.peekAt(p.pos) == '-' && isBlank(p.peekAt(p.pos+1))
+}
+
+// parseNode parses the node starting at the current position, whose parent
+// is indented by parentIndent columns. Inline nodes follow a mapping key on
+// the same line, and cannot be block collections.
+func (p *yamlParser) parseNode(parentIndent int, inline bool) (*Node, error) {
+	switch c := p.src[p.pos]; {
+	case p.atSequenceEntry():
+		if inline {
+			return nil, p.errorf(p.pos, "block sequence entries are not allowed here")
+		}
+		return p.parseSequence(p.column(p.pos))
+	case c == '[' || c == '{':
+		return p.parseFlow()
+	case c == '&' || c == '*' || c == '!':
+		return p.parseOpaque(parentIndent)
+	case c == '|' || c == '>':
+		return p.parseBlockScalar(parentIndent)
+	case p.atMappingKey():
+		if inline {
+			return nil, p.errorf(p.pos, "mapping values are not allowed here")
+		}
+		return p.parseMapping(p.column(p.pos))
+	case c == '\'' || c == '"':
+		n, err := p.parseQuoted()
+		if err != nil {
+			return nil, err
+		}
+		if !p.atLineEnd() {
+			return nil, p.errorf(p.pos, "unexpected content after quoted scalar")
+		}
+		return n, nil
+	default:
+		return p.parsePlain(parentIndent)
+	}
+}
+
+// atMappingKey reports whether the current line holds a mapping key at the
+// current position.
+func (p *yamlParser) atMappingKey() bool {
+	pos := p.pos
+	if c := p.src[pos]; c == '\'' || c == '"' {
+		end, ok := p.quotedKeyEnd(pos)
+		if !ok {
+			return false
+		}
+		for pos = end; p.peekAt(pos) == ' ' || p.peekAt(pos) == '\t'; pos++ {
+		}
+		return p.peekAt(pos) == ':' && isBlank(p.peekAt(pos+1))
+	}
+	for ; pos < len(p.src) && p.src[pos] != '\n'; pos++ {
+		switch p.src[pos] {
+		case ':':
+			if isBlank(p.peekAt(pos + 1)) {
+				return true
+			}
+		case '#':
+			if pos > p.pos && (p.src[pos-1] == ' ' || p.src[pos-1] == '\t') {
+				return false
+			}
+		}
+	}
+	return false
+}
+
+// quotedKeyEnd returns the offset following a quoted scalar on a single
+// line.
+func (p *yamlParser) quotedKeyEnd(pos int) (int, bool) {
+	quote := p.src[pos]
+	for pos++; pos < len(p.src) && p.src[pos] != '\n'; pos++ {
+		switch {
+		case quote == '"' && p.src[pos] == '\\':
+			pos++
+		case p.src[pos] == quote:
+			if quote == '\'' && p.peekAt(pos+1) == '\'' {
+				pos++
+				continue
+			}
+			return pos + 1, true
+		}
+	}
+	return 0, false
+}
+
+// parseMapping parses a block mapping whose keys are indented by indent
+// columns.
+func (p *yamlParser) parseMapping(indent int) (*Node, error) {
+	n := &Node{Kind: MappingNode, Start: p.pos}
+	for {
+		key, err := p.parseKey()
+		if err != nil {
+			return nil, err
+		}
+		p.pos++ // Skip the colon.
+		value, err := p.parseValue(indent)
+		if err != nil {
+			return nil, err
+		}
+		n.Keys = append(n.Keys, key)
+		n.Values = append(n.Values, value)
+		n.End = value.End
+
+		if err := p.skipToContent(); err != nil {
+			return nil, err
+		}
+		if p.eof() || p.atDocumentMarker("---") || p.atDocumentMarker("...") || p.column(p.pos) < indent {
+			return n, nil
+		}
+		if p.column(p.pos) > indent || !p.atMappingKey() {
+			return nil, p.errorf(p.pos, "expected a mapping key at column %d", indent+1)
+		}
+	}
+}
+
+// parseKey parses a mapping key, and stops at the following colon.
+func (p *yamlParser) parseKey() (*Node, error) {
+	if c := p.src[p.pos]; c == '\'' || c == '"' {
+		key, err := p.parseQuoted()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpaces()
+		return key, nil
+	}
+	key := &Node{Kind: ScalarNode, Style: PlainStyle, Start: p.pos}
+	for !(p.src[p.pos] == ':' && isBlank(p.peekAt(p.pos+1))) {
+		p.pos++
+	}
+	key.End = p.pos
+	for key.End > key.Start && (p.src[key.End-1] == ' ' || p.src[key.End-1] == '\t') {
+		key.End--
+	}
+	key.Value = string(p.src[key.Start:key.End])
+	for i := key.Start; i <= key.End; i++ {
+		key.Offsets = append(key.Offsets, i)
+	}
+	return key, nil
+}
+
+// parseValue parses the value following a mapping key indented by indent
+// columns.
+func (p *yamlParser) parseValue(indent int) (*Node, error) {
+	if !p.atLineEnd() {
+		return p.parseNode(indent, true)
+	}
+	null := &Node{Kind: NullNode, Start: p.pos, End: p.pos}
+	if err := p.skipToContent(); err != nil {
+		return nil, err
+	}
+	switch col := p.column(p.pos); {
+	case p.eof() || p.atDocumentMarker("---") || p.atDocumentMarker("..."):
+		return null, nil
+	case col > indent:
+		return p.parseNode(indent, false)
+	case col == indent && p.atSequenceEntry():
+		return p.parseSequence(col)
+	default:
+		return null, nil
+	}
+}
+
+// parseSequence parses a block sequence whose entries are indented by
+// indent columns.
+func (p *yamlParser) parseSequence(indent int) (*Node, error) {
+	n := &Node{Kind: SequenceNode, Start: p.pos}
+	for {
+		p.pos++ // Skip the dash.
+		item := &Node{Kind: NullNode, Start: p.pos, End: p.pos}
+		if !p.atLineEnd() {
+			var err error
+			if item, err = p.parseNode(indent, false); err != nil {
+				return nil, err
+			}
+		} else {
+			if err := p.skipToContent(); err != nil {
+				return nil, err
+			}
+			if !p.eof() && !p.atDocumentMarker("---") && !p.atDocumentMarker("...") && p.column(p.pos) > indent {
+				var err error
+				if item, err = p.parseNode(indent, false); err != nil {
+					return nil, err
+				}
+			}
+		}
+		n.Items = append(n.Items, item)
+		n.End = item.End
+
+		if err := p.skipToContent(); err != nil {
+			return nil, err
+		}
+		if p.eof() || p.atDocumentMarker("---") || p.atDocumentMarker("...") || p.column(p.pos) < indent {
+			return n, nil
+		}
+		if p.column(p.pos) > indent {
+			return nil, p.errorf(p.pos, "unexpected indentation")
+		}
+		if !p.atSequenceEntry() {
+			return n, nil
+		}
+	}
+}
+
+// parseFlow parses a flow collection. Empty ones are returned as empty
+// mappings or sequences, and other ones as opaque nodes.
+func (p *yamlParser) parseFlow() (*Node, error) {
+	n := &Node{Kind: OpaqueNode, Start: p.pos}
+	empty := true
+	depth := 0
+	for {
+		if p.eof() {
+			return nil, p.errorf(n.Start, "unterminated flow collection")
+		}
+		switch c := p.src[p.pos]; c {
+		case '[', '{':
+			depth++
+			if depth > 1 {
+				empty = false
+			}
+		case ']', '}':
+			depth--
+		case '\'', '"':
+			if _, err := p.parseQuoted(); err != nil {
+				return nil, err
+			}
+			empty = false
+			continue
+		case '#':
+			if p.src[p.pos-1] == ' ' || p.src[p.pos-1] == '\t' || p.src[p.pos-1] == '\n' {
+				for !p.eof() && p.src[p.pos] != '\n' {
+					p.pos++
+				}
+				continue
+			}
+			empty = false
+		case ' ', '\t', '\n':
+		default:
+			empty = false
+		}
+		p.pos++
+		if depth == 0 {
+			break
+		}
+	}
+	n.End = p.pos
+	if empty {
+		n.Kind = MappingNode
+		if p.src[n.Start] == '[' {
+			n.Kind = SequenceNode
+		}
+	}
+	if !p.atLineEnd() {
+		return nil, p.errorf(p.pos, "unexpected content after flow collection")
+	}
+	return n, nil
+}
+
+// parseOpaque skips a node starting with an anchor, an alias or a tag: the
+// rest of the line, and the following lines indented by more than
+// parentIndent columns.
+func (p *yamlParser) parseOpaque(parentIndent int) (*Node, error) {
+	n := &Node{Kind: OpaqueNode, Start: p.pos}
+	for {
+		for !p.eof() && p.src[p.pos] != '\n' {
+			p.pos++
+		}
+		n.End = p.pos
+		next := p.pos
+		if err := p.skipToContent(); err != nil {
+			return nil, err
+		}
+		if p.eof() || p.column(p.pos) <= parentIndent || p.atDocumentMarker("---") || p.atDocumentMarker("...") {
+			p.pos = next
+			return n, nil
+		}
+	}
+}
+
+// scalarBuilder builds the value of a scalar, along with the source offsets
+// of its bytes.
+type scalarBuilder struct {
+	value   []byte
+	offsets []int
+}
+
+func (b *scalarBuilder) add(s []byte, offset int) {
+	for i, c := range s {
+		b.value = append(b.value, c)
+		b.offsets = append(b.offsets, offset+i)
+	}
+}
+
+// addAt adds bytes all coming from the same source offset, such as decoded
+// escape sequences and folded line breaks.
+func (b *scalarBuilder) addAt(s string, offset int) {
+	for i := range len(s) {
+		b.value = append(b.value, s[i])
+		b.offsets = append(b.offsets, offset)
+	}
+}
+
+// node returns the scalar node ending at end.
+func (b *scalarBuilder) node(style Style, start, end int) *Node {
+	return &Node{
+		Kind:    ScalarNode,
+		Style:   style,
+		Start:   start,
+		End:     end,
+		Value:   string(b.value),
+		Offsets: append(b.offsets, end),
+	}
+}
+
+// fold adds the folded form of breaks line breaks.
+func (b *scalarBuilder) fold(breaks, offset int) {
+	if breaks == 1 {
+		b.addAt(" ", offset)
+		return
+	}
+	b.addAt(strings.Repeat("\n", breaks-1), offset)
+}
+
+// parsePlain parses a plain scalar, continued on the following lines
+// indented by more than parentIndent columns.
+func (p *yamlParser) parsePlain(parentIndent int) (*Node, error) {
+	var b scalarBuilder
+	start := p.pos
+	end := p.pos
+	for {
+		lineStart := p.pos
+		contentEnd := p.pos
+		for ; !p.eof() && p.src[p.pos] != '\n'; p.pos++ {
+			c := p.src[p.pos]
+			if c == '#' && (p.pos == lineStart || p.src[p.pos-1] == ' ' || p.src[p.pos-1] == '\t') {
+				break
+			}
+			if c == ':' && isBlank(p.peekAt(p.pos+1)) {
+				return nil, p.errorf(p.pos, "mapping values are not allowed in multi-line plain scalars")
+			}
+			if c != ' ' && c != '\t' {
+				contentEnd = p.pos + 1
+			}
+		}
+		b.add(p.src[lineStart:contentEnd], lineStart)
+		end = contentEnd
+		if p.peekAt(p.pos) == '#' {
+			break
+		}
+
+		// Look for a continuation line.
+		next := p.pos
+		breaks := 0
+		for next < len(p.src) && p.src[next] == '\n' {
+			next++
+			breaks++
+			for next < len(p.src) && (p.src[next] == ' ' || p.src[next] == '\t') {
+				next++
+			}
+		}
+		if next >= len(p.src) || p.column(next) <= parentIndent || p.src[next] == '#' {
+			break
+		}
+		save := p.pos
+		p.pos = next
+		if p.atDocumentMarker("---") || p.atDocumentMarker("...") {
+			p.pos = save
+			break
+		}
+		b.fold(breaks, end)
+	}
+	p.pos = end
+	return b.node(PlainStyle, start, end), nil
+}
+
+// parseQuoted parses a single or double-quoted scalar.
+func (p *yamlParser) parseQuoted() (*Node, error) {
+	var b scalarBuilder
+	start := p.pos
+	quote := p.src[p.pos]
+	style := SingleQuotedStyle
+	if quote == '"' {
+		style = DoubleQuotedStyle
+	}
+	p.pos++
+	for {
+		if p.eof() {
+			return nil, p.errorf(start, "unterminated quoted scalar")
+		}
+		switch c := p.src[p.pos]; {
+		case c == quote && quote == '\'' && p.peekAt(p.pos+1) == '\'':
+			b.addAt("'", p.pos)
+			p.pos += 2
+		case c == quote:
+			p.pos++
+			return b.node(style, start, p.pos), nil
+		case c == '\\' && quote == '"':
+			if p.peekAt(p.pos+1) == '\n' {
+				// An escaped line break joins the lines without a space.
+				p.pos += 2
+				p.skipSpaces()
+				continue
+			}
+			s, n, err := p.unescape()
+			if err != nil {
+				return nil, err
+			}
+			b.addAt(s, p.pos)
+			p.pos += n
+		case c == ' ' || c == '\t' || c == '\n':
+			next := p.pos
+			for next < len(p.src) && (p.src[next] == ' ' || p.src[next] == '\t') {
+				next++
+			}
+			if next < len(p.src) && p.src[next] != '\n' {
+				b.add(p.src[p.pos:next], p.pos)
+				p.pos = next
+				continue
+			}
+			// Fold the line breaks, dropping the surrounding whitespace.
+			offset := p.pos
+			breaks := 0
+			for p.pos = next; !p.eof() && p.src[p.pos] == '\n'; {
+				p.pos++
+				breaks++
+				p.skipSpaces()
+			}
+			if breaks > 0 {
+				b.fold(breaks, offset)
+			}
+		default:
+			b.add(p.src[p.pos:p.pos+1], p.pos)
+			p.pos++
+		}
+	}
+}
+
+// unescape decodes the escape sequence at the current position, and returns
+// its value and length.
+func (p *yamlParser) unescape() (string, int, error) {
+	simple := map[byte]string{
+		'0': "\x00", 'a': "\a", 'b': "\b", 't': "\t", '\t': "\t", 'n': "\n",
+		'v': "\v", 'f': "\f", 'r': "\r", 'e': "\x1b", ' ': " ", '"': `"`,
+		'/': "/", '\\': `\`, 'N': "\u0085", '_': "\u00a0", 'L': "\u2028", 'P': "\u2029",
+	}
+	c := p.peekAt(p.pos + 1)
+	if s, ok := simple[c]; ok {
+		return s, 2, nil
+	}
+	digits := map[byte]int{'x': 2, 'u': 4, 'U': 8}[c]
+	if digits == 0 || p.pos+2+digits > len(p.src) {
+		return "", 0, p.errorf(p.pos, "invalid escape sequence")
+	}
+	r, err := strconv.ParseUint(string(p.src[p.pos+2:p.pos+2+digits]), 16, 32)
+	if err != nil || !utf8.ValidRune(rune(r)) {
+		return "", 0, p.errorf(p.pos, "invalid escape sequence")
+	}
+	return string(rune(r)), 2 + digits, nil
+}
+
+// blockLine is a content line of a block scalar.
+type blockLine struct {
+	start, end int
+	// eol is the offset of the line feed ending the line.
+	eol int
+}
+
+// parseBlockScalar parses a literal or folded block scalar, whose parent is
+// indented by parentIndent columns.
+func (p *yamlParser) parseBlockScalar(parentIndent int) (*Node, error) {
+	start := p.pos
+	style := LiteralStyle
+	if p.src[p.pos] == '>' {
+		style = FoldedStyle
+	}
+	p.pos++
+	chomping, explicit := byte(0), 0
+	for range 2 {
+		switch c := p.peekAt(p.pos); {
+		case (c == '+' || c == '-') && chomping == 0:
+			chomping = c
+			p.pos++
+		case c >= '1' && c <= '9' && explicit == 0:
+			explicit = int(c - '0')
+			p.pos++
+		}
+	}
+	if !p.atLineEnd() {
+		return nil, p.errorf(p.pos, "invalid block scalar header")
+	}
+	for !p.eof() && p.src[p.pos] != '\n' {
+		p.pos++
+	}
+	headerEnd := p.pos
+
+	// Find the indentation of the content.
+	indent := max(parentIndent, 0) + explicit
+	if explicit == 0 {
+		for pos := p.pos + 1; pos < len(p.src); pos++ {
+			lineStart := pos
+			for pos < len(p.src) && p.src[pos] == ' ' {
+				pos++
+			}
+			if pos < len(p.src) && p.src[pos] != '\n' {
+				indent = pos - lineStart
+				break
+			}
+		}
+	}
+
+	var lines []blockLine
+	trailing := 0
+	end := headerEnd
+	for !p.eof() {
+		lineStart := p.pos + 1
+		pos := lineStart
+		for pos < len(p.src) && p.src[pos] == ' ' {
+			pos++
+		}
+		eol := pos
+		for eol < len(p.src) && p.src[eol] != '\n' {
+			eol++
+		}
+		if pos == eol && pos-lineStart <= indent {
+			// An empty line.
+			if lineStart >= len(p.src) {
+				break
+			}
+			lines = append(lines, blockLine{start: pos, end: pos, eol: eol})
+			trailing++
+			p.pos = eol
+			continue
+		}
+		if pos-lineStart < indent || indent <= parentIndent {
+			break
+		}
+		lines = append(lines, blockLine{start: lineStart + indent, end: eol, eol: eol})
+		trailing = 0
+		end = eol
+		p.pos = eol
+	}
+	content := lines[:len(lines)-trailing]
+
+	var b scalarBuilder
+	lastEOL := headerEnd
+	if style == LiteralStyle {
+		for i, l := range content {
+			if i > 0 {
+				b.addAt("\n", content[i-1].eol)
+			}
+			b.add(p.src[l.start:l.end], l.start)
+		}
+	} else {
+		prev := -1
+		empties := 0
+		for i, l := range content {
+			if l.start == l.end {
+				empties++
+				continue
+			}
+			switch {
+			case prev < 0:
+				b.addAt(strings.Repeat("\n", empties), l.start)
+			case isFoldable(p.src, content[prev]) && isFoldable(p.src, l):
+				b.fold(empties+1, content[prev].eol)
+			default:
+				b.addAt(strings.Repeat("\n", empties+1), content[prev].eol)
+			}
+			b.add(p.src[l.start:l.end], l.start)
+			prev, empties = i, 0
+		}
+	}
+	if len(content) > 0 {
+		lastEOL = content[len(content)-1].eol
+	}
+	switch {
+	case chomping == '-' || len(content) == 0 && chomping != '+':
+	case chomping == '+':
+		b.addAt(strings.Repeat("\n", 1+trailing), lastEOL)
+	default:
+		b.addAt("\n", lastEOL)
+	}
+	n := b.node(style, start, end)
+	// The offsets of the final line breaks are past the edited content.
+	p.pos = end
+	return n, nil
+}
+
+// isFoldable reports whether a line of a folded scalar can be folded, which
+// is not the case of more-indented lines.
+func isFoldable(src []byte, l blockLine) bool {
+	return l.start < l.end && src[l.start] != ' ' && src[l.start] != '\t'
+}
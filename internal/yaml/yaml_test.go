@@ -0,0 +1,94 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package yaml
+
+import "testing"
+
+func TestParse_Scalars(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "plain", in: "v: a b  # comment\n", want: "a b"},
+		{name: "multi-line plain", in: "v: a\n  b\n\n  c\nw: d\n", want: "a b\nc"},
+		{name: "single-quoted", in: "v: 'it''s'\n", want: "it's"},
+		{name: "double-quoted", in: `v: "a\"b\\cé\x41"` + "\n", want: `a"b\cé` + "A"},
+		{name: "folded double-quoted", in: "v: \"a  \n   b\\\n  c\"\n", want: "a bc"},
+		{name: "literal", in: "v: |\n  a\n    b\n\n  c\n\nw: d\n", want: "a\n  b\n\nc\n"},
+		{name: "literal strip", in: "v: |-\n  a\n  b\n", want: "a\nb"},
+		{name: "literal keep", in: "v: |+\n  a\n\n", want: "a\n\n"},
+		{name: "literal explicit indentation", in: "v: |2\n    a\n  b\n", want: "  a\nb\n"},
+		{name: "folded", in: "v: >\n  a\n  b\n\n  c\n    d\n  e\n", want: "a b\nc\n  d\ne\n"},
+		{name: "sequence entry", in: "- |\n  a\n- b\n", want: "a\n"},
+		{name: "document markers", in: "---\nv: a\n...\n", want: "a"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			root, err := Parse([]byte(tc.in))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			n := root.Get("v")
+			if root.Kind == SequenceNode {
+				n = root.Items[0]
+			}
+			if n.Value != tc.want {
+				t.Errorf("value = %q, want %q", n.Value, tc.want)
+			}
+			if len(n.Offsets) != len(n.Value)+1 {
+				t.Fatalf("got %d offsets for %d bytes", len(n.Offsets), len(n.Value))
+			}
+			// Bytes copied from the source must map to themselves.
+			for i, off := range n.Offsets[:len(n.Value)] {
+				if c := tc.in[off]; c == n.Value[i] || c == '\\' || c == '\n' || c == ' ' || c == '\'' {
+					continue
+				}
+				t.Errorf("offset of byte %d (%q) points to %q", i, n.Value[i], tc.in[off])
+			}
+		})
+	}
+}
+
+func TestParse_Structure(t *testing.T) {
+	root, err := Parse([]byte(`groups:
+- name: a # comment
+  rules:
+    - record: r
+      expr: x
+      labels: {}
+- name: "b"
+  rules: [{record: s, expr: y}]
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	groups := root.Get("groups")
+	if groups.Kind != SequenceNode || len(groups.Items) != 2 {
+		t.Fatalf("groups = %+v, want a sequence of 2 items", groups)
+	}
+	a, b := groups.Items[0], groups.Items[1]
+	if got := a.Get("name").Scalar(); got != "a" {
+		t.Errorf("first group name = %q", got)
+	}
+	rule := a.Get("rules").Items[0]
+	if rule.Get("record").Scalar() != "r" || rule.Get("expr").Scalar() != "x" || rule.Get("labels").Kind != MappingNode {
+		t.Errorf("rule = %+v", rule)
+	}
+	if got := b.Get("name").Scalar(); got != "b" {
+		t.Errorf("second group name = %q", got)
+	}
+	if got := b.Get("rules").Kind; got != OpaqueNode {
+		t.Errorf("flow rules kind = %v, want opaque", got)
+	}
+}
@@ -22,6 +22,7 @@ package otlptranslator
 import (
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"unicode"
 )
@@ -50,6 +51,9 @@ type LabelNamer struct {
 	// specification https://github.com/open-telemetry/opentelemetry-specification/blob/v1.38.0/specification/compatibility/prometheus_and_openmetrics.md#otlp-metric-points-to-prometheus),
 	// but may be needed for compatibility with legacy systems that rely on the old behavior.
 	PreserveMultipleUnderscores bool
+	// Behavior pins the rules names are translated with. The zero value
+	// keeps the BehaviorV1 rules.
+	Behavior BehaviorVersion
 }
 
 // Build normalizes the specified label to follow Prometheus label names standard.
@@ -60,6 +64,7 @@ type LabelNamer struct {
 //   - With the deprecated UnderscoreLabelSanitization option, prefixes labels starting with a single underscore with "key"
 //   - Preserves double underscore labels (reserved names)
 //   - If UTF8Allowed is true, returns label as-is
+//   - Behavior selects the version of these rules, see BehaviorVersion
 //
 // Examples:
 //
@@ -68,6 +73,9 @@ type LabelNamer struct {
 //	namer.Build("123invalid")      // "key_123invalid"
 //	namer.Build("__reserved__")    // "__reserved__" (preserved)
 func (ln *LabelNamer) Build(label string) (string, error) {
+	if err := ln.Behavior.validate(); err != nil {
+		return "", err
+	}
 	if len(label) == 0 {
 		return "", errors.New("label name is empty")
 	}
@@ -79,16 +87,23 @@ func (ln *LabelNamer) Build(label string) (string, error) {
 		return label, nil
 	}
 
-	if canFastPathLabel(label, ln.PreserveMultipleUnderscores, ln.UnderscoreLabelSanitization) {
+	preserveMultipleUnderscores, underscoreLabelSanitization := ln.options()
+	if canFastPathLabel(label, preserveMultipleUnderscores, underscoreLabelSanitization) {
 		return label, nil
 	}
+	return ln.buildEscaped(label)
+}
 
-	normalizedName := sanitizeLabelName(label, ln.PreserveMultipleUnderscores)
+// buildEscaped builds an escaped label name, without the fast path of
+// labels that are returned unchanged.
+func (ln *LabelNamer) buildEscaped(label string) (string, error) {
+	preserveMultipleUnderscores, underscoreLabelSanitization := ln.options()
+	normalizedName := sanitizeLabelName(label, preserveMultipleUnderscores)
 
 	// If label starts with a number, prepend with "key_".
 	if unicode.IsDigit(rune(normalizedName[0])) {
 		normalizedName = "key_" + normalizedName
-	} else if ln.UnderscoreLabelSanitization && strings.HasPrefix(normalizedName, "_") && !strings.HasPrefix(normalizedName, "__") {
+	} else if underscoreLabelSanitization && strings.HasPrefix(normalizedName, "_") && !strings.HasPrefix(normalizedName, "__") {
 		normalizedName = "key" + normalizedName
 	}
 
@@ -99,6 +114,51 @@ func (ln *LabelNamer) Build(label string) (string, error) {
 	return normalizedName, nil
 }
 
+// options returns the escaping options of the namer, which the
+// BehaviorCollectorContrib rules always enable.
+func (ln *LabelNamer) options() (preserveMultipleUnderscores, underscoreLabelSanitization bool) {
+	if ln.Behavior == BehaviorCollectorContrib {
+		return true, true
+	}
+	return ln.PreserveMultipleUnderscores, ln.UnderscoreLabelSanitization
+}
+
+// BuildLabels translates a set of OTLP attributes into Prometheus labels.
+//
+// Attributes whose names collide after translation are merged into a single
+// label, whose value is the concatenation of the original values ordered by
+// their original attribute name and separated with ";", as required by the
+// OpenTelemetry to Prometheus compatibility specification.
+//
+// Examples:
+//
+//	namer := LabelNamer{UTF8Allowed: false}
+//	namer.BuildLabels([]Attribute{{Key: "foo.bar", Value: "a"}, {Key: "foo_bar", Value: "b"}})
+//	// Labels{{Name: "foo_bar", Value: "a;b"}}
+func (ln *LabelNamer) BuildLabels(attributes []Attribute) (Labels, error) {
+	if len(attributes) == 0 {
+		return nil, nil
+	}
+	sorted := slices.Clone(attributes)
+	slices.SortStableFunc(sorted, func(a, b Attribute) int {
+		return strings.Compare(a.Key, b.Key)
+	})
+
+	ls := make(Labels, 0, len(sorted))
+	for _, attr := range sorted {
+		name, err := ln.Build(attr.Key)
+		if err != nil {
+			return nil, err
+		}
+		if i, ok := ls.index(name); ok {
+			ls[i].Value += ";" + attr.Value
+			continue
+		}
+		ls = ls.set(Label{Name: name, Value: attr.Value})
+	}
+	return ls, nil
+}
+
 func hasUnderscoresOnly(label string) bool {
 	for _, c := range label {
 		if c != '_' {
@@ -287,3 +287,68 @@ func TestLabelNamerBuildZeroAlloc(t *testing.T) {
 		t.Fatalf("Build allocated %f times per run on the fast path, want 0", got)
 	}
 }
+
+func TestBuildLabels(t *testing.T) {
+	tests := []struct {
+		name       string
+		namer      LabelNamer
+		attributes []Attribute
+		want       Labels
+		wantError  string
+	}{
+		{
+			name:  "no attributes",
+			namer: LabelNamer{},
+		},
+		{
+			name:  "sorted by translated name",
+			namer: LabelNamer{},
+			attributes: []Attribute{
+				{Key: "http.route", Value: "/"},
+				{Key: "http.method", Value: "GET"},
+			},
+			want: Labels{{Name: "http_method", Value: "GET"}, {Name: "http_route", Value: "/"}},
+		},
+		{
+			name:  "colliding names are merged in original name order",
+			namer: LabelNamer{},
+			attributes: []Attribute{
+				{Key: "foo_bar", Value: "b"},
+				{Key: "foo.bar", Value: "a"},
+			},
+			want: Labels{{Name: "foo_bar", Value: "a;b"}},
+		},
+		{
+			name:  "no collision when UTF-8 is allowed",
+			namer: LabelNamer{UTF8Allowed: true},
+			attributes: []Attribute{
+				{Key: "foo_bar", Value: "b"},
+				{Key: "foo.bar", Value: "a"},
+			},
+			want: Labels{{Name: "foo.bar", Value: "a"}, {Name: "foo_bar", Value: "b"}},
+		},
+		{
+			name:       "invalid name",
+			namer:      LabelNamer{},
+			attributes: []Attribute{{Key: "ようこそ", Value: "a"}},
+			wantError:  `normalization for label name "ようこそ" resulted in invalid name "_"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.namer.BuildLabels(tt.attributes)
+			if tt.wantError != "" {
+				if err == nil || err.Error() != tt.wantError {
+					t.Fatalf("LabelNamer.BuildLabels() error = %v, want %q", err, tt.wantError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LabelNamer.BuildLabels() returned an error: %s", err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("LabelNamer.BuildLabels() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
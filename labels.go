@@ -0,0 +1,116 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlptranslator
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Label is a translated Prometheus label.
+type Label struct {
+	Name  string
+	Value string
+}
+
+// Labels is a set of translated Prometheus labels, sorted by name.
+type Labels []Label
+
+// NewLabels returns a sorted copy of the given labels. When the same name
+// appears more than once, the last value wins.
+func NewLabels(ls ...Label) Labels {
+	res := make(Labels, 0, len(ls))
+	for _, l := range ls {
+		res = res.set(l)
+	}
+	return res
+}
+
+// Get returns the value of the label with the given name, or an empty string
+// if there is no such label.
+func (ls Labels) Get(name string) string {
+	if i, ok := ls.index(name); ok {
+		return ls[i].Value
+	}
+	return ""
+}
+
+// Has reports whether a label with the given name is present.
+func (ls Labels) Has(name string) bool {
+	_, ok := ls.index(name)
+	return ok
+}
+
+// Merge returns a new label set holding the labels of both sets. Labels in
+// other take precedence over labels with the same name in ls.
+func (ls Labels) Merge(other Labels) Labels {
+	res := slices.Clone(ls)
+	for _, l := range other {
+		res = res.set(l)
+	}
+	return res
+}
+
+// Equal reports whether both label sets hold exactly the same labels.
+func (ls Labels) Equal(other Labels) bool {
+	return slices.Equal(ls, other)
+}
+
+// String returns the label set in the Prometheus text notation, for example
+// {a="1", b="2"}.
+func (ls Labels) String() string {
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, l := range ls {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(l.Name)
+		b.WriteByte('=')
+		b.WriteString(strconv.Quote(l.Value))
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+func (ls Labels) index(name string) (int, bool) {
+	return slices.BinarySearchFunc(ls, name, func(l Label, name string) int {
+		return strings.Compare(l.Name, name)
+	})
+}
+
+// set inserts or replaces l, keeping ls sorted. It may modify ls in place.
+func (ls Labels) set(l Label) Labels {
+	i, ok := ls.index(l.Name)
+	if ok {
+		ls[i].Value = l.Value
+		return ls
+	}
+	return slices.Insert(ls, i, l)
+}
+
+// seriesKey returns a string uniquely identifying the series with the given
+// metric name and labels.
+func seriesKey(name string, ls Labels) string {
+	var b strings.Builder
+	b.WriteString(name)
+	for _, l := range ls {
+		b.WriteByte(0xff)
+		b.WriteString(l.Name)
+		b.WriteByte(0xfe)
+		b.WriteString(l.Value)
+	}
+	return b.String()
+}
@@ -0,0 +1,72 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlptranslator
+
+import "strings"
+
+var helpEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+
+// Metadata is the Prometheus metadata of a metric family.
+type Metadata struct {
+	// FamilyName is the metric name, as built by MetricNamer.Build. For
+	// counters with the `_total` suffix, OpenMetrics names the family without
+	// it.
+	FamilyName string
+	Type       PrometheusType
+	// Help is the metric description, unescaped.
+	Help string
+	// Unit is the metric unit, as built by UnitNamer.Build. It is empty for
+	// dimensionless metrics.
+	Unit string
+}
+
+// EscapedHelp returns the help text with backslashes and newlines escaped,
+// as written on `# HELP` lines of the Prometheus text format.
+func (m Metadata) EscapedHelp() string {
+	return helpEscaper.Replace(m.Help)
+}
+
+// MetadataBuilder builds the Prometheus metadata of OTLP metrics, shared by
+// exposition formats and remote-write requests.
+//
+// Example usage:
+//
+//	builder := MetadataBuilder{
+//		MetricNamer: NewMetricNamer("", UnderscoreEscapingWithSuffixes),
+//		UnitNamer:   UnitNamer{},
+//	}
+//	md, err := builder.Build(Metric{Name: "http.requests", Type: MetricTypeNonMonotonicCounter, Temporality: TemporalityDelta})
+//	// md.FamilyName == "http_requests", md.Type == PrometheusTypeUnknown
+type MetadataBuilder struct {
+	MetricNamer MetricNamer
+	UnitNamer   UnitNamer
+}
+
+// Build builds the metadata of the specified metric. The type takes the
+// temporality into account, see MetricDescriptor.PrometheusType.
+func (b *MetadataBuilder) Build(metric Metric) (Metadata, error) {
+	name, err := b.MetricNamer.Build(metric)
+	if err != nil {
+		return Metadata{}, err
+	}
+	if err := b.UnitNamer.Behavior.validate(); err != nil {
+		return Metadata{}, err
+	}
+	return Metadata{
+		FamilyName: name,
+		Type:       metric.Descriptor().PrometheusType(),
+		Help:       metric.Description,
+		Unit:       b.UnitNamer.Build(metric.Unit),
+	}, nil
+}
@@ -0,0 +1,90 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlptranslator
+
+import "testing"
+
+func TestMetadataBuilder(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy TranslationStrategyOption
+		metric   Metric
+		want     Metadata
+	}{
+		{
+			name:     "counter",
+			strategy: UnderscoreEscapingWithSuffixes,
+			metric:   Metric{Name: "http.requests", Type: MetricTypeMonotonicCounter, Description: "Number of requests."},
+			want:     Metadata{FamilyName: "http_requests_total", Type: PrometheusTypeCounter, Help: "Number of requests."},
+		},
+		{
+			name:     "delta counter",
+			strategy: UnderscoreEscapingWithSuffixes,
+			metric:   Metric{Name: "http.requests", Type: MetricTypeMonotonicCounter, Temporality: TemporalityDelta},
+			want:     Metadata{FamilyName: "http_requests_total", Type: PrometheusTypeUnknown},
+		},
+		{
+			name:     "delta up-down counter",
+			strategy: UnderscoreEscapingWithSuffixes,
+			metric:   Metric{Name: "queue.size", Type: MetricTypeNonMonotonicCounter, Temporality: TemporalityDelta},
+			want:     Metadata{FamilyName: "queue_size", Type: PrometheusTypeUnknown},
+		},
+		{
+			name:     "histogram with unit",
+			strategy: UnderscoreEscapingWithSuffixes,
+			metric:   Metric{Name: "http.duration", Unit: "s", Type: MetricTypeHistogram},
+			want:     Metadata{FamilyName: "http_duration_seconds", Type: PrometheusTypeHistogram, Unit: "seconds"},
+		},
+		{
+			name:     "delta exponential histogram",
+			strategy: UnderscoreEscapingWithSuffixes,
+			metric:   Metric{Name: "payload", Unit: "By", Type: MetricTypeExponentialHistogram, Temporality: TemporalityDelta},
+			want:     Metadata{FamilyName: "payload_bytes", Type: PrometheusTypeGaugeHistogram, Unit: "bytes"},
+		},
+		{
+			name:     "unit without suffixes",
+			strategy: NoTranslation,
+			metric:   Metric{Name: "http.duration", Unit: "ms", Type: MetricTypeGauge, Description: "Line 1\nLine 2"},
+			want:     Metadata{FamilyName: "http.duration", Type: PrometheusTypeGauge, Help: "Line 1\nLine 2", Unit: "milliseconds"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			namer := NewMetricNamer("", tt.strategy)
+			builder := MetadataBuilder{MetricNamer: namer, UnitNamer: UnitNamer{UTF8Allowed: namer.UTF8Allowed}}
+			got, err := builder.Build(tt.metric)
+			if err != nil {
+				t.Fatalf("Build() returned an error: %s", err)
+			}
+			if got != tt.want {
+				t.Errorf("Build() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMetadataBuilder_UnknownBehavior(t *testing.T) {
+	builder := MetadataBuilder{UnitNamer: UnitNamer{Behavior: "v0"}}
+	_, err := builder.Build(Metric{Name: "http.duration", Unit: "s", Type: MetricTypeHistogram})
+	if want := `unknown behavior version "v0"`; err == nil || err.Error() != want {
+		t.Errorf("Build(), got err %v, want %q", err, want)
+	}
+}
+
+func TestMetadata_EscapedHelp(t *testing.T) {
+	md := Metadata{Help: "Size of C:\\tmp,\nin \"bytes\"."}
+	if got, want := md.EscapedHelp(), `Size of C:\\tmp,\nin "bytes".`; got != want {
+		t.Errorf("EscapedHelp() = %q, want %q", got, want)
+	}
+}
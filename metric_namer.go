@@ -104,6 +104,9 @@ type MetricNamer struct {
 	Namespace          string
 	WithMetricSuffixes bool
 	UTF8Allowed        bool
+	// Behavior pins the rules names are translated with. The zero value
+	// keeps the BehaviorV1 rules.
+	Behavior BehaviorVersion
 }
 
 // NewMetricNamer creates a MetricNamer with the specified namespace (can be
@@ -117,7 +120,8 @@ func NewMetricNamer(namespace string, strategy TranslationStrategyOption) Metric
 }
 
 // Metric is a helper struct that holds information about a metric.
-// It represents an OpenTelemetry metric with its name, unit, and type.
+// It represents an OpenTelemetry metric with its name, unit, type and
+// temporality.
 //
 // Example:
 //
@@ -130,6 +134,20 @@ type Metric struct {
 	Name string
 	Unit string
 	Type MetricType
+	// Temporality is the aggregation temporality of sums and histograms.
+	Temporality Temporality
+	// Description is the OTLP metric description, exposed as the Prometheus
+	// help text.
+	Description string
+}
+
+// Descriptor returns the full description of the metric type.
+func (m Metric) Descriptor() MetricDescriptor {
+	return MetricDescriptor{
+		Kind:        m.Type.Kind(),
+		Temporality: m.Temporality,
+		Monotonic:   m.Type == MetricTypeMonotonicCounter,
+	}
 }
 
 // Build builds a metric name for the specified metric.
@@ -138,6 +156,7 @@ type Metric struct {
 //   - If UTF8Allowed is true, doesn't translate names - all characters must be valid UTF-8, however.
 //   - If UTF8Allowed is false, translates metric names to comply with legacy Prometheus name scheme by escaping invalid characters to `_`.
 //   - If WithMetricSuffixes is true, adds appropriate suffixes based on type and unit.
+//   - Behavior selects the version of these rules, see BehaviorVersion.
 //
 // See rules at https://prometheus.io/docs/concepts/data_model/#metric-names-and-labels
 //
@@ -161,13 +180,41 @@ type Metric struct {
 //	}
 //	// result == "memory_usage_bytes"
 func (mn *MetricNamer) Build(metric Metric) (string, error) {
+	if err := mn.Behavior.validate(); err != nil {
+		return "", err
+	}
 	if mn.UTF8Allowed {
-		return mn.buildMetricName(metric.Name, metric.Unit, metric.Type)
+		name, err := mn.buildMetricName(metric.Name, metric.Unit, metric.Descriptor())
+		if err == nil && name == "" && !mn.Behavior.before(BehaviorV2) {
+			err = fmt.Errorf("normalization for metric %q resulted in empty name", metric.Name)
+		}
+		return name, err
 	}
-	return mn.buildCompliantMetricName(metric.Name, metric.Unit, metric.Type)
+	return mn.buildCompliantMetricName(metric.Name, metric.Unit, metric.Descriptor())
 }
 
-func (mn *MetricNamer) buildCompliantMetricName(name, unit string, metricType MetricType) (normalizedName string, err error) {
+// BuildCreated builds the name of the OpenMetrics `_created` series holding
+// the creation time of the specified metric. The `_total` suffix of counters
+// is replaced, as the created series belongs to the whole metric family:
+// https://github.com/prometheus/OpenMetrics/blob/v1.0.0/specification/OpenMetrics.md#counter-1
+//
+// Examples:
+//
+//	namer := MetricNamer{WithMetricSuffixes: true, UTF8Allowed: false}
+//	namer.BuildCreated(Metric{Name: "requests", Type: MetricTypeMonotonicCounter}) // "requests_created"
+//	namer.BuildCreated(Metric{Name: "latency", Unit: "s", Type: MetricTypeHistogram}) // "latency_seconds_created"
+func (mn *MetricNamer) BuildCreated(metric Metric) (string, error) {
+	name, err := mn.Build(metric)
+	if err != nil {
+		return "", err
+	}
+	if mn.WithMetricSuffixes && metric.Descriptor().hasTotalSuffix() {
+		name = strings.TrimSuffix(name, "_total")
+	}
+	return name + createdSuffix, nil
+}
+
+func (mn *MetricNamer) buildCompliantMetricName(name, unit string, descriptor MetricDescriptor) (normalizedName string, err error) {
 	defer func() {
 		if len(normalizedName) == 0 {
 			err = fmt.Errorf("normalization for metric %q resulted in empty name", name)
@@ -190,7 +237,11 @@ func (mn *MetricNamer) buildCompliantMetricName(name, unit string, metricType Me
 
 	// Full normalization following standard Prometheus naming conventions
 	if mn.WithMetricSuffixes {
-		normalizedName = normalizeName(name, unit, metricType, mn.Namespace)
+		if mn.Behavior == BehaviorCollectorContrib {
+			normalizedName = normalizeNameCollectorContrib(name, unit, descriptor, mn.Namespace)
+			return
+		}
+		normalizedName = normalizeName(name, unit, descriptor, mn.Namespace, mn.Behavior)
 		return
 	}
 
@@ -201,11 +252,17 @@ func (mn *MetricNamer) buildCompliantMetricName(name, unit string, metricType Me
 
 	// Namespace?
 	if mn.Namespace != "" {
-		namespace := strings.Join(strings.FieldsFunc(mn.Namespace, func(r rune) bool {
-			return !isValidCompliantMetricChar(r) && r != '_'
-		}), "_")
-		normalizedName = namespace + "_" + metricName
-		return
+		namespace := mn.Namespace
+		if mn.Behavior != BehaviorCollectorContrib {
+			namespace = strings.Join(strings.FieldsFunc(namespace, func(r rune) bool {
+				return !isValidCompliantMetricChar(r) && r != '_'
+			}), "_")
+		}
+		metricName = namespace + "_" + metricName
+		if mn.Behavior.before(BehaviorV2) {
+			normalizedName = metricName
+			return
+		}
 	}
 
 	// Metric name starts with a digit? Prefix it with an underscore.
@@ -234,7 +291,7 @@ func replaceInvalidMetricChar(r rune) rune {
 }
 
 // Build a normalized name for the specified metric.
-func normalizeName(name, unit string, metricType MetricType, namespace string) string {
+func normalizeName(name, unit string, descriptor MetricDescriptor, namespace string, behavior BehaviorVersion) string {
 	// Split metric name into "tokens" (of supported metric name runes).
 	// Note that this has the side effect of replacing multiple consecutive underscores with a single underscore.
 	// This is part of the OTel to Prometheus specification: https://github.com/open-telemetry/opentelemetry-specification/blob/v1.38.0/specification/compatibility/prometheus_and_openmetrics.md#otlp-metric-points-to-prometheus.
@@ -244,19 +301,73 @@ func normalizeName(name, unit string, metricType MetricType, namespace string) s
 	)
 
 	mainUnitSuffix, perUnitSuffix := buildUnitSuffixes(unit)
-	nameTokens = addUnitTokens(nameTokens, cleanUpUnit(mainUnitSuffix), cleanUpUnit(perUnitSuffix))
+	nameTokens = addUnitTokens(nameTokens, cleanUpUnit(mainUnitSuffix), cleanUpUnit(perUnitSuffix), behavior)
+
+	// Append _total for Counters
+	if descriptor.hasTotalSuffix() {
+		nameTokens = append(removeItem(nameTokens, "total"), "total")
+	}
+
+	// Append _ratio for metrics with unit "1"
+	if descriptor.hasRatioSuffix(unit) {
+		nameTokens = append(removeItem(nameTokens, "ratio"), "ratio")
+	}
+
+	// Namespace? It is split into tokens like the name, so that it is
+	// escaped the same way.
+	switch {
+	case namespace == "":
+	case behavior.before(BehaviorV2):
+		nameTokens = append([]string{namespace}, nameTokens...)
+	default:
+		nameTokens = append(strings.FieldsFunc(
+			namespace,
+			func(r rune) bool { return !isValidCompliantMetricChar(r) },
+		), nameTokens...)
+	}
+
+	// Build the string from the tokens, separated with underscores
+	normalizedName := strings.Join(nameTokens, "_")
+
+	// Metric name cannot start with a digit, so prefix it with "_" in this case
+	if normalizedName != "" && unicode.IsDigit(rune(normalizedName[0])) {
+		normalizedName = "_" + normalizedName
+	}
+
+	return normalizedName
+}
+
+// normalizeNameCollectorContrib builds a normalized name for the specified
+// metric like the normalizer of the OpenTelemetry Collector contrib, see
+// BehaviorCollectorContrib.
+func normalizeNameCollectorContrib(name, unit string, descriptor MetricDescriptor, namespace string) string {
+	nameTokens := strings.FieldsFunc(
+		name,
+		func(r rune) bool { return !isValidCompliantMetricChar(r) },
+	)
+
+	// Units are split into tokens at any character but letters and digits,
+	// and their suffixes are only appended if not present in the name already.
+	unitTokens := func(unit string) string {
+		return strings.Join(strings.FieldsFunc(unit, func(r rune) bool {
+			return !isValidCompliantMetricChar(r) || r == ':'
+		}), "_")
+	}
+	mainUnitSuffix, perUnitSuffix := buildUnitSuffixes(unit)
+	if mainUnit := unitTokens(mainUnitSuffix); mainUnit != "" && !slices.Contains(nameTokens, mainUnit) {
+		nameTokens = append(nameTokens, mainUnit)
+	}
+	if perUnit := unitTokens(strings.TrimPrefix(perUnitSuffix, "per_")); perUnit != "" && !slices.Contains(nameTokens, perUnit) {
+		nameTokens = append(nameTokens, "per", perUnit)
+	}
 
 	// Append _total for Counters
-	if metricType == MetricTypeMonotonicCounter {
+	if descriptor.hasTotalSuffix() {
 		nameTokens = append(removeItem(nameTokens, "total"), "total")
 	}
 
 	// Append _ratio for metrics with unit "1"
-	// Some OTel receivers improperly use unit "1" for counters of objects
-	// See https://github.com/open-telemetry/opentelemetry-collector-contrib/issues?q=is%3Aissue+some+metric+units+don%27t+follow+otel+semantic+conventions
-	// Until these issues have been fixed, we're appending `_ratio` for gauges ONLY
-	// Theoretically, counters could be ratios as well, but it's absurd (for mathematical reasons)
-	if unit == "1" && metricType == MetricTypeGauge {
+	if descriptor.hasRatioSuffix(unit) {
 		nameTokens = append(removeItem(nameTokens, "ratio"), "ratio")
 	}
 
@@ -282,8 +393,15 @@ func normalizeName(name, unit string, metricType MetricType, namespace string) s
 //
 // If the 'per' unit ends with underscore, the underscore will be removed. If the per unit is just
 // 'per_', it will be entirely removed.
-func addUnitTokens(nameTokens []string, mainUnitSuffix, perUnitSuffix string) []string {
-	if slices.Contains(nameTokens, mainUnitSuffix) {
+func addUnitTokens(nameTokens []string, mainUnitSuffix, perUnitSuffix string, behavior BehaviorVersion) []string {
+	contains := containsTokens
+	if behavior.before(BehaviorV2) {
+		// Suffixes are only found in names as single tokens.
+		contains = slices.Contains[[]string]
+	} else {
+		mainUnitSuffix = strings.TrimRight(mainUnitSuffix, "_")
+	}
+	if contains(nameTokens, mainUnitSuffix) {
 		mainUnitSuffix = ""
 	}
 
@@ -291,7 +409,7 @@ func addUnitTokens(nameTokens []string, mainUnitSuffix, perUnitSuffix string) []
 		perUnitSuffix = ""
 	} else {
 		perUnitSuffix = strings.TrimSuffix(perUnitSuffix, "_")
-		if slices.Contains(nameTokens, perUnitSuffix) {
+		if contains(nameTokens, perUnitSuffix) {
 			perUnitSuffix = ""
 		}
 	}
@@ -309,6 +427,22 @@ func addUnitTokens(nameTokens []string, mainUnitSuffix, perUnitSuffix string) []
 	return nameTokens
 }
 
+// containsTokens reports whether the underscore-separated tokens of suffix,
+// such as the ones of "per_second", appear in a row in nameTokens, so that
+// translating a translated name does not add its unit suffixes again.
+func containsTokens(nameTokens []string, suffix string) bool {
+	if suffix == "" {
+		return false
+	}
+	suffixTokens := strings.Split(suffix, "_")
+	for i := 0; i+len(suffixTokens) <= len(nameTokens); i++ {
+		if slices.Equal(nameTokens[i:i+len(suffixTokens)], suffixTokens) {
+			return true
+		}
+	}
+	return false
+}
+
 // Remove the specified value from the slice.
 func removeItem(slice []string, value string) []string {
 	newSlice := make([]string, 0, len(slice))
@@ -320,7 +454,7 @@ func removeItem(slice []string, value string) []string {
 	return newSlice
 }
 
-func (mn *MetricNamer) buildMetricName(inputName, unit string, metricType MetricType) (name string, err error) {
+func (mn *MetricNamer) buildMetricName(inputName, unit string, descriptor MetricDescriptor) (name string, err error) {
 	name = inputName
 	if mn.Namespace != "" {
 		name = mn.Namespace + "_" + name
@@ -328,20 +462,16 @@ func (mn *MetricNamer) buildMetricName(inputName, unit string, metricType Metric
 
 	if mn.WithMetricSuffixes {
 		// Append _ratio for metrics with unit "1"
-		// Some OTel receivers improperly use unit "1" for counters of objects
-		// See https://github.com/open-telemetry/opentelemetry-collector-contrib/issues?q=is%3Aissue+some+metric+units+don%27t+follow+otel+semantic+conventions
-		// Until these issues have been fixed, we're appending `_ratio` for gauges ONLY
-		// Theoretically, counters could be ratios as well, but it's absurd (for mathematical reasons)
-		if unit == "1" && metricType == MetricTypeGauge {
-			name = trimSuffixAndDelimiter(name, "ratio")
+		if descriptor.hasRatioSuffix(unit) {
+			name = trimSuffixAndDelimiter(name, "ratio", mn.Behavior)
 			defer func() {
 				name += "_ratio"
 			}()
 		}
 
 		// Append _total for Counters.
-		if metricType == MetricTypeMonotonicCounter {
-			name = trimSuffixAndDelimiter(name, "total")
+		if descriptor.hasTotalSuffix() {
+			name = trimSuffixAndDelimiter(name, "total", mn.Behavior)
 			defer func() {
 				name += "_total"
 			}()
@@ -349,7 +479,7 @@ func (mn *MetricNamer) buildMetricName(inputName, unit string, metricType Metric
 
 		mainUnitSuffix, perUnitSuffix := buildUnitSuffixes(unit)
 		if perUnitSuffix != "" {
-			name = trimSuffixAndDelimiter(name, perUnitSuffix)
+			name = trimSuffixAndDelimiter(name, perUnitSuffix, mn.Behavior)
 			defer func() {
 				name = name + "_" + perUnitSuffix
 			}()
@@ -364,9 +494,10 @@ func (mn *MetricNamer) buildMetricName(inputName, unit string, metricType Metric
 }
 
 // trimSuffixAndDelimiter trims a suffix, plus one extra character which is
-// assumed to be a delimiter.
-func trimSuffixAndDelimiter(name, suffix string) string {
-	if strings.HasSuffix(name, suffix) && len(name) > len(suffix)+1 {
+// assumed to be a delimiter. A name made of an underscore and the suffix only,
+// as an empty name translates to, is trimmed too since BehaviorV2.
+func trimSuffixAndDelimiter(name, suffix string, behavior BehaviorVersion) string {
+	if strings.HasSuffix(name, suffix) && (len(name) > len(suffix)+1 || name == "_"+suffix && !behavior.before(BehaviorV2)) {
 		return name[:len(name)-(len(suffix)+1)]
 	}
 	return name
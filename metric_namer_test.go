@@ -1130,6 +1130,54 @@ func TestMetricNamer_Build(t *testing.T) {
 			wantMetricName: "metric",
 		},
 
+		// Temporality does not change the suffixes
+		{
+			name:  "delta monotonic sum gets total suffix",
+			namer: NewMetricNamer("", UnderscoreEscapingWithSuffixes),
+			metric: Metric{
+				Name:        "http.requests",
+				Unit:        "1",
+				Type:        MetricTypeMonotonicCounter,
+				Temporality: TemporalityDelta,
+			},
+			wantMetricName: "http_requests_total",
+		},
+		{
+			name:  "cumulative non-monotonic sum has no total suffix",
+			namer: NewMetricNamer("", UnderscoreEscapingWithSuffixes),
+			metric: Metric{
+				Name:        "queue.size",
+				Unit:        "By",
+				Type:        MetricTypeNonMonotonicCounter,
+				Temporality: TemporalityCumulative,
+			},
+			wantMetricName: "queue_size_bytes",
+			wantUnitName:   "bytes",
+		},
+		{
+			name:  "delta monotonic sum gets total suffix/UTF-8",
+			namer: NewMetricNamer("", NoUTF8EscapingWithSuffixes),
+			metric: Metric{
+				Name:        "http.requests",
+				Unit:        "1",
+				Type:        MetricTypeMonotonicCounter,
+				Temporality: TemporalityDelta,
+			},
+			wantMetricName: "http.requests_total",
+		},
+		{
+			name:  "delta histogram",
+			namer: NewMetricNamer("", UnderscoreEscapingWithSuffixes),
+			metric: Metric{
+				Name:        "http.duration",
+				Unit:        "ms",
+				Type:        MetricTypeHistogram,
+				Temporality: TemporalityDelta,
+			},
+			wantMetricName: "http_duration_milliseconds",
+			wantUnitName:   "milliseconds",
+		},
+
 		// Common OTel metrics to showcase how the namer works
 		{
 			name: "http.request.duration/Prometheus-style",
@@ -1185,6 +1233,69 @@ func TestMetricNamer_Build(t *testing.T) {
 			},
 			wantMetricName: "http.requests",
 		},
+		{
+			name: "namespace starting with digit without suffixes",
+			namer: MetricNamer{
+				Namespace:          "1app",
+				UTF8Allowed:        false,
+				WithMetricSuffixes: false,
+			},
+			metric: Metric{
+				Name: "requests",
+				Type: MetricTypeGauge,
+			},
+			wantMetricName: "1app_requests",
+		},
+		{
+			name: "namespace with special characters with suffixes",
+			namer: MetricNamer{
+				Namespace:          "my-app",
+				UTF8Allowed:        false,
+				WithMetricSuffixes: true,
+			},
+			metric: Metric{
+				Name: "requests",
+				Type: MetricTypeGauge,
+			},
+			wantMetricName: "my-app_requests",
+		},
+		{
+			name: "metric name already contains per unit suffix",
+			namer: MetricNamer{
+				UTF8Allowed:        false,
+				WithMetricSuffixes: true,
+			},
+			metric: Metric{
+				Name: "throughput_bytes_per_second",
+				Unit: "By/s",
+				Type: MetricTypeGauge,
+			},
+			wantMetricName: "throughput_bytes_per_second_per_second",
+			wantUnitName:   "bytes_per_second",
+		},
+		{
+			name: "metric name is only the total suffix with UTF8Allowed",
+			namer: MetricNamer{
+				UTF8Allowed:        true,
+				WithMetricSuffixes: true,
+			},
+			metric: Metric{
+				Name: "_total",
+				Type: MetricTypeMonotonicCounter,
+			},
+			wantMetricName: "_total_total",
+		},
+		{
+			name: "empty metric name with UTF8Allowed",
+			namer: MetricNamer{
+				UTF8Allowed:        true,
+				WithMetricSuffixes: false,
+			},
+			metric: Metric{
+				Type: MetricTypeGauge,
+			},
+			wantMetricName: "",
+		},
 	}
 
 	for _, tt := range tests {
@@ -1223,3 +1334,48 @@ func TestMetricNamer_Build(t *testing.T) {
 		})
 	}
 }
+
+func TestMetricNamer_BuildCreated(t *testing.T) {
+	tests := []struct {
+		name   string
+		namer  MetricNamer
+		metric Metric
+		want   string
+	}{
+		{
+			name:   "counter total suffix is replaced",
+			namer:  NewMetricNamer("", UnderscoreEscapingWithSuffixes),
+			metric: Metric{Name: "http.requests", Type: MetricTypeMonotonicCounter},
+			want:   "http_requests_created",
+		},
+		{
+			name:   "histogram keeps unit suffix",
+			namer:  NewMetricNamer("", UnderscoreEscapingWithSuffixes),
+			metric: Metric{Name: "http.duration", Unit: "s", Type: MetricTypeHistogram},
+			want:   "http_duration_seconds_created",
+		},
+		{
+			name:   "counter without suffixes",
+			namer:  NewMetricNamer("", UnderscoreEscapingWithoutSuffixes),
+			metric: Metric{Name: "requests_total", Type: MetricTypeMonotonicCounter},
+			want:   "requests_total_created",
+		},
+		{
+			name:   "UTF-8 counter",
+			namer:  NewMetricNamer("", NoUTF8EscapingWithSuffixes),
+			metric: Metric{Name: "http.requests", Type: MetricTypeMonotonicCounter},
+			want:   "http.requests_created",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.namer.BuildCreated(tt.metric)
+			if err != nil {
+				t.Fatalf("MetricNamer.BuildCreated(%v) returned an error: %s", tt.metric, err)
+			}
+			if got != tt.want {
+				t.Errorf("MetricNamer.BuildCreated(%v) = %q, want %q", tt.metric, got, tt.want)
+			}
+		})
+	}
+}
@@ -12,8 +12,11 @@
 
 package otlptranslator
 
+import "fmt"
+
 // MetricType is a representation of metric types from OpenTelemetry.
-// Different types of Sums were introduced based on their metric temporalities.
+// Different types of Sums were introduced based on their monotonicity; their
+// aggregation temporality is given separately by Metric.Temporality.
 // For more details, see:
 // https://github.com/open-telemetry/opentelemetry-specification/blob/main/specification/metrics/data-model.md#sums
 type MetricType int
@@ -21,9 +24,12 @@ type MetricType int
 const (
 	// MetricTypeUnknown represents an unknown metric type.
 	MetricTypeUnknown = iota
-	// MetricTypeNonMonotonicCounter represents a counter that is not monotonically increasing, also known as delta counter.
+	// MetricTypeNonMonotonicCounter represents a sum that is not monotonically increasing, such as an up-down counter.
+	//
+	// Historically, this type was also used for delta sums. Set Metric.Temporality to TemporalityDelta and use
+	// MetricTypeMonotonicCounter for delta sums that are monotonic instead.
 	MetricTypeNonMonotonicCounter
-	// MetricTypeMonotonicCounter represents a counter that is monotonically increasing, also known as cumulative counter.
+	// MetricTypeMonotonicCounter represents a sum that is monotonically increasing, also known as counter.
 	MetricTypeMonotonicCounter
 	// MetricTypeGauge represents a gauge metric.
 	MetricTypeGauge
@@ -34,3 +40,200 @@ const (
 	// MetricTypeSummary represents a summary metric.
 	MetricTypeSummary
 )
+
+// Kind returns the OTLP data point kind of the metric type.
+func (t MetricType) Kind() MetricKind {
+	switch t {
+	case MetricTypeNonMonotonicCounter, MetricTypeMonotonicCounter:
+		return MetricKindSum
+	case MetricTypeGauge:
+		return MetricKindGauge
+	case MetricTypeHistogram:
+		return MetricKindHistogram
+	case MetricTypeExponentialHistogram:
+		return MetricKindExponentialHistogram
+	case MetricTypeSummary:
+		return MetricKindSummary
+	default:
+		return MetricKindUnknown
+	}
+}
+
+// metricTypeNames are the names of metric types, as returned by
+// MetricType.String.
+var metricTypeNames = map[MetricType]string{
+	MetricTypeUnknown:              "unknown",
+	MetricTypeGauge:                "gauge",
+	MetricTypeMonotonicCounter:     "counter",
+	MetricTypeNonMonotonicCounter:  "updowncounter",
+	MetricTypeHistogram:            "histogram",
+	MetricTypeExponentialHistogram: "exponential_histogram",
+	MetricTypeSummary:              "summary",
+}
+
+// String returns the name of the metric type: unknown, gauge, counter,
+// updowncounter, histogram, exponential_histogram or summary.
+func (t MetricType) String() string {
+	if name, ok := metricTypeNames[t]; ok {
+		return name
+	}
+	return fmt.Sprintf("MetricType(%d)", int(t))
+}
+
+// ParseMetricType returns the metric type with the specified name, as
+// returned by MetricType.String.
+func ParseMetricType(name string) (MetricType, error) {
+	for typ, typeName := range metricTypeNames {
+		if typeName == name {
+			return typ, nil
+		}
+	}
+	return MetricTypeUnknown, fmt.Errorf("unknown metric type %q", name)
+}
+
+// MetricKind is the kind of data points of an OpenTelemetry metric,
+// regardless of their temporality and monotonicity.
+type MetricKind int
+
+const (
+	// MetricKindUnknown represents an unknown kind of data points.
+	MetricKindUnknown MetricKind = iota
+	// MetricKindGauge represents gauge data points.
+	MetricKindGauge
+	// MetricKindSum represents sum data points.
+	MetricKindSum
+	// MetricKindHistogram represents explicit bucket histogram data points.
+	MetricKindHistogram
+	// MetricKindExponentialHistogram represents exponential histogram data points.
+	MetricKindExponentialHistogram
+	// MetricKindSummary represents summary data points.
+	MetricKindSummary
+)
+
+// Temporality is the aggregation temporality of OpenTelemetry sums and
+// histograms. For more details, see:
+// https://github.com/open-telemetry/opentelemetry-specification/blob/main/specification/metrics/data-model.md#temporality
+type Temporality int
+
+const (
+	// TemporalityUnspecified represents an unknown temporality. It is handled
+	// as cumulative.
+	TemporalityUnspecified Temporality = iota
+	// TemporalityDelta represents data points holding the change since the
+	// previous data point.
+	TemporalityDelta
+	// TemporalityCumulative represents data points holding the total since the
+	// start time.
+	TemporalityCumulative
+)
+
+// PrometheusType is a Prometheus metric type, as written in the `# TYPE`
+// line of the exposition formats.
+type PrometheusType string
+
+const (
+	// PrometheusTypeUnknown represents an untyped metric.
+	PrometheusTypeUnknown PrometheusType = "unknown"
+	// PrometheusTypeCounter represents a counter.
+	PrometheusTypeCounter PrometheusType = "counter"
+	// PrometheusTypeGauge represents a gauge.
+	PrometheusTypeGauge PrometheusType = "gauge"
+	// PrometheusTypeHistogram represents a cumulative histogram.
+	PrometheusTypeHistogram PrometheusType = "histogram"
+	// PrometheusTypeGaugeHistogram represents a histogram whose buckets may
+	// go down over time.
+	PrometheusTypeGaugeHistogram PrometheusType = "gaugehistogram"
+	// PrometheusTypeSummary represents a summary.
+	PrometheusTypeSummary PrometheusType = "summary"
+)
+
+// MetricDescriptor fully describes the type of an OpenTelemetry metric:
+// the kind of its data points, their temporality and their monotonicity.
+//
+// Example:
+//
+//	// A delta monotonic sum, as produced by an OpenTelemetry SDK configured
+//	// with the delta temporality preference.
+//	d := MetricDescriptor{Kind: MetricKindSum, Temporality: TemporalityDelta, Monotonic: true}
+//	d.PrometheusType() // PrometheusTypeUnknown
+type MetricDescriptor struct {
+	Kind        MetricKind
+	Temporality Temporality
+	// Monotonic is only meaningful for sums.
+	Monotonic bool
+}
+
+// MetricType returns the MetricType matching the descriptor, dropping its
+// temporality.
+func (d MetricDescriptor) MetricType() MetricType {
+	switch d.Kind {
+	case MetricKindGauge:
+		return MetricTypeGauge
+	case MetricKindSum:
+		if d.Monotonic {
+			return MetricTypeMonotonicCounter
+		}
+		return MetricTypeNonMonotonicCounter
+	case MetricKindHistogram:
+		return MetricTypeHistogram
+	case MetricKindExponentialHistogram:
+		return MetricTypeExponentialHistogram
+	case MetricKindSummary:
+		return MetricTypeSummary
+	default:
+		return MetricTypeUnknown
+	}
+}
+
+// PrometheusType returns the Prometheus type of the metric, without any
+// delta-to-cumulative conversion:
+//   - Gauges and non-monotonic cumulative sums are gauges.
+//   - Monotonic cumulative sums are counters.
+//   - Delta sums are unknown, as Prometheus has no delta counter type.
+//   - Cumulative histograms and exponential histograms are histograms.
+//   - Delta histograms and exponential histograms are gauge histograms, as
+//     their buckets are not cumulative.
+//   - Summaries are summaries.
+//
+// An unspecified temporality is handled as cumulative.
+func (d MetricDescriptor) PrometheusType() PrometheusType {
+	switch d.Kind {
+	case MetricKindGauge:
+		return PrometheusTypeGauge
+	case MetricKindSum:
+		switch {
+		case d.Temporality == TemporalityDelta:
+			return PrometheusTypeUnknown
+		case d.Monotonic:
+			return PrometheusTypeCounter
+		default:
+			return PrometheusTypeGauge
+		}
+	case MetricKindHistogram, MetricKindExponentialHistogram:
+		if d.Temporality == TemporalityDelta {
+			return PrometheusTypeGaugeHistogram
+		}
+		return PrometheusTypeHistogram
+	case MetricKindSummary:
+		return PrometheusTypeSummary
+	default:
+		return PrometheusTypeUnknown
+	}
+}
+
+// hasTotalSuffix reports whether the metric name gets a `_total` suffix. It
+// applies to all monotonic sums, including delta ones that are meant to be
+// accumulated into Prometheus counters.
+func (d MetricDescriptor) hasTotalSuffix() bool {
+	return d.Kind == MetricKindSum && d.Monotonic
+}
+
+// hasRatioSuffix reports whether a metric with the given unit gets a
+// `_ratio` suffix.
+func (d MetricDescriptor) hasRatioSuffix(unit string) bool {
+	// Some OTel receivers improperly use unit "1" for counters of objects
+	// See https://github.com/open-telemetry/opentelemetry-collector-contrib/issues?q=is%3Aissue+some+metric+units+don%27t+follow+otel+semantic+conventions
+	// Until these issues have been fixed, we're appending `_ratio` for gauges ONLY
+	// Theoretically, counters could be ratios as well, but it's absurd (for mathematical reasons)
+	return unit == "1" && d.Kind == MetricKindGauge
+}
@@ -0,0 +1,89 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlptranslator
+
+import "testing"
+
+// The MetricType constants are untyped, so that code assigning them to other
+// integer types keeps compiling.
+var _ int = MetricTypeSummary
+
+func TestMetricDescriptor_PrometheusType(t *testing.T) {
+	tests := []struct {
+		descriptor MetricDescriptor
+		want       PrometheusType
+	}{
+		{MetricDescriptor{Kind: MetricKindUnknown}, PrometheusTypeUnknown},
+		{MetricDescriptor{Kind: MetricKindGauge}, PrometheusTypeGauge},
+		{MetricDescriptor{Kind: MetricKindSum, Monotonic: true}, PrometheusTypeCounter},
+		{MetricDescriptor{Kind: MetricKindSum, Temporality: TemporalityCumulative, Monotonic: true}, PrometheusTypeCounter},
+		{MetricDescriptor{Kind: MetricKindSum, Temporality: TemporalityDelta, Monotonic: true}, PrometheusTypeUnknown},
+		{MetricDescriptor{Kind: MetricKindSum, Temporality: TemporalityCumulative}, PrometheusTypeGauge},
+		{MetricDescriptor{Kind: MetricKindSum, Temporality: TemporalityDelta}, PrometheusTypeUnknown},
+		{MetricDescriptor{Kind: MetricKindHistogram, Temporality: TemporalityCumulative}, PrometheusTypeHistogram},
+		{MetricDescriptor{Kind: MetricKindHistogram, Temporality: TemporalityDelta}, PrometheusTypeGaugeHistogram},
+		{MetricDescriptor{Kind: MetricKindExponentialHistogram}, PrometheusTypeHistogram},
+		{MetricDescriptor{Kind: MetricKindExponentialHistogram, Temporality: TemporalityDelta}, PrometheusTypeGaugeHistogram},
+		{MetricDescriptor{Kind: MetricKindSummary}, PrometheusTypeSummary},
+	}
+	for _, tt := range tests {
+		if got := tt.descriptor.PrometheusType(); got != tt.want {
+			t.Errorf("%+v.PrometheusType() = %q, want %q", tt.descriptor, got, tt.want)
+		}
+	}
+}
+
+func TestMetricDescriptor_RoundTrip(t *testing.T) {
+	for _, typ := range []MetricType{
+		MetricTypeUnknown,
+		MetricTypeNonMonotonicCounter,
+		MetricTypeMonotonicCounter,
+		MetricTypeGauge,
+		MetricTypeHistogram,
+		MetricTypeExponentialHistogram,
+		MetricTypeSummary,
+	} {
+		m := Metric{Type: typ, Temporality: TemporalityDelta}
+		d := m.Descriptor()
+		if d.Temporality != TemporalityDelta {
+			t.Errorf("Metric{Type: %d}.Descriptor() lost the temporality", typ)
+		}
+		if got := d.MetricType(); got != typ {
+			t.Errorf("Metric{Type: %d}.Descriptor().MetricType() = %d", typ, got)
+		}
+	}
+}
+
+func TestMetricType_String(t *testing.T) {
+	for _, typ := range []MetricType{
+		MetricTypeUnknown,
+		MetricTypeNonMonotonicCounter,
+		MetricTypeMonotonicCounter,
+		MetricTypeGauge,
+		MetricTypeHistogram,
+		MetricTypeExponentialHistogram,
+		MetricTypeSummary,
+	} {
+		got, err := ParseMetricType(typ.String())
+		if err != nil || got != typ {
+			t.Errorf("ParseMetricType(%q) = %d, %v, want %d", typ.String(), got, err, typ)
+		}
+	}
+	if got, want := MetricType(42).String(), "MetricType(42)"; got != want {
+		t.Errorf("MetricType(42).String() = %q, want %q", got, want)
+	}
+	if _, err := ParseMetricType("sum"); err == nil {
+		t.Error("ParseMetricType(\"sum\"), got nil err")
+	}
+}
@@ -0,0 +1,233 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// Provenance-includes-location: https://github.com/prometheus/prometheus/blob/93e991ef7ed19cc997a9360c8016cac3767b8057/storage/remote/otlptranslator/prometheusremotewrite/histograms.go
+// Provenance-includes-license: Apache-2.0
+// Provenance-includes-copyright: Copyright The Prometheus Authors
+
+package otlptranslator
+
+import (
+	"fmt"
+	"math"
+)
+
+const (
+	// maxNativeHistogramSchema is the highest schema supported by Prometheus
+	// native histograms. Exponential histograms with a higher scale are
+	// downscaled.
+	maxNativeHistogramSchema = 8
+	// minNativeHistogramSchema is the lowest schema supported by Prometheus
+	// native histograms.
+	minNativeHistogramSchema = -4
+)
+
+// CounterResetHint tells Prometheus whether a native histogram is the
+// continuation of the previous one of its series.
+type CounterResetHint int
+
+const (
+	// CounterResetHintUnknown lets Prometheus detect counter resets.
+	CounterResetHintUnknown CounterResetHint = iota
+	// CounterResetHintReset marks a histogram following a counter reset.
+	CounterResetHintReset
+	// CounterResetHintNotReset marks a histogram that follows the previous one
+	// without counter reset.
+	CounterResetHintNotReset
+	// CounterResetHintGauge marks a gauge histogram, whose buckets are not
+	// cumulative.
+	CounterResetHintGauge
+)
+
+// BucketSpan is a run of consecutive native histogram buckets. Offset is the
+// index of the first bucket of the first span, and the gap to the end of the
+// previous span for the following ones.
+type BucketSpan struct {
+	Offset int32
+	Length uint32
+}
+
+// NativeHistogram is a Prometheus native histogram, with integer counts.
+// Bucket counts are delta-encoded: each value is the difference to the count
+// of the previous bucket.
+//
+// With schema s, the positive bucket of index i holds observations in
+// (2^((i-1)/2^s), 2^(i/2^s)], and the negative bucket of index i the
+// observations in [-2^(i/2^s), -2^((i-1)/2^s)).
+type NativeHistogram struct {
+	Schema          int32
+	ZeroThreshold   float64
+	ZeroCount       uint64
+	Count           uint64
+	Sum             float64
+	PositiveSpans   []BucketSpan
+	PositiveBuckets []int64
+	NegativeSpans   []BucketSpan
+	NegativeBuckets []int64
+	ResetHint       CounterResetHint
+}
+
+// IsStale reports whether the histogram is a staleness marker.
+func (h *NativeHistogram) IsStale() bool {
+	return IsStaleNaN(h.Sum)
+}
+
+// HistogramSample is a single Prometheus native histogram sample produced
+// from an OTLP exponential histogram data point.
+type HistogramSample struct {
+	// Name is the name of the series the sample belongs to.
+	Name string
+	// Labels holds the labels of the series, without the metric name.
+	Labels    Labels
+	Histogram NativeHistogram
+	// Timestamp is the sample timestamp in milliseconds since the Unix epoch.
+	Timestamp int64
+	// CreatedTimestamp is the time in milliseconds since the Unix epoch at
+	// which the series was created, as taken from the data point start time.
+	// It is 0 for gauge histograms, such as delta exponential histograms.
+	CreatedTimestamp int64
+	// Exemplars holds the exemplars of the sample, sorted by value.
+	Exemplars []SampleExemplar
+}
+
+// BuildExponentialHistogram builds the native histogram sample for an
+// exponential histogram data point. See BuildNumber for the meaning of extra.
+//
+// Exponential histograms with a scale above 8 are downscaled to 8, the
+// highest native histogram schema. Scales below -4 cannot be represented and
+// result in an error. Delta exponential histograms produce gauge histograms.
+func (b *SampleBuilder) BuildExponentialHistogram(metric Metric, extra Labels, p ExponentialHistogramDataPoint) (HistogramSample, error) {
+	name, ls, err := b.names(metric, extra, p.Attributes)
+	if err != nil {
+		return HistogramSample{}, err
+	}
+	h, err := ExponentialToNativeHistogram(p)
+	if err != nil {
+		return HistogramSample{}, fmt.Errorf("metric %q: %w", metric.Name, err)
+	}
+	if metric.Descriptor().PrometheusType() == PrometheusTypeGaugeHistogram {
+		h.ResetHint = CounterResetHintGauge
+	}
+	exemplars, err := b.pointExemplars(p.Flags, p.Exemplars)
+	if err != nil {
+		return HistogramSample{}, err
+	}
+	return HistogramSample{
+		Name:             name,
+		Labels:           ls,
+		Histogram:        h,
+		Timestamp:        nanosToMillis(p.TimeUnixNano),
+		CreatedTimestamp: nanosToMillis(histogramStartTime(metric, p.StartTimeUnixNano)),
+		Exemplars:        exemplars,
+	}, nil
+}
+
+// ExponentialToNativeHistogram converts an OTLP exponential histogram data
+// point into a Prometheus native histogram.
+//
+// OTLP bucket i of scale s holds observations in (base^i, base^(i+1)], while
+// native bucket i holds observations in (base^(i-1), base^i], so bucket
+// indexes are shifted by one. Data points flagged with
+// DataPointFlagNoRecordedValue produce a staleness marker.
+func ExponentialToNativeHistogram(p ExponentialHistogramDataPoint) (NativeHistogram, error) {
+	scale := p.Scale
+	if scale < minNativeHistogramSchema {
+		return NativeHistogram{}, fmt.Errorf("cannot convert exponential to native histogram: scale must be >= %d, was %d", minNativeHistogramSchema, scale)
+	}
+	var scaleDown int32
+	if scale > maxNativeHistogramSchema {
+		scaleDown = scale - maxNativeHistogramSchema
+		scale = maxNativeHistogramSchema
+	}
+
+	h := NativeHistogram{
+		Schema:        scale,
+		ZeroThreshold: p.ZeroThreshold,
+		ZeroCount:     p.ZeroCount,
+		Count:         p.Count,
+		Sum:           staleValue(p.Flags, p.Sum),
+	}
+	h.PositiveSpans, h.PositiveBuckets = nativeBucketsLayout(downscaleExponentialBuckets(p.Positive, scaleDown))
+	h.NegativeSpans, h.NegativeBuckets = nativeBucketsLayout(downscaleExponentialBuckets(p.Negative, scaleDown))
+	return h, nil
+}
+
+// nativeBucketsLayout converts dense OTLP buckets into native histogram spans
+// and delta-encoded bucket counts. Runs of more than two empty buckets are
+// skipped by starting a new span, shorter ones are kept as empty buckets as
+// they take less space than a new span.
+func nativeBucketsLayout(b ExponentialHistogramBuckets) ([]BucketSpan, []int64) {
+	var (
+		spans   []BucketSpan
+		deltas  []int64
+		prev    int64
+		lastIdx int32
+	)
+	appendBucket := func(count int64) {
+		spans[len(spans)-1].Length++
+		deltas = append(deltas, count-prev)
+		prev = count
+	}
+	for i, c := range b.BucketCounts {
+		if c == 0 {
+			continue
+		}
+		idx := b.Offset + int32(i) + 1
+		if len(spans) == 0 {
+			spans = append(spans, BucketSpan{Offset: idx})
+		} else {
+			gap := idx - lastIdx - 1
+			if gap > 2 {
+				spans = append(spans, BucketSpan{Offset: gap})
+			} else {
+				for range gap {
+					appendBucket(0)
+				}
+			}
+		}
+		appendBucket(int64(c))
+		lastIdx = idx
+	}
+	return spans, deltas
+}
+
+// forEachNativeBucket calls fn with the index and count of every bucket
+// described by the spans and delta-encoded counts.
+func forEachNativeBucket(spans []BucketSpan, deltas []int64, fn func(idx int32, count uint64)) {
+	var (
+		idx   int32
+		count int64
+		i     int
+	)
+	for si, span := range spans {
+		if si == 0 {
+			idx = span.Offset
+		} else {
+			idx += span.Offset
+		}
+		for range span.Length {
+			if i >= len(deltas) {
+				return
+			}
+			count += deltas[i]
+			i++
+			fn(idx, uint64(count))
+			idx++
+		}
+	}
+}
+
+// nativeBucketUpperBound returns the upper bound of the absolute value of
+// the observations in the bucket of the given index and schema.
+func nativeBucketUpperBound(idx, schema int32) float64 {
+	return math.Exp2(math.Ldexp(float64(idx), -int(schema)))
+}
@@ -0,0 +1,259 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlptranslator
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestExponentialToNativeHistogram(t *testing.T) {
+	tests := []struct {
+		name      string
+		point     ExponentialHistogramDataPoint
+		want      NativeHistogram
+		wantError string
+	}{
+		{
+			name: "spans and gaps",
+			point: ExponentialHistogramDataPoint{
+				Count: 5, Sum: 20, Scale: 0, ZeroCount: 1, ZeroThreshold: 1e-9,
+				Positive: ExponentialHistogramBuckets{Offset: 0, BucketCounts: []uint64{1, 0, 0, 0, 2, 0, 1}},
+			},
+			want: NativeHistogram{
+				Schema: 0, ZeroThreshold: 1e-9, ZeroCount: 1, Count: 5, Sum: 20,
+				// A gap of three buckets starts a new span, a gap of one does not.
+				PositiveSpans:   []BucketSpan{{Offset: 1, Length: 1}, {Offset: 3, Length: 3}},
+				PositiveBuckets: []int64{1, 1, -2, 1},
+			},
+		},
+		{
+			name: "negative buckets",
+			point: ExponentialHistogramDataPoint{
+				Count: 3, Sum: -3, Scale: 2,
+				Negative: ExponentialHistogramBuckets{Offset: -3, BucketCounts: []uint64{2, 1}},
+			},
+			want: NativeHistogram{
+				Schema: 2, Count: 3, Sum: -3,
+				NegativeSpans:   []BucketSpan{{Offset: -2, Length: 2}},
+				NegativeBuckets: []int64{2, -1},
+			},
+		},
+		{
+			name: "scale above 8 is downscaled",
+			point: ExponentialHistogramDataPoint{
+				Count: 4, Sum: 4, Scale: 10,
+				Positive: ExponentialHistogramBuckets{Offset: 0, BucketCounts: []uint64{1, 1, 1, 1}},
+			},
+			want: NativeHistogram{
+				Schema: 8, Count: 4, Sum: 4,
+				PositiveSpans:   []BucketSpan{{Offset: 1, Length: 1}},
+				PositiveBuckets: []int64{4},
+			},
+		},
+		{
+			name:      "scale below -4",
+			point:     ExponentialHistogramDataPoint{Scale: -5},
+			wantError: "cannot convert exponential to native histogram: scale must be >= -4, was -5",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExponentialToNativeHistogram(tt.point)
+			if tt.wantError != "" {
+				if err == nil || err.Error() != tt.wantError {
+					t.Fatalf("ExponentialToNativeHistogram() error = %v, want %q", err, tt.wantError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ExponentialToNativeHistogram() returned an error: %s", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExponentialToNativeHistogram() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSampleBuilder_BuildExponentialHistogram(t *testing.T) {
+	builder := SampleBuilder{MetricNamer: NewMetricNamer("", UnderscoreEscapingWithSuffixes)}
+	point := ExponentialHistogramDataPoint{
+		StartTimeUnixNano: 1_000_000_000,
+		TimeUnixNano:      2_000_000_000,
+		Count:             1,
+		Sum:               1.5,
+		Positive:          ExponentialHistogramBuckets{BucketCounts: []uint64{1}},
+	}
+
+	got, err := builder.BuildExponentialHistogram(Metric{Name: "latency", Unit: "s", Type: MetricTypeExponentialHistogram}, nil, point)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Name != "latency_seconds" || got.Timestamp != 2000 || got.CreatedTimestamp != 1000 {
+		t.Errorf("BuildExponentialHistogram() = %+v", got)
+	}
+	if got.Histogram.ResetHint != CounterResetHintUnknown {
+		t.Errorf("BuildExponentialHistogram() reset hint = %d, want %d", got.Histogram.ResetHint, CounterResetHintUnknown)
+	}
+
+	delta := Metric{Name: "latency", Unit: "s", Type: MetricTypeExponentialHistogram, Temporality: TemporalityDelta}
+	got, err = builder.BuildExponentialHistogram(delta, nil, point)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Histogram.ResetHint != CounterResetHintGauge {
+		t.Errorf("BuildExponentialHistogram() reset hint = %d, want %d", got.Histogram.ResetHint, CounterResetHintGauge)
+	}
+	if got.CreatedTimestamp != 0 {
+		t.Errorf("BuildExponentialHistogram() created timestamp = %d, want 0 for a gauge histogram", got.CreatedTimestamp)
+	}
+
+	point.Flags = DataPointFlagNoRecordedValue
+	got, err = builder.BuildExponentialHistogram(delta, nil, point)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Histogram.IsStale() {
+		t.Errorf("BuildExponentialHistogram() sum = %g, want a stale marker", got.Histogram.Sum)
+	}
+}
+
+func TestNativeHistogram_ClassicBuckets(t *testing.T) {
+	tests := []struct {
+		name      string
+		histogram NativeHistogram
+		layout    ClassicHistogramLayout
+		want      []ClassicBucket
+	}{
+		{
+			name: "explicit bounds are interpolated",
+			histogram: NativeHistogram{
+				Schema: 0, ZeroThreshold: 0.001, ZeroCount: 1, Count: 5,
+				// (1, 2] and (2, 4] hold two observations each.
+				PositiveSpans:   []BucketSpan{{Offset: 1, Length: 2}},
+				PositiveBuckets: []int64{2, 0},
+			},
+			layout: ClassicHistogramLayout{Bounds: []float64{4, 2, 1, 3, 2}},
+			want: []ClassicBucket{
+				{UpperBound: 1, Count: 1},
+				{UpperBound: 2, Count: 3},
+				{UpperBound: 3, Count: 3 + 2*math.Log2(1.5)},
+				{UpperBound: 4, Count: 5},
+				{UpperBound: math.Inf(1), Count: 5},
+			},
+		},
+		{
+			name: "coarser schema is exact",
+			histogram: NativeHistogram{
+				Schema: 1, Count: 4,
+				PositiveSpans:   []BucketSpan{{Offset: 1, Length: 4}},
+				PositiveBuckets: []int64{1, 0, 0, 0},
+			},
+			layout: ClassicHistogramLayout{Schema: 0},
+			want: []ClassicBucket{
+				{UpperBound: 2, Count: 2},
+				{UpperBound: 4, Count: 4},
+				{UpperBound: math.Inf(1), Count: 4},
+			},
+		},
+		{
+			name: "negative buckets",
+			histogram: NativeHistogram{
+				Schema: 0, Count: 2,
+				// [-2, -1) and [-4, -2) hold one observation each.
+				NegativeSpans:   []BucketSpan{{Offset: 1, Length: 2}},
+				NegativeBuckets: []int64{1, 0},
+			},
+			layout: ClassicHistogramLayout{Schema: 0},
+			want: []ClassicBucket{
+				{UpperBound: -2, Count: 1},
+				{UpperBound: -1, Count: 2},
+				{UpperBound: 0, Count: 2},
+				{UpperBound: math.Inf(1), Count: 2},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.histogram.ClassicBuckets(tt.layout)
+			if len(got) != len(tt.want) {
+				t.Fatalf("ClassicBuckets() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i].UpperBound != tt.want[i].UpperBound || math.Abs(got[i].Count-tt.want[i].Count) > 1e-9 {
+					t.Errorf("ClassicBuckets()[%d] = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSampleBuilder_BuildClassicHistogram(t *testing.T) {
+	builder := SampleBuilder{MetricNamer: NewMetricNamer("", UnderscoreEscapingWithSuffixes)}
+	got, err := builder.BuildClassicHistogram(
+		Metric{Name: "latency", Unit: "s", Type: MetricTypeExponentialHistogram},
+		nil,
+		ExponentialHistogramDataPoint{
+			TimeUnixNano: 2_000_000_000,
+			Count:        3, Sum: 5, Scale: 0,
+			// OTLP buckets (1, 2] and (2, 4].
+			Positive: ExponentialHistogramBuckets{Offset: 0, BucketCounts: []uint64{1, 2}},
+		},
+		ClassicHistogramLayout{Bounds: []float64{2, 4}},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Sample{
+		{Name: "latency_seconds_bucket", Labels: Labels{{Name: "le", Value: "2"}}, Value: 1, Timestamp: 2000},
+		{Name: "latency_seconds_bucket", Labels: Labels{{Name: "le", Value: "4"}}, Value: 3, Timestamp: 2000},
+		{Name: "latency_seconds_bucket", Labels: Labels{{Name: "le", Value: "+Inf"}}, Value: 3, Timestamp: 2000},
+		{Name: "latency_seconds_sum", Value: 5, Timestamp: 2000},
+		{Name: "latency_seconds_count", Value: 3, Timestamp: 2000},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("BuildClassicHistogram() = %+v, want %+v", got, want)
+	}
+}
+
+func TestSampleBuilder_BuildClassicHistogram_Delta(t *testing.T) {
+	builder := SampleBuilder{MetricNamer: NewMetricNamer("", UnderscoreEscapingWithSuffixes), CreatedSeries: true, StartTimeZeroSamples: true}
+	got, err := builder.BuildClassicHistogram(
+		Metric{Name: "latency", Unit: "s", Type: MetricTypeExponentialHistogram, Temporality: TemporalityDelta},
+		nil,
+		ExponentialHistogramDataPoint{
+			StartTimeUnixNano: 1_000_000_000,
+			TimeUnixNano:      2_000_000_000,
+			Count:             3, Sum: 5, Scale: 0,
+			// OTLP buckets (1, 2] and (2, 4].
+			Positive: ExponentialHistogramBuckets{Offset: 0, BucketCounts: []uint64{1, 2}},
+		},
+		ClassicHistogramLayout{Bounds: []float64{2, 4}},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Sample{
+		{Name: "latency_seconds_bucket", Labels: Labels{{Name: "le", Value: "2"}}, Value: 1, Timestamp: 2000},
+		{Name: "latency_seconds_bucket", Labels: Labels{{Name: "le", Value: "4"}}, Value: 3, Timestamp: 2000},
+		{Name: "latency_seconds_bucket", Labels: Labels{{Name: "le", Value: "+Inf"}}, Value: 3, Timestamp: 2000},
+		{Name: "latency_seconds_sum", Value: 5, Timestamp: 2000},
+		{Name: "latency_seconds_count", Value: 3, Timestamp: 2000},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("BuildClassicHistogram() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlp
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"math"
+	"strconv"
+)
+
+// attributeString converts a decoded AnyValue to the string representation
+// used for Prometheus label values, the same way the OpenTelemetry Collector
+// does: strings are kept as is, booleans and numbers are formatted, bytes are
+// base64-encoded and arrays and maps are JSON-encoded. Empty values are empty
+// strings.
+func attributeString(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return formatDouble(v)
+	case []byte:
+		return base64.StdEncoding.EncodeToString(v)
+	default:
+		// Maps are encoded with sorted keys, and nested bytes are base64-encoded.
+		b, err := json.Marshal(jsonValue(v))
+		if err != nil {
+			return ""
+		}
+		return string(b)
+	}
+}
+
+// jsonValue replaces the non-finite floats of an array or map value, which
+// JSON cannot represent, by their string representation.
+func jsonValue(v any) any {
+	switch v := v.(type) {
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return formatDouble(v)
+		}
+		return v
+	case []any:
+		res := make([]any, len(v))
+		for i, e := range v {
+			res[i] = jsonValue(e)
+		}
+		return res
+	case map[string]any:
+		res := make(map[string]any, len(v))
+		for k, e := range v {
+			res[k] = jsonValue(e)
+		}
+		return res
+	default:
+		return v
+	}
+}
+
+// formatDouble formats a double the way JSON encoders do, following the
+// ECMAScript number to string conversion.
+func formatDouble(f float64) string {
+	if abs := math.Abs(f); abs != 0 && (abs < 1e-6 || abs >= 1e21) && !math.IsInf(f, 0) {
+		// Clean up e-07 to e-7, like encoding/json. Positive exponents have
+		// two digits at least.
+		s := strconv.FormatFloat(f, 'e', -1, 64)
+		if n := len(s); n >= 4 && s[n-4] == 'e' && s[n-3] == '-' && s[n-2] == '0' {
+			s = s[:n-2] + s[n-1:]
+		}
+		return s
+	}
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
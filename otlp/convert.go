@@ -0,0 +1,313 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlp
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/prometheus/otlptranslator"
+)
+
+// Resource attributes identifying the target of a resource, as defined by the
+// OpenTelemetry to Prometheus compatibility specification:
+// https://github.com/open-telemetry/opentelemetry-specification/blob/v1.38.0/specification/compatibility/prometheus_and_openmetrics.md#resource-attributes-1
+const (
+	serviceNameKey       = "service.name"
+	serviceNamespaceKey  = "service.namespace"
+	serviceInstanceIDKey = "service.instance.id"
+
+	jobLabel      = "job"
+	instanceLabel = "instance"
+)
+
+// Appender receives the samples of translated metrics. It is implemented by
+// the writers of the exposition package and by the encoders of the
+// remotewrite package.
+type Appender interface {
+	Add(metric otlptranslator.Metric, samples ...otlptranslator.Sample) error
+}
+
+// HistogramAppender is an Appender supporting native histograms. Exponential
+// histograms are translated to native histograms for such appenders, and to
+// classic histograms otherwise.
+type HistogramAppender interface {
+	Appender
+	AddHistograms(metric otlptranslator.Metric, samples ...otlptranslator.HistogramSample) error
+}
+
+// Converter translates decoded OTLP requests into Prometheus samples, the
+// way the Prometheus OTLP receiver does.
+//
+// Every series gets the labels built from its data point attributes, plus:
+//   - job, set to the service.name resource attribute, prefixed with the
+//     service.namespace one and a slash if any, and instance, set to the
+//     service.instance.id resource attribute.
+//   - The promoted resource attributes. Their values are joined with the
+//     ones of data point attributes translating to the same label.
+//   - With PromoteScopeMetadata, otel_scope_name and otel_scope_version.
+//
+// For every resource holding attributes other than the identifying service.*
+// ones, a target_info gauge is added with the resource attributes as labels,
+// at the time of the latest data point of the resource.
+//
+// Example usage:
+//
+//	c := otlp.Converter{
+//		SampleBuilder: otlptranslator.SampleBuilder{MetricNamer: namer},
+//		PromoteResourceAttributes: []string{"k8s.namespace.name"},
+//	}
+//	w := exposition.NewTextWriter(namer)
+//	if err := c.Convert(req, w); err != nil {
+//		// handle err
+//	}
+type Converter struct {
+	SampleBuilder otlptranslator.SampleBuilder
+	// PromoteResourceAttributes lists the resource attributes added as labels
+	// to every series of the resource.
+	PromoteResourceAttributes []string
+	// PromoteAllResourceAttributes, if true, promotes all resource attributes
+	// but the ones listed in IgnoreResourceAttributes.
+	PromoteAllResourceAttributes bool
+	IgnoreResourceAttributes     []string
+	// KeepIdentifyingResourceAttributes, if true, keeps the service.name,
+	// service.namespace and service.instance.id attributes as target_info
+	// labels, in addition to job and instance.
+	KeepIdentifyingResourceAttributes bool
+	// PromoteScopeMetadata, if true, adds the otel_scope_name and
+	// otel_scope_version labels to every series.
+	PromoteScopeMetadata bool
+	// DisableTargetInfo, if true, omits the target_info metric.
+	DisableTargetInfo bool
+	// ClassicHistogramLayout is the layout of the classic histograms
+	// exponential histograms are translated to, for appenders that do not
+	// support native histograms.
+	ClassicHistogramLayout otlptranslator.ClassicHistogramLayout
+	// Accumulator, if set, converts delta sums and histograms to cumulative
+	// ones. Otherwise, they are translated with their delta temporality.
+	Accumulator *otlptranslator.DeltaAccumulator
+}
+
+// Convert translates all the metrics of the request, and adds their samples
+// to app. Metrics that cannot be translated are skipped, and their errors
+// returned joined once all the other ones were added.
+func (c *Converter) Convert(req *MetricsRequest, app Appender) error {
+	var errs []error
+	for _, rm := range req.ResourceMetrics {
+		errs = append(errs, c.convertResource(rm, app)...)
+	}
+	return errors.Join(errs...)
+}
+
+func (c *Converter) convertResource(rm ResourceMetrics, app Appender) []error {
+	var errs []error
+	identity := resourceLabels(rm.Resource)
+	promoted := c.promotedAttributes(rm.Resource)
+
+	var latest uint64
+	for _, sm := range rm.ScopeMetrics {
+		extra := identity
+		if c.PromoteScopeMetadata {
+			extra = extra.Merge(otlptranslator.NewLabels(
+				otlptranslator.Label{Name: otlptranslator.ScopeNameLabelKey, Value: sm.Scope.Name},
+				otlptranslator.Label{Name: otlptranslator.ScopeVersionLabelKey, Value: sm.Scope.Version},
+			))
+		}
+		for _, m := range sm.Metrics {
+			ts, err := c.convertMetric(m, extra, promoted, app)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("metric %q: %w", m.Name, err))
+			}
+			latest = max(latest, ts)
+		}
+	}
+
+	if !c.DisableTargetInfo && latest > 0 {
+		if err := c.addTargetInfo(rm.Resource, identity, latest, app); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", otlptranslator.TargetInfoMetricName, err))
+		}
+	}
+	return errs
+}
+
+// resourceLabels returns the job and instance labels of a resource.
+func resourceLabels(r Resource) otlptranslator.Labels {
+	var name, namespace, instance string
+	for _, a := range r.Attributes {
+		switch a.Key {
+		case serviceNameKey:
+			name = a.Value
+		case serviceNamespaceKey:
+			namespace = a.Value
+		case serviceInstanceIDKey:
+			instance = a.Value
+		}
+	}
+	var ls otlptranslator.Labels
+	if name != "" {
+		if namespace != "" {
+			name = namespace + "/" + name
+		}
+		ls = append(ls, otlptranslator.Label{Name: jobLabel, Value: name})
+	}
+	if instance != "" {
+		ls = append(ls, otlptranslator.Label{Name: instanceLabel, Value: instance})
+	}
+	return otlptranslator.NewLabels(ls...)
+}
+
+// promotedAttributes returns the resource attributes to add to the data
+// point attributes.
+func (c *Converter) promotedAttributes(r Resource) []otlptranslator.Attribute {
+	var promoted []otlptranslator.Attribute
+	for _, a := range r.Attributes {
+		if c.PromoteAllResourceAttributes && !slices.Contains(c.IgnoreResourceAttributes, a.Key) ||
+			!c.PromoteAllResourceAttributes && slices.Contains(c.PromoteResourceAttributes, a.Key) {
+			promoted = append(promoted, a)
+		}
+	}
+	return promoted
+}
+
+// addTargetInfo adds the target_info sample of a resource, unless it only
+// holds identifying attributes.
+func (c *Converter) addTargetInfo(r Resource, identity otlptranslator.Labels, timeUnixNano uint64, app Appender) error {
+	var attrs []otlptranslator.Attribute
+	nonIdentifying := 0
+	for _, a := range r.Attributes {
+		if !isIdentifying(a.Key) {
+			nonIdentifying++
+		} else if !c.KeepIdentifyingResourceAttributes {
+			continue
+		}
+		attrs = append(attrs, a)
+	}
+	if nonIdentifying == 0 {
+		return nil
+	}
+	metric := otlptranslator.Metric{
+		Name:        otlptranslator.TargetInfoMetricName,
+		Type:        otlptranslator.MetricTypeGauge,
+		Description: "Target metadata",
+	}
+	samples, err := c.SampleBuilder.BuildNumber(metric, identity, otlptranslator.NumberDataPoint{
+		Attributes:   attrs,
+		TimeUnixNano: timeUnixNano,
+		Value:        1,
+	})
+	if err != nil {
+		return err
+	}
+	return app.Add(metric, samples...)
+}
+
+// isIdentifying reports whether a resource attribute is one of the
+// attributes job and instance are built from.
+func isIdentifying(key string) bool {
+	return key == serviceNameKey || key == serviceNamespaceKey || key == serviceInstanceIDKey
+}
+
+// convertMetric translates the data points of a metric, and returns the time
+// of the latest one. It stops at the first error.
+func (c *Converter) convertMetric(m Metric, extra otlptranslator.Labels, promoted []otlptranslator.Attribute, app Appender) (uint64, error) {
+	metric := m.Metric
+	accumulate := c.Accumulator != nil && metric.Temporality == otlptranslator.TemporalityDelta
+	if accumulate {
+		metric.Temporality = otlptranslator.TemporalityCumulative
+	}
+	sb := &c.SampleBuilder
+
+	var latest uint64
+	for _, p := range m.NumberDataPoints {
+		p.Attributes = withPromoted(promoted, p.Attributes)
+		if accumulate && metric.Type.Kind() == otlptranslator.MetricKindSum {
+			var err error
+			if p, err = c.Accumulator.AddSum(m.Metric, extra, p); err != nil {
+				return latest, err
+			}
+		}
+		samples, err := sb.BuildNumber(metric, extra, p)
+		if err == nil {
+			err = app.Add(metric, samples...)
+		}
+		if err != nil {
+			return latest, err
+		}
+		latest = max(latest, p.TimeUnixNano)
+	}
+	for _, p := range m.HistogramDataPoints {
+		p.Attributes = withPromoted(promoted, p.Attributes)
+		if accumulate {
+			var err error
+			if p, err = c.Accumulator.AddHistogram(m.Metric, extra, p); err != nil {
+				return latest, err
+			}
+		}
+		samples, err := sb.BuildHistogram(metric, extra, p)
+		if err == nil {
+			err = app.Add(metric, samples...)
+		}
+		if err != nil {
+			return latest, err
+		}
+		latest = max(latest, p.TimeUnixNano)
+	}
+	for _, p := range m.ExponentialHistogramDataPoints {
+		p.Attributes = withPromoted(promoted, p.Attributes)
+		if accumulate {
+			var err error
+			if p, err = c.Accumulator.AddExponentialHistogram(m.Metric, extra, p); err != nil {
+				return latest, err
+			}
+		}
+		var err error
+		if ha, ok := app.(HistogramAppender); ok {
+			var h otlptranslator.HistogramSample
+			if h, err = sb.BuildExponentialHistogram(metric, extra, p); err == nil {
+				err = ha.AddHistograms(metric, h)
+			}
+		} else {
+			var samples []otlptranslator.Sample
+			if samples, err = sb.BuildClassicHistogram(metric, extra, p, c.ClassicHistogramLayout); err == nil {
+				err = app.Add(metric, samples...)
+			}
+		}
+		if err != nil {
+			return latest, err
+		}
+		latest = max(latest, p.TimeUnixNano)
+	}
+	for _, p := range m.SummaryDataPoints {
+		p.Attributes = withPromoted(promoted, p.Attributes)
+		samples, err := sb.BuildSummary(metric, extra, p)
+		if err == nil {
+			err = app.Add(metric, samples...)
+		}
+		if err != nil {
+			return latest, err
+		}
+		latest = max(latest, p.TimeUnixNano)
+	}
+	return latest, nil
+}
+
+// withPromoted returns the data point attributes, preceded by the promoted
+// resource attributes.
+func withPromoted(promoted, attrs []otlptranslator.Attribute) []otlptranslator.Attribute {
+	if len(promoted) == 0 {
+		return attrs
+	}
+	return append(slices.Clip(promoted), attrs...)
+}
@@ -0,0 +1,223 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlp
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/otlptranslator"
+)
+
+// recorder is an Appender recording samples as `name{labels} value ts`
+// strings.
+type recorder struct {
+	samples []string
+}
+
+func (r *recorder) Add(_ otlptranslator.Metric, samples ...otlptranslator.Sample) error {
+	for _, s := range samples {
+		r.samples = append(r.samples, fmt.Sprintf("%s%s %v %d", s.Name, s.Labels, s.Value, s.Timestamp))
+	}
+	return nil
+}
+
+func (r *recorder) recorded() []string {
+	return r.samples
+}
+
+// histogramRecorder is a recorder supporting native histograms.
+type histogramRecorder struct {
+	recorder
+}
+
+func (r *histogramRecorder) AddHistograms(_ otlptranslator.Metric, samples ...otlptranslator.HistogramSample) error {
+	for _, s := range samples {
+		r.samples = append(r.samples, fmt.Sprintf("%s%s native(count=%d) %d", s.Name, s.Labels, s.Histogram.Count, s.Timestamp))
+	}
+	return nil
+}
+
+func TestConverter(t *testing.T) {
+	namer := otlptranslator.NewMetricNamer("", otlptranslator.UnderscoreEscapingWithSuffixes)
+	resource := Resource{Attributes: []otlptranslator.Attribute{
+		{Key: "service.name", Value: "api"},
+		{Key: "service.namespace", Value: "shop"},
+		{Key: "service.instance.id", Value: "pod-1"},
+		{Key: "k8s.namespace.name", Value: "prod"},
+		{Key: "host.name", Value: "node-1"},
+	}}
+	counter := Metric{
+		Metric: otlptranslator.Metric{Name: "http.requests", Type: otlptranslator.MetricTypeMonotonicCounter, Temporality: otlptranslator.TemporalityCumulative},
+		NumberDataPoints: []otlptranslator.NumberDataPoint{{
+			Attributes:   []otlptranslator.Attribute{{Key: "http.method", Value: "GET"}, {Key: "k8s.namespace.name", Value: "override"}},
+			TimeUnixNano: 2_000_000_000,
+			Value:        3,
+		}},
+	}
+	expHistogram := Metric{
+		Metric: otlptranslator.Metric{Name: "rpc.duration", Unit: "s", Type: otlptranslator.MetricTypeExponentialHistogram},
+		ExponentialHistogramDataPoints: []otlptranslator.ExponentialHistogramDataPoint{{
+			TimeUnixNano: 3_000_000_000,
+			Count:        2,
+			Sum:          3,
+			Positive:     otlptranslator.ExponentialHistogramBuckets{Offset: 0, BucketCounts: []uint64{2}},
+		}},
+	}
+	req := &MetricsRequest{ResourceMetrics: []ResourceMetrics{{
+		Resource: resource,
+		ScopeMetrics: []ScopeMetrics{{
+			Scope:   Scope{Name: "http", Version: "1.0"},
+			Metrics: []Metric{counter, expHistogram},
+		}},
+	}}}
+
+	tests := []struct {
+		name      string
+		converter Converter
+		app       interface {
+			Appender
+			recorded() []string
+		}
+		want []string
+	}{
+		{
+			name:      "defaults",
+			converter: Converter{},
+			app:       &recorder{},
+			want: []string{
+				`http_requests_total{http_method="GET", instance="pod-1", job="shop/api", k8s_namespace_name="override"} 3 2000`,
+				`rpc_duration_seconds_bucket{instance="pod-1", job="shop/api", le="2"} 2 3000`,
+				`rpc_duration_seconds_bucket{instance="pod-1", job="shop/api", le="+Inf"} 2 3000`,
+				`rpc_duration_seconds_sum{instance="pod-1", job="shop/api"} 3 3000`,
+				`rpc_duration_seconds_count{instance="pod-1", job="shop/api"} 2 3000`,
+				`target_info{host_name="node-1", instance="pod-1", job="shop/api", k8s_namespace_name="prod"} 1 3000`,
+			},
+		},
+		{
+			name: "promotion and native histograms",
+			converter: Converter{
+				PromoteResourceAttributes:         []string{"k8s.namespace.name"},
+				PromoteScopeMetadata:              true,
+				KeepIdentifyingResourceAttributes: true,
+			},
+			app: &histogramRecorder{},
+			want: []string{
+				`http_requests_total{http_method="GET", instance="pod-1", job="shop/api", k8s_namespace_name="prod;override", otel_scope_name="http", otel_scope_version="1.0"} 3 2000`,
+				`rpc_duration_seconds{instance="pod-1", job="shop/api", k8s_namespace_name="prod", otel_scope_name="http", otel_scope_version="1.0"} native(count=2) 3000`,
+				`target_info{host_name="node-1", instance="pod-1", job="shop/api", k8s_namespace_name="prod", service_instance_id="pod-1", service_name="api", service_namespace="shop"} 1 3000`,
+			},
+		},
+		{
+			name: "promote all but ignored, without target_info",
+			converter: Converter{
+				PromoteAllResourceAttributes: true,
+				IgnoreResourceAttributes:     []string{"service.name", "service.namespace", "service.instance.id", "k8s.namespace.name"},
+				DisableTargetInfo:            true,
+			},
+			app: &histogramRecorder{},
+			want: []string{
+				`http_requests_total{host_name="node-1", http_method="GET", instance="pod-1", job="shop/api", k8s_namespace_name="override"} 3 2000`,
+				`rpc_duration_seconds{host_name="node-1", instance="pod-1", job="shop/api"} native(count=2) 3000`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.converter.SampleBuilder = otlptranslator.SampleBuilder{MetricNamer: namer}
+			if err := tt.converter.Convert(req, tt.app); err != nil {
+				t.Fatal(err)
+			}
+			if got := tt.app.recorded(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestConverter_TargetInfo(t *testing.T) {
+	namer := otlptranslator.NewMetricNamer("app", otlptranslator.UnderscoreEscapingWithSuffixes)
+	gauge := Metric{
+		Metric:           otlptranslator.Metric{Name: "up", Type: otlptranslator.MetricTypeGauge},
+		NumberDataPoints: []otlptranslator.NumberDataPoint{{TimeUnixNano: 1_000_000_000, Value: 1}},
+	}
+	tests := []struct {
+		name     string
+		resource []otlptranslator.Attribute
+		metrics  []Metric
+		want     []string
+	}{
+		{
+			name:     "identifying attributes only",
+			resource: []otlptranslator.Attribute{{Key: "service.name", Value: "api"}},
+			metrics:  []Metric{gauge},
+			want:     []string{`app_up{job="api"} 1 1000`},
+		},
+		{
+			name:     "no data points",
+			resource: []otlptranslator.Attribute{{Key: "host.name", Value: "node-1"}},
+			metrics:  []Metric{{Metric: gauge.Metric}},
+		},
+		{
+			name:     "namespaced",
+			resource: []otlptranslator.Attribute{{Key: "host.name", Value: "node-1"}},
+			metrics:  []Metric{gauge},
+			want:     []string{`app_up{} 1 1000`, `app_target_info{host_name="node-1"} 1 1000`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Converter{SampleBuilder: otlptranslator.SampleBuilder{MetricNamer: namer}}
+			var r recorder
+			req := &MetricsRequest{ResourceMetrics: []ResourceMetrics{{
+				Resource:     Resource{Attributes: tt.resource},
+				ScopeMetrics: []ScopeMetrics{{Metrics: tt.metrics}},
+			}}}
+			if err := c.Convert(req, &r); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(r.samples, tt.want) {
+				t.Errorf("got %q, want %q", r.samples, tt.want)
+			}
+		})
+	}
+}
+
+func TestConverter_DeltaToCumulative(t *testing.T) {
+	namer := otlptranslator.NewMetricNamer("", otlptranslator.UnderscoreEscapingWithSuffixes)
+	c := Converter{
+		SampleBuilder:     otlptranslator.SampleBuilder{MetricNamer: namer},
+		Accumulator:       otlptranslator.NewDeltaAccumulator(namer, otlptranslator.LabelNamer{}, otlptranslator.DeltaAccumulatorOptions{}),
+		DisableTargetInfo: true,
+	}
+	delta := func(start, end uint64, v float64) *MetricsRequest {
+		return &MetricsRequest{ResourceMetrics: []ResourceMetrics{{ScopeMetrics: []ScopeMetrics{{Metrics: []Metric{{
+			Metric:           otlptranslator.Metric{Name: "jobs", Type: otlptranslator.MetricTypeMonotonicCounter, Temporality: otlptranslator.TemporalityDelta},
+			NumberDataPoints: []otlptranslator.NumberDataPoint{{StartTimeUnixNano: start, TimeUnixNano: end, Value: v}},
+		}}}}}}}
+	}
+	var r recorder
+	for _, req := range []*MetricsRequest{delta(1e9, 2e9, 3), delta(2e9, 3e9, 4), delta(2e9, 3e9, 4)} {
+		err := c.Convert(req, &r)
+		if err != nil && !strings.Contains(err.Error(), `metric "jobs": out of order delta data point`) {
+			t.Fatal(err)
+		}
+	}
+	want := []string{"jobs_total{} 3 2000", "jobs_total{} 7 3000"}
+	if !reflect.DeepEqual(r.samples, want) {
+		t.Errorf("got %q, want %q", r.samples, want)
+	}
+}
@@ -0,0 +1,28 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package otlp decodes OTLP metrics export requests, encoded in protobuf or in
+// JSON, into the otlptranslator model, without depending on the OpenTelemetry
+// Collector nor on a protobuf runtime.
+//
+// Every decoded metric comes with a ready-to-use otlptranslator.Metric, so
+// that MetricNamer and SampleBuilder can be driven directly from raw OTLP/HTTP
+// bodies.
+//
+// Main components:
+//   - DecodeMetricsRequest: Decodes protobuf ExportMetricsServiceRequest messages
+//   - DecodeMetricsRequestJSON: Decodes OTLP/JSON ExportMetricsServiceRequest messages
+//   - JSONDecoder: Decodes streams of OTLP/JSON requests, such as the output of the Collector file exporter
+//   - Converter: Translates decoded requests into samples, with resource attribute promotion and target_info
+//   - PrometheusConverter: Converts scraped Prometheus and OpenMetrics families back into requests
+package otlp
@@ -0,0 +1,558 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlp
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+
+	"github.com/prometheus/otlptranslator"
+)
+
+// JSONContentType is the content type of JSON-encoded OTLP/HTTP requests.
+const JSONContentType = "application/json"
+
+// DecodeMetricsRequestJSON decodes a JSON-encoded
+// ExportMetricsServiceRequest, as sent by OTLP/HTTP exporters.
+//
+// It follows the OTLP/JSON encoding: keys are lowerCamelCase field names,
+// 64-bit integers are decimal strings, trace and span IDs are hex strings and
+// enums are integers. Numbers are also accepted for 64-bit integers, and enum
+// names for aggregation temporalities, as produced by other protobuf JSON
+// encoders. Unknown fields are skipped.
+//
+// The resulting request is the same as the one DecodeMetricsRequest would
+// return for the protobuf encoding of the payload.
+func DecodeMetricsRequestJSON(b []byte) (*MetricsRequest, error) {
+	var req jsonMetricsRequest
+	if err := json.Unmarshal(b, &req); err != nil {
+		return nil, fmt.Errorf("otlp: decoding JSON request: %w", err)
+	}
+	return req.convert()
+}
+
+// JSONDecoder reads a stream of JSON-encoded ExportMetricsServiceRequest
+// messages, such as the JSON lines written by the file exporter of the
+// OpenTelemetry Collector.
+//
+// Example usage:
+//
+//	d := otlp.NewJSONDecoder(f)
+//	for {
+//		req, err := d.Decode()
+//		if errors.Is(err, io.EOF) {
+//			break
+//		}
+//		if err != nil {
+//			// handle err
+//		}
+//		// ...
+//	}
+type JSONDecoder struct {
+	dec *json.Decoder
+}
+
+// NewJSONDecoder creates a JSONDecoder reading from r.
+func NewJSONDecoder(r io.Reader) *JSONDecoder {
+	return &JSONDecoder{dec: json.NewDecoder(r)}
+}
+
+// Decode decodes the next request of the stream. It returns io.EOF when the
+// stream ends.
+func (d *JSONDecoder) Decode() (*MetricsRequest, error) {
+	var req jsonMetricsRequest
+	if err := d.dec.Decode(&req); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("otlp: decoding JSON request: %w", err)
+	}
+	return req.convert()
+}
+
+type jsonMetricsRequest struct {
+	ResourceMetrics []struct {
+		Resource struct {
+			Attributes []jsonKeyValue `json:"attributes"`
+		} `json:"resource"`
+		ScopeMetrics []struct {
+			Scope struct {
+				Name       string         `json:"name"`
+				Version    string         `json:"version"`
+				Attributes []jsonKeyValue `json:"attributes"`
+			} `json:"scope"`
+			Metrics   []jsonMetric `json:"metrics"`
+			SchemaURL string       `json:"schemaUrl"`
+		} `json:"scopeMetrics"`
+		SchemaURL string `json:"schemaUrl"`
+	} `json:"resourceMetrics"`
+}
+
+func (r *jsonMetricsRequest) convert() (*MetricsRequest, error) {
+	req := &MetricsRequest{}
+	for _, jrm := range r.ResourceMetrics {
+		rm := ResourceMetrics{
+			Resource:  Resource{Attributes: convertAttributes(jrm.Resource.Attributes)},
+			SchemaURL: jrm.SchemaURL,
+		}
+		for _, jsm := range jrm.ScopeMetrics {
+			sm := ScopeMetrics{
+				Scope: Scope{
+					Name:       jsm.Scope.Name,
+					Version:    jsm.Scope.Version,
+					Attributes: convertAttributes(jsm.Scope.Attributes),
+				},
+				SchemaURL: jsm.SchemaURL,
+			}
+			for _, jm := range jsm.Metrics {
+				m, err := jm.convert()
+				if err != nil {
+					return nil, err
+				}
+				sm.Metrics = append(sm.Metrics, m)
+			}
+			rm.ScopeMetrics = append(rm.ScopeMetrics, sm)
+		}
+		req.ResourceMetrics = append(req.ResourceMetrics, rm)
+	}
+	return req, nil
+}
+
+type jsonMetric struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Unit        string `json:"unit"`
+	Gauge       *struct {
+		DataPoints []jsonNumberDataPoint `json:"dataPoints"`
+	} `json:"gauge"`
+	Sum *struct {
+		DataPoints             []jsonNumberDataPoint `json:"dataPoints"`
+		AggregationTemporality jsonTemporality       `json:"aggregationTemporality"`
+		IsMonotonic            bool                  `json:"isMonotonic"`
+	} `json:"sum"`
+	Histogram *struct {
+		DataPoints             []jsonHistogramDataPoint `json:"dataPoints"`
+		AggregationTemporality jsonTemporality          `json:"aggregationTemporality"`
+	} `json:"histogram"`
+	ExponentialHistogram *struct {
+		DataPoints             []jsonExponentialHistogramDataPoint `json:"dataPoints"`
+		AggregationTemporality jsonTemporality                     `json:"aggregationTemporality"`
+	} `json:"exponentialHistogram"`
+	Summary *struct {
+		DataPoints []jsonSummaryDataPoint `json:"dataPoints"`
+	} `json:"summary"`
+}
+
+func (jm *jsonMetric) convert() (Metric, error) {
+	m := Metric{Metric: otlptranslator.Metric{Name: jm.Name, Unit: jm.Unit, Description: jm.Description}}
+	var kinds int
+	if jm.Gauge != nil {
+		kinds++
+		m.Type = otlptranslator.MetricTypeGauge
+		for _, jdp := range jm.Gauge.DataPoints {
+			m.NumberDataPoints = append(m.NumberDataPoints, jdp.convert())
+		}
+	}
+	if jm.Sum != nil {
+		kinds++
+		m.Type = otlptranslator.MetricTypeNonMonotonicCounter
+		if jm.Sum.IsMonotonic {
+			m.Type = otlptranslator.MetricTypeMonotonicCounter
+		}
+		m.Temporality = otlptranslator.Temporality(jm.Sum.AggregationTemporality)
+		for _, jdp := range jm.Sum.DataPoints {
+			m.NumberDataPoints = append(m.NumberDataPoints, jdp.convert())
+		}
+	}
+	if jm.Histogram != nil {
+		kinds++
+		m.Type = otlptranslator.MetricTypeHistogram
+		m.Temporality = otlptranslator.Temporality(jm.Histogram.AggregationTemporality)
+		for _, jdp := range jm.Histogram.DataPoints {
+			dp := jdp.convert()
+			if err := checkBucketCounts(dp); err != nil {
+				return m, err
+			}
+			m.HistogramDataPoints = append(m.HistogramDataPoints, dp)
+		}
+	}
+	if jm.ExponentialHistogram != nil {
+		kinds++
+		m.Type = otlptranslator.MetricTypeExponentialHistogram
+		m.Temporality = otlptranslator.Temporality(jm.ExponentialHistogram.AggregationTemporality)
+		for _, jdp := range jm.ExponentialHistogram.DataPoints {
+			m.ExponentialHistogramDataPoints = append(m.ExponentialHistogramDataPoints, jdp.convert())
+		}
+	}
+	if jm.Summary != nil {
+		kinds++
+		m.Type = otlptranslator.MetricTypeSummary
+		for _, jdp := range jm.Summary.DataPoints {
+			m.SummaryDataPoints = append(m.SummaryDataPoints, jdp.convert())
+		}
+	}
+	if kinds > 1 {
+		return m, fmt.Errorf("otlp: metric %q holds %d kinds of data points", m.Name, kinds)
+	}
+	return m, nil
+}
+
+type jsonNumberDataPoint struct {
+	Attributes        []jsonKeyValue `json:"attributes"`
+	StartTimeUnixNano jsonUint64     `json:"startTimeUnixNano"`
+	TimeUnixNano      jsonUint64     `json:"timeUnixNano"`
+	AsDouble          *jsonDouble    `json:"asDouble"`
+	AsInt             *jsonInt64     `json:"asInt"`
+	Exemplars         []jsonExemplar `json:"exemplars"`
+	Flags             uint32         `json:"flags"`
+}
+
+func (jdp *jsonNumberDataPoint) convert() otlptranslator.NumberDataPoint {
+	dp := otlptranslator.NumberDataPoint{
+		Attributes:        convertAttributes(jdp.Attributes),
+		StartTimeUnixNano: uint64(jdp.StartTimeUnixNano),
+		TimeUnixNano:      uint64(jdp.TimeUnixNano),
+		Value:             numberValueJSON(jdp.AsDouble, jdp.AsInt),
+		Exemplars:         convertExemplars(jdp.Exemplars),
+		Flags:             otlptranslator.DataPointFlags(jdp.Flags),
+	}
+	return dp
+}
+
+type jsonHistogramDataPoint struct {
+	Attributes        []jsonKeyValue `json:"attributes"`
+	StartTimeUnixNano jsonUint64     `json:"startTimeUnixNano"`
+	TimeUnixNano      jsonUint64     `json:"timeUnixNano"`
+	Count             jsonUint64     `json:"count"`
+	Sum               jsonDouble     `json:"sum"`
+	BucketCounts      []jsonUint64   `json:"bucketCounts"`
+	ExplicitBounds    []jsonDouble   `json:"explicitBounds"`
+	Exemplars         []jsonExemplar `json:"exemplars"`
+	Flags             uint32         `json:"flags"`
+}
+
+func (jdp *jsonHistogramDataPoint) convert() otlptranslator.HistogramDataPoint {
+	dp := otlptranslator.HistogramDataPoint{
+		Attributes:        convertAttributes(jdp.Attributes),
+		StartTimeUnixNano: uint64(jdp.StartTimeUnixNano),
+		TimeUnixNano:      uint64(jdp.TimeUnixNano),
+		Count:             uint64(jdp.Count),
+		Sum:               float64(jdp.Sum),
+		Exemplars:         convertExemplars(jdp.Exemplars),
+		Flags:             otlptranslator.DataPointFlags(jdp.Flags),
+	}
+	for _, c := range jdp.BucketCounts {
+		dp.BucketCounts = append(dp.BucketCounts, uint64(c))
+	}
+	for _, b := range jdp.ExplicitBounds {
+		dp.ExplicitBounds = append(dp.ExplicitBounds, float64(b))
+	}
+	return dp
+}
+
+type jsonExponentialHistogramDataPoint struct {
+	Attributes        []jsonKeyValue `json:"attributes"`
+	StartTimeUnixNano jsonUint64     `json:"startTimeUnixNano"`
+	TimeUnixNano      jsonUint64     `json:"timeUnixNano"`
+	Count             jsonUint64     `json:"count"`
+	Sum               jsonDouble     `json:"sum"`
+	Scale             int32          `json:"scale"`
+	ZeroCount         jsonUint64     `json:"zeroCount"`
+	Positive          jsonBuckets    `json:"positive"`
+	Negative          jsonBuckets    `json:"negative"`
+	Flags             uint32         `json:"flags"`
+	Exemplars         []jsonExemplar `json:"exemplars"`
+	ZeroThreshold     jsonDouble     `json:"zeroThreshold"`
+}
+
+type jsonBuckets struct {
+	Offset       int32        `json:"offset"`
+	BucketCounts []jsonUint64 `json:"bucketCounts"`
+}
+
+func (jb jsonBuckets) convert() otlptranslator.ExponentialHistogramBuckets {
+	b := otlptranslator.ExponentialHistogramBuckets{Offset: jb.Offset}
+	for _, c := range jb.BucketCounts {
+		b.BucketCounts = append(b.BucketCounts, uint64(c))
+	}
+	return b
+}
+
+func (jdp *jsonExponentialHistogramDataPoint) convert() otlptranslator.ExponentialHistogramDataPoint {
+	return otlptranslator.ExponentialHistogramDataPoint{
+		Attributes:        convertAttributes(jdp.Attributes),
+		StartTimeUnixNano: uint64(jdp.StartTimeUnixNano),
+		TimeUnixNano:      uint64(jdp.TimeUnixNano),
+		Count:             uint64(jdp.Count),
+		Sum:               float64(jdp.Sum),
+		Scale:             jdp.Scale,
+		ZeroCount:         uint64(jdp.ZeroCount),
+		ZeroThreshold:     float64(jdp.ZeroThreshold),
+		Positive:          jdp.Positive.convert(),
+		Negative:          jdp.Negative.convert(),
+		Exemplars:         convertExemplars(jdp.Exemplars),
+		Flags:             otlptranslator.DataPointFlags(jdp.Flags),
+	}
+}
+
+type jsonSummaryDataPoint struct {
+	Attributes        []jsonKeyValue `json:"attributes"`
+	StartTimeUnixNano jsonUint64     `json:"startTimeUnixNano"`
+	TimeUnixNano      jsonUint64     `json:"timeUnixNano"`
+	Count             jsonUint64     `json:"count"`
+	Sum               jsonDouble     `json:"sum"`
+	QuantileValues    []struct {
+		Quantile jsonDouble `json:"quantile"`
+		Value    jsonDouble `json:"value"`
+	} `json:"quantileValues"`
+	Flags uint32 `json:"flags"`
+}
+
+func (jdp *jsonSummaryDataPoint) convert() otlptranslator.SummaryDataPoint {
+	dp := otlptranslator.SummaryDataPoint{
+		Attributes:        convertAttributes(jdp.Attributes),
+		StartTimeUnixNano: uint64(jdp.StartTimeUnixNano),
+		TimeUnixNano:      uint64(jdp.TimeUnixNano),
+		Count:             uint64(jdp.Count),
+		Sum:               float64(jdp.Sum),
+		Flags:             otlptranslator.DataPointFlags(jdp.Flags),
+	}
+	for _, q := range jdp.QuantileValues {
+		dp.QuantileValues = append(dp.QuantileValues, otlptranslator.ValueAtQuantile{Quantile: float64(q.Quantile), Value: float64(q.Value)})
+	}
+	return dp
+}
+
+type jsonExemplar struct {
+	FilteredAttributes []jsonKeyValue   `json:"filteredAttributes"`
+	TimeUnixNano       jsonUint64       `json:"timeUnixNano"`
+	AsDouble           *jsonDouble      `json:"asDouble"`
+	AsInt              *jsonInt64       `json:"asInt"`
+	SpanID             jsonID[[8]byte]  `json:"spanId"`
+	TraceID            jsonID[[16]byte] `json:"traceId"`
+}
+
+func convertExemplars(jes []jsonExemplar) []otlptranslator.Exemplar {
+	var res []otlptranslator.Exemplar
+	for _, je := range jes {
+		res = append(res, otlptranslator.Exemplar{
+			FilteredAttributes: convertAttributes(je.FilteredAttributes),
+			TimeUnixNano:       uint64(je.TimeUnixNano),
+			Value:              numberValueJSON(je.AsDouble, je.AsInt),
+			TraceID:            je.TraceID.id,
+			SpanID:             je.SpanID.id,
+		})
+	}
+	return res
+}
+
+// numberValueJSON returns the value of the asDouble or asInt field of a
+// number data point or exemplar.
+func numberValueJSON(asDouble *jsonDouble, asInt *jsonInt64) float64 {
+	switch {
+	case asDouble != nil:
+		return float64(*asDouble)
+	case asInt != nil:
+		return float64(*asInt)
+	default:
+		return 0
+	}
+}
+
+type jsonKeyValue struct {
+	Key   string       `json:"key"`
+	Value jsonAnyValue `json:"value"`
+}
+
+func convertAttributes(kvs []jsonKeyValue) []otlptranslator.Attribute {
+	var attrs []otlptranslator.Attribute
+	for _, kv := range kvs {
+		attrs = append(attrs, otlptranslator.Attribute{Key: kv.Key, Value: attributeString(kv.Value.value)})
+	}
+	return attrs
+}
+
+// jsonAnyValue is an AnyValue, decoded the same way as decodeAnyValue does.
+type jsonAnyValue struct {
+	value any
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (v *jsonAnyValue) UnmarshalJSON(b []byte) error {
+	var raw struct {
+		StringValue *string     `json:"stringValue"`
+		BoolValue   *bool       `json:"boolValue"`
+		IntValue    *jsonInt64  `json:"intValue"`
+		DoubleValue *jsonDouble `json:"doubleValue"`
+		ArrayValue  *struct {
+			Values []jsonAnyValue `json:"values"`
+		} `json:"arrayValue"`
+		KvlistValue *struct {
+			Values []jsonKeyValue `json:"values"`
+		} `json:"kvlistValue"`
+		// Bytes are base64-encoded, as encoding/json expects.
+		BytesValue []byte `json:"bytesValue"`
+	}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	switch {
+	case raw.StringValue != nil:
+		v.value = *raw.StringValue
+	case raw.BoolValue != nil:
+		v.value = *raw.BoolValue
+	case raw.IntValue != nil:
+		v.value = int64(*raw.IntValue)
+	case raw.DoubleValue != nil:
+		v.value = float64(*raw.DoubleValue)
+	case raw.ArrayValue != nil:
+		values := make([]any, 0, len(raw.ArrayValue.Values))
+		for _, e := range raw.ArrayValue.Values {
+			values = append(values, e.value)
+		}
+		v.value = values
+	case raw.KvlistValue != nil:
+		values := make(map[string]any, len(raw.KvlistValue.Values))
+		for _, kv := range raw.KvlistValue.Values {
+			values[kv.Key] = kv.Value.value
+		}
+		v.value = values
+	case raw.BytesValue != nil:
+		v.value = raw.BytesValue
+	}
+	return nil
+}
+
+// jsonUint64 is a uint64 encoded either as a decimal string or as a number.
+type jsonUint64 uint64
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (u *jsonUint64) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		return nil
+	}
+	v, err := strconv.ParseUint(string(unquote(b)), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid uint64 %s", b)
+	}
+	*u = jsonUint64(v)
+	return nil
+}
+
+// jsonInt64 is an int64 encoded either as a decimal string or as a number.
+type jsonInt64 int64
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (i *jsonInt64) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		return nil
+	}
+	v, err := strconv.ParseInt(string(unquote(b)), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid int64 %s", b)
+	}
+	*i = jsonInt64(v)
+	return nil
+}
+
+// jsonDouble is a double encoded as a number, or as one of the "NaN",
+// "Infinity" and "-Infinity" strings.
+type jsonDouble float64
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (d *jsonDouble) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		return nil
+	}
+	switch string(b) {
+	case `"NaN"`:
+		*d = jsonDouble(math.NaN())
+	case `"Infinity"`:
+		*d = jsonDouble(math.Inf(1))
+	case `"-Infinity"`:
+		*d = jsonDouble(math.Inf(-1))
+	default:
+		v, err := strconv.ParseFloat(string(unquote(b)), 64)
+		if err != nil {
+			return fmt.Errorf("invalid double %s", b)
+		}
+		*d = jsonDouble(v)
+	}
+	return nil
+}
+
+// jsonTemporality is an AggregationTemporality, encoded as an integer or by
+// its name.
+type jsonTemporality otlptranslator.Temporality
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (t *jsonTemporality) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		return nil
+	}
+	switch string(b) {
+	case `"AGGREGATION_TEMPORALITY_UNSPECIFIED"`:
+		*t = jsonTemporality(otlptranslator.TemporalityUnspecified)
+	case `"AGGREGATION_TEMPORALITY_DELTA"`:
+		*t = jsonTemporality(otlptranslator.TemporalityDelta)
+	case `"AGGREGATION_TEMPORALITY_CUMULATIVE"`:
+		*t = jsonTemporality(otlptranslator.TemporalityCumulative)
+	default:
+		v, err := strconv.ParseUint(string(b), 10, 32)
+		if err != nil {
+			return fmt.Errorf("invalid aggregation temporality %s", b)
+		}
+		*t = jsonTemporality(temporality(v))
+	}
+	return nil
+}
+
+// jsonID is a trace or span ID, encoded as a hex string, which must either
+// be empty or have the exact length of the ID.
+type jsonID[T [8]byte | [16]byte] struct {
+	id T
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (id *jsonID[T]) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	decoded, err := hex.DecodeString(s)
+	if err != nil {
+		return fmt.Errorf("invalid hex ID %q", s)
+	}
+	if len(decoded) == 0 {
+		return nil
+	}
+	if len(decoded) != len(id.id) {
+		return fmt.Errorf("ID %q has %d bytes, want %d", s, len(decoded), len(id.id))
+	}
+	id.id = T(decoded)
+	return nil
+}
+
+// unquote removes the quotes around a JSON string, if any.
+func unquote(b []byte) []byte {
+	if len(b) >= 2 && b[0] == '"' && b[len(b)-1] == '"' {
+		return b[1 : len(b)-1]
+	}
+	return b
+}
@@ -0,0 +1,208 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlp
+
+import (
+	"errors"
+	"io"
+	"math"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/otlptranslator"
+)
+
+func TestJSONDecoder(t *testing.T) {
+	f, err := os.Open("testdata/metrics.jsonl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	d := NewJSONDecoder(f)
+
+	var reqs []*MetricsRequest
+	for {
+		req, err := d.Decode()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		reqs = append(reqs, req)
+	}
+	if len(reqs) != 2 {
+		t.Fatalf("decoded %d requests, want 2", len(reqs))
+	}
+
+	// The first request holds the same payload as testRequest.
+	want, err := DecodeMetricsRequest(testRequest())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(reqs[0], want) {
+		t.Errorf("Decode():\ngot  %+v\nwant %+v", reqs[0], want)
+	}
+
+	namer := otlptranslator.NewMetricNamer("", otlptranslator.UnderscoreEscapingWithSuffixes)
+	var names []string
+	for _, req := range reqs {
+		for _, rm := range req.ResourceMetrics {
+			for _, sm := range rm.ScopeMetrics {
+				for _, m := range sm.Metrics {
+					name, err := namer.Build(m.Metric)
+					if err != nil {
+						t.Fatal(err)
+					}
+					names = append(names, name)
+				}
+			}
+		}
+	}
+	wantNames := []string{
+		"http_server_requests_total",
+		"queue_size",
+		"http_server_duration_seconds",
+		"rpc_duration",
+		"gc_pause",
+		"jobs_processed_total",
+	}
+	if !reflect.DeepEqual(names, wantNames) {
+		t.Errorf("translated names = %q, want %q", names, wantNames)
+	}
+}
+
+func TestDecodeMetricsRequestJSON(t *testing.T) {
+	request := func(metric string) string {
+		return `{"resourceMetrics":[{"scopeMetrics":[{"metrics":[` + metric + `]}]}]}`
+	}
+	tests := []struct {
+		name  string
+		input string
+		want  Metric
+	}{
+		{
+			name:  "numbers for 64-bit integers",
+			input: request(`{"name":"a","gauge":{"dataPoints":[{"timeUnixNano":2000000000,"asInt":-3}]}}`),
+			want: Metric{
+				Metric:           otlptranslator.Metric{Name: "a", Type: otlptranslator.MetricTypeGauge},
+				NumberDataPoints: []otlptranslator.NumberDataPoint{{TimeUnixNano: 2_000_000_000, Value: -3}},
+			},
+		},
+		{
+			name:  "non-finite doubles",
+			input: request(`{"name":"a","gauge":{"dataPoints":[{"asDouble":"-Infinity"}]}}`),
+			want: Metric{
+				Metric:           otlptranslator.Metric{Name: "a", Type: otlptranslator.MetricTypeGauge},
+				NumberDataPoints: []otlptranslator.NumberDataPoint{{Value: math.Inf(-1)}},
+			},
+		},
+		{
+			name:  "non-monotonic delta sum",
+			input: request(`{"name":"a","sum":{"aggregationTemporality":"AGGREGATION_TEMPORALITY_DELTA"}}`),
+			want: Metric{Metric: otlptranslator.Metric{
+				Name:        "a",
+				Type:        otlptranslator.MetricTypeNonMonotonicCounter,
+				Temporality: otlptranslator.TemporalityDelta,
+			}},
+		},
+		{
+			name: "attribute values",
+			input: request(`{"name":"a","gauge":{"dataPoints":[{"attributes":[` +
+				`{"key":"bytes","value":{"bytesValue":"AQI="}},` +
+				`{"key":"double","value":{"doubleValue":2.5}},` +
+				`{"key":"list","value":{"arrayValue":{"values":[{"intValue":"1"},{"kvlistValue":{"values":[{"key":"k","value":{"stringValue":"v"}}]}}]}}},` +
+				`{"key":"empty","value":{}}` +
+				`]}]}}`),
+			want: Metric{
+				Metric: otlptranslator.Metric{Name: "a", Type: otlptranslator.MetricTypeGauge},
+				NumberDataPoints: []otlptranslator.NumberDataPoint{{Attributes: []otlptranslator.Attribute{
+					{Key: "bytes", Value: "AQI="},
+					{Key: "double", Value: "2.5"},
+					{Key: "list", Value: `[1,{"k":"v"}]`},
+					{Key: "empty", Value: ""},
+				}}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := DecodeMetricsRequestJSON([]byte(tt.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := req.ResourceMetrics[0].ScopeMetrics[0].Metrics[0]
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got  %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeMetricsRequestJSON_Errors(t *testing.T) {
+	request := func(metric string) string {
+		return `{"resourceMetrics":[{"scopeMetrics":[{"metrics":[` + metric + `]}]}]}`
+	}
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "invalid JSON",
+			input: `{"resourceMetrics":[`,
+			want:  "otlp: decoding JSON request: unexpected end of JSON input",
+		},
+		{
+			name:  "invalid uint64",
+			input: request(`{"gauge":{"dataPoints":[{"timeUnixNano":"-1"}]}}`),
+			want:  `invalid uint64 "-1"`,
+		},
+		{
+			name:  "invalid temporality",
+			input: request(`{"sum":{"aggregationTemporality":"DELTA"}}`),
+			want:  `invalid aggregation temporality "DELTA"`,
+		},
+		{
+			name:  "base64 trace id",
+			input: request(`{"gauge":{"dataPoints":[{"exemplars":[{"traceId":"AAAAAAAAAAAAAAAAAAAAAQ=="}]}]}}`),
+			want:  `invalid hex ID "AAAAAAAAAAAAAAAAAAAAAQ=="`,
+		},
+		{
+			name:  "short span id",
+			input: request(`{"gauge":{"dataPoints":[{"exemplars":[{"spanId":"0102"}]}]}}`),
+			want:  `ID "0102" has 2 bytes, want 8`,
+		},
+		{
+			name:  "several kinds of data points",
+			input: request(`{"name":"up","gauge":{},"summary":{}}`),
+			want:  `otlp: metric "up" holds 2 kinds of data points`,
+		},
+		{
+			name:  "bucket counts mismatch",
+			input: request(`{"histogram":{"dataPoints":[{"bucketCounts":["1"],"explicitBounds":[1]}]}}`),
+			want:  "otlp: decoding HistogramDataPoint: 1 bucket counts for 1 explicit bounds",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecodeMetricsRequestJSON([]byte(tt.input))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("DecodeMetricsRequestJSON() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlp
+
+import "github.com/prometheus/otlptranslator"
+
+// MetricsRequest is a decoded
+// opentelemetry.proto.collector.metrics.v1.ExportMetricsServiceRequest.
+type MetricsRequest struct {
+	ResourceMetrics []ResourceMetrics
+}
+
+// ResourceMetrics holds the metrics produced by a resource.
+type ResourceMetrics struct {
+	Resource     Resource
+	ScopeMetrics []ScopeMetrics
+	SchemaURL    string
+}
+
+// Resource is the entity producing metrics, such as a service instance.
+type Resource struct {
+	Attributes []otlptranslator.Attribute
+}
+
+// ScopeMetrics holds the metrics produced by an instrumentation scope.
+type ScopeMetrics struct {
+	Scope     Scope
+	Metrics   []Metric
+	SchemaURL string
+}
+
+// Scope is an instrumentation scope, usually a library.
+type Scope struct {
+	Name       string
+	Version    string
+	Attributes []otlptranslator.Attribute
+}
+
+// Metric is a decoded OTLP metric. Only the data points slice matching the
+// type of the metric is populated.
+type Metric struct {
+	// Metric holds the name, unit, type, temporality and description of the
+	// metric, ready to be passed to otlptranslator.
+	otlptranslator.Metric
+	NumberDataPoints               []otlptranslator.NumberDataPoint
+	HistogramDataPoints            []otlptranslator.HistogramDataPoint
+	ExponentialHistogramDataPoints []otlptranslator.ExponentialHistogramDataPoint
+	SummaryDataPoints              []otlptranslator.SummaryDataPoint
+}
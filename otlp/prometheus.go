@@ -0,0 +1,521 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlp
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/otlptranslator"
+	"github.com/prometheus/otlptranslator/exposition"
+)
+
+// PrometheusConverter converts metric families read from a Prometheus or
+// OpenMetrics exposition into an OTLP metrics request, reversing the
+// translation done by Converter:
+//   - Counters become monotonic cumulative sums named without `_total`.
+//   - Classic histograms are reassembled from their `_bucket`, `_sum` and
+//     `_count` samples, gauge histograms becoming delta histograms.
+//   - Summaries are rebuilt from their quantile, `_sum` and `_count` samples.
+//   - Gauges, untyped, info and stateset families become gauges.
+//   - `_created` samples set the start time of the data points.
+//   - The unit comes from the `# UNIT` line or, failing that, from a known
+//     unit suffix, as found by otlptranslator.CutUnitSuffix. The unit suffix
+//     is removed from the metric name, and the unit turned back into UCUM
+//     notation by UnitNamer.Parse.
+//
+// Series are grouped into resources by their job and instance labels, which
+// become the service.namespace, service.name and service.instance.id
+// attributes, joined with the labels of the matching target_info series.
+// target_info is not converted into a metric. The otel_scope_name and
+// otel_scope_version labels set the instrumentation scope.
+//
+// Names and label names are kept as exposed: an escaped name cannot be told
+// apart from one that was originally written with underscores.
+//
+// Example usage:
+//
+//	c := otlp.PrometheusConverter{ScrapeTime: time.Now()}
+//	req, err := c.Convert(families)
+//	if err != nil {
+//		// handle err, req holds the families that could be converted.
+//	}
+type PrometheusConverter struct {
+	// ScrapeTime is the time of the samples exposed without timestamp,
+	// usually the time they were scraped at.
+	ScrapeTime time.Time
+}
+
+// Convert converts the families into a metrics request. Families that cannot
+// be converted are skipped, and their errors returned joined along with the
+// request holding all the other ones.
+func (c PrometheusConverter) Convert(families []exposition.Family) (*MetricsRequest, error) {
+	b := requestBuilder{targets: map[string][]otlptranslator.Attribute{}, resources: map[string]*promResource{}}
+	var errs []error
+	for _, f := range families {
+		if isTargetInfo(f) {
+			b.addTargetInfo(f)
+		}
+	}
+	for _, f := range families {
+		if isTargetInfo(f) {
+			continue
+		}
+		if err := c.convertFamily(&b, f); err != nil {
+			errs = append(errs, fmt.Errorf("family %q: %w", f.Name, err))
+		}
+	}
+	return b.request(), errors.Join(errs...)
+}
+
+// isTargetInfo reports whether the family holds the target_info series, as
+// exposed in the Prometheus text format or as an OpenMetrics info family.
+func isTargetInfo(f exposition.Family) bool {
+	return f.Name == otlptranslator.TargetInfoMetricName ||
+		f.Type == infoType && f.Name+infoSuffix == otlptranslator.TargetInfoMetricName
+}
+
+const (
+	infoType     otlptranslator.PrometheusType = "info"
+	statesetType otlptranslator.PrometheusType = "stateset"
+	untypedType  otlptranslator.PrometheusType = "untyped"
+
+	infoSuffix = "_info"
+)
+
+// requestBuilder groups the converted metrics by resource and scope.
+type requestBuilder struct {
+	// targets holds the target_info attributes by resource key.
+	targets   map[string][]otlptranslator.Attribute
+	resources map[string]*promResource
+	order     []*promResource
+}
+
+type promResource struct {
+	resource Resource
+	scopes   map[[2]string]*promScope
+	order    []*promScope
+}
+
+type promScope struct {
+	scope   Scope
+	metrics map[string]*Metric
+	order   []*Metric
+}
+
+// resourceKey returns the key of the resource a series belongs to.
+func resourceKey(ls otlptranslator.Labels) string {
+	return ls.Get(jobLabel) + "\xff" + ls.Get(instanceLabel)
+}
+
+func (b *requestBuilder) addTargetInfo(f exposition.Family) {
+	for _, s := range f.Samples {
+		key := resourceKey(s.Labels)
+		for _, l := range s.Labels {
+			if l.Name != jobLabel && l.Name != instanceLabel {
+				b.targets[key] = append(b.targets[key], otlptranslator.Attribute{Key: l.Name, Value: l.Value})
+			}
+		}
+	}
+}
+
+// metric returns the metric of the given name in the resource and scope of a
+// series, creating them if needed.
+func (b *requestBuilder) metric(meta otlptranslator.Metric, ls otlptranslator.Labels) *Metric {
+	key := resourceKey(ls)
+	r, ok := b.resources[key]
+	if !ok {
+		r = &promResource{resource: Resource{Attributes: b.resourceAttributes(key, ls)}, scopes: map[[2]string]*promScope{}}
+		b.resources[key] = r
+		b.order = append(b.order, r)
+	}
+	scope := [2]string{ls.Get(otlptranslator.ScopeNameLabelKey), ls.Get(otlptranslator.ScopeVersionLabelKey)}
+	s, ok := r.scopes[scope]
+	if !ok {
+		s = &promScope{scope: Scope{Name: scope[0], Version: scope[1]}, metrics: map[string]*Metric{}}
+		r.scopes[scope] = s
+		r.order = append(r.order, s)
+	}
+	m, ok := s.metrics[meta.Name]
+	if !ok {
+		m = &Metric{Metric: meta}
+		s.metrics[meta.Name] = m
+		s.order = append(s.order, m)
+	}
+	return m
+}
+
+// resourceAttributes returns the attributes of the resource identified by
+// the job and instance labels, followed by its target_info attributes.
+func (b *requestBuilder) resourceAttributes(key string, ls otlptranslator.Labels) []otlptranslator.Attribute {
+	var attrs []otlptranslator.Attribute
+	if job := ls.Get(jobLabel); job != "" {
+		if namespace, name, ok := strings.Cut(job, "/"); ok {
+			attrs = append(attrs, otlptranslator.Attribute{Key: serviceNamespaceKey, Value: namespace})
+			job = name
+		}
+		attrs = append(attrs, otlptranslator.Attribute{Key: serviceNameKey, Value: job})
+	}
+	if instance := ls.Get(instanceLabel); instance != "" {
+		attrs = append(attrs, otlptranslator.Attribute{Key: serviceInstanceIDKey, Value: instance})
+	}
+	for _, a := range b.targets[key] {
+		if !slices.ContainsFunc(attrs, func(other otlptranslator.Attribute) bool { return other.Key == a.Key }) {
+			attrs = append(attrs, a)
+		}
+	}
+	return attrs
+}
+
+func (b *requestBuilder) request() *MetricsRequest {
+	req := &MetricsRequest{}
+	for _, r := range b.order {
+		rm := ResourceMetrics{Resource: r.resource}
+		for _, s := range r.order {
+			sm := ScopeMetrics{Scope: s.scope}
+			for _, m := range s.order {
+				sm.Metrics = append(sm.Metrics, *m)
+			}
+			rm.ScopeMetrics = append(rm.ScopeMetrics, sm)
+		}
+		req.ResourceMetrics = append(req.ResourceMetrics, rm)
+	}
+	return req
+}
+
+// promPoint gathers the samples of a family making a single data point.
+type promPoint struct {
+	// labels holds the labels of the series, without le and quantile.
+	labels    otlptranslator.Labels
+	timestamp int64
+	created   uint64
+	stale     bool
+
+	value     float64
+	sum       float64
+	count     float64
+	hasCount  bool
+	buckets   []promBucket
+	quantiles []otlptranslator.ValueAtQuantile
+	exemplars []otlptranslator.SampleExemplar
+}
+
+type promBucket struct {
+	upperBound float64
+	count      float64
+}
+
+func (c PrometheusConverter) convertFamily(b *requestBuilder, f exposition.Family) error {
+	meta, base, err := prometheusMetric(f)
+	if err != nil {
+		return err
+	}
+	points, err := groupPoints(f, base, meta.Type.Kind())
+	if err != nil {
+		return err
+	}
+	// Data points are only added once the whole family was converted.
+	converted := make([]func(*Metric), 0, len(points))
+	for _, p := range points {
+		add, err := c.dataPoint(meta, p)
+		if err != nil {
+			return fmt.Errorf("series %s: %w", p.labels, err)
+		}
+		converted = append(converted, add)
+	}
+	for i, p := range points {
+		converted[i](b.metric(meta, p.labels))
+	}
+	return nil
+}
+
+// prometheusMetric returns the OTLP metric of a family, and the name its
+// samples start with.
+func prometheusMetric(f exposition.Family) (otlptranslator.Metric, string, error) {
+	meta := otlptranslator.Metric{Description: f.Help}
+	base := f.Name
+	switch f.Type {
+	case otlptranslator.PrometheusTypeCounter:
+		meta.Type, meta.Temporality = otlptranslator.MetricTypeMonotonicCounter, otlptranslator.TemporalityCumulative
+		base = strings.TrimSuffix(f.Name, "_total")
+	case otlptranslator.PrometheusTypeGauge, otlptranslator.PrometheusTypeUnknown, untypedType, infoType, statesetType, "":
+		meta.Type = otlptranslator.MetricTypeGauge
+	case otlptranslator.PrometheusTypeHistogram:
+		meta.Type, meta.Temporality = otlptranslator.MetricTypeHistogram, otlptranslator.TemporalityCumulative
+	case otlptranslator.PrometheusTypeGaugeHistogram:
+		meta.Type, meta.Temporality = otlptranslator.MetricTypeHistogram, otlptranslator.TemporalityDelta
+	case otlptranslator.PrometheusTypeSummary:
+		meta.Type = otlptranslator.MetricTypeSummary
+	default:
+		return meta, "", fmt.Errorf("unsupported type %q", f.Type)
+	}
+
+	meta.Name = base
+	unitNamer := otlptranslator.UnitNamer{}
+	if f.Unit != "" {
+		meta.Name = strings.TrimSuffix(base, "_"+f.Unit)
+		meta.Unit = unitNamer.Parse(f.Unit)
+	} else if name, unit, ok := otlptranslator.CutUnitSuffix(base); ok && (unit != "ratio" || meta.Type == otlptranslator.MetricTypeGauge) {
+		meta.Name, meta.Unit = name, unitNamer.Parse(unit)
+	}
+	if f.Type == infoType {
+		base += infoSuffix
+	}
+	return meta, base, nil
+}
+
+// groupPoints groups the samples of a family by data point, in exposition
+// order.
+func groupPoints(f exposition.Family, base string, kind otlptranslator.MetricKind) ([]*promPoint, error) {
+	var (
+		points []*promPoint
+		byKey  = map[string]*promPoint{}
+	)
+	for _, s := range f.Samples {
+		suffix, ok := strings.CutPrefix(s.Name, base)
+		if !ok || !validSuffix(f.Type, suffix) {
+			return nil, fmt.Errorf("sample %q does not belong to the family", s.Name)
+		}
+		ls := s.Labels
+		var bound string
+		switch {
+		case kind == otlptranslator.MetricKindHistogram && suffix == "_bucket":
+			bound = ls.Get(otlptranslator.BucketLabel)
+			ls = without(ls, otlptranslator.BucketLabel)
+		case kind == otlptranslator.MetricKindSummary && suffix == "":
+			bound = ls.Get(otlptranslator.QuantileLabel)
+			ls = without(ls, otlptranslator.QuantileLabel)
+		}
+		key := ls.String()
+		p, ok := byKey[key]
+		if !ok {
+			p = &promPoint{labels: ls}
+			byKey[key] = p
+			points = append(points, p)
+		}
+		p.timestamp = max(p.timestamp, s.Timestamp)
+		if s.CreatedTimestamp > 0 && p.created == 0 {
+			p.created = uint64(s.CreatedTimestamp) * uint64(time.Millisecond)
+		}
+		if otlptranslator.IsStaleNaN(s.Value) {
+			p.stale = true
+			continue
+		}
+
+		switch suffix {
+		case createdSuffix:
+			if s.Value > 0 {
+				p.created = uint64(s.Value * float64(time.Second))
+			}
+		case "_sum", "_gsum":
+			p.sum = s.Value
+		case "_count", "_gcount":
+			p.count, p.hasCount = s.Value, true
+		case "_bucket":
+			upperBound, err := strconv.ParseFloat(bound, 64)
+			if err != nil {
+				return nil, fmt.Errorf("sample %s%s: invalid %s label %q", s.Name, s.Labels, otlptranslator.BucketLabel, bound)
+			}
+			p.buckets = append(p.buckets, promBucket{upperBound: upperBound, count: s.Value})
+			p.exemplars = append(p.exemplars, s.Exemplars...)
+		default:
+			if kind != otlptranslator.MetricKindSummary {
+				p.value = s.Value
+				p.exemplars = append(p.exemplars, s.Exemplars...)
+				break
+			}
+			quantile, err := strconv.ParseFloat(bound, 64)
+			if err != nil {
+				return nil, fmt.Errorf("sample %s%s: invalid %s label %q", s.Name, s.Labels, otlptranslator.QuantileLabel, bound)
+			}
+			p.quantiles = append(p.quantiles, otlptranslator.ValueAtQuantile{Quantile: quantile, Value: s.Value})
+		}
+	}
+	return points, nil
+}
+
+const createdSuffix = "_created"
+
+// validSuffix reports whether a family of the given type can have samples
+// with the given suffix.
+func validSuffix(typ otlptranslator.PrometheusType, suffix string) bool {
+	switch typ {
+	case otlptranslator.PrometheusTypeCounter:
+		return suffix == "" || suffix == "_total" || suffix == createdSuffix
+	case otlptranslator.PrometheusTypeHistogram:
+		return suffix == "_bucket" || suffix == "_sum" || suffix == "_count" || suffix == createdSuffix
+	case otlptranslator.PrometheusTypeGaugeHistogram:
+		return suffix == "_bucket" || suffix == "_gsum" || suffix == "_gcount" || suffix == "_sum" || suffix == "_count"
+	case otlptranslator.PrometheusTypeSummary:
+		return suffix == "" || suffix == "_sum" || suffix == "_count" || suffix == createdSuffix
+	default:
+		return suffix == ""
+	}
+}
+
+func without(ls otlptranslator.Labels, name string) otlptranslator.Labels {
+	return slices.DeleteFunc(slices.Clone(ls), func(l otlptranslator.Label) bool { return l.Name == name })
+}
+
+// dataPoint builds the data point of a series, returning a function adding it
+// to a metric.
+func (c PrometheusConverter) dataPoint(meta otlptranslator.Metric, p *promPoint) (func(*Metric), error) {
+	attrs := pointAttributes(p.labels)
+	ts := uint64(p.timestamp) * uint64(time.Millisecond)
+	if p.timestamp == 0 && !c.ScrapeTime.IsZero() {
+		ts = uint64(c.ScrapeTime.UnixNano())
+	}
+	var flags otlptranslator.DataPointFlags
+	if p.stale {
+		flags = otlptranslator.DataPointFlagNoRecordedValue
+	}
+	exemplars := prometheusExemplars(p.exemplars)
+
+	switch meta.Type.Kind() {
+	case otlptranslator.MetricKindHistogram:
+		dp := otlptranslator.HistogramDataPoint{Attributes: attrs, StartTimeUnixNano: p.created, TimeUnixNano: ts, Sum: p.sum, Exemplars: exemplars, Flags: flags}
+		if !p.stale {
+			if err := reassembleBuckets(&dp, p); err != nil {
+				return nil, err
+			}
+		}
+		return func(m *Metric) { m.HistogramDataPoints = append(m.HistogramDataPoints, dp) }, nil
+	case otlptranslator.MetricKindSummary:
+		dp := otlptranslator.SummaryDataPoint{Attributes: attrs, StartTimeUnixNano: p.created, TimeUnixNano: ts, Sum: p.sum, Flags: flags}
+		if !p.stale {
+			count, err := toCount(p.count)
+			if err != nil {
+				return nil, err
+			}
+			dp.Count = count
+			dp.QuantileValues = slices.SortedFunc(slices.Values(p.quantiles), func(a, b otlptranslator.ValueAtQuantile) int {
+				return compareFloats(a.Quantile, b.Quantile)
+			})
+		}
+		return func(m *Metric) { m.SummaryDataPoints = append(m.SummaryDataPoints, dp) }, nil
+	default:
+		dp := otlptranslator.NumberDataPoint{Attributes: attrs, TimeUnixNano: ts, Value: p.value, Exemplars: exemplars, Flags: flags}
+		if meta.Type == otlptranslator.MetricTypeMonotonicCounter {
+			dp.StartTimeUnixNano = p.created
+		}
+		return func(m *Metric) { m.NumberDataPoints = append(m.NumberDataPoints, dp) }, nil
+	}
+}
+
+// reassembleBuckets sets the explicit bounds and the bucket counts of a
+// histogram data point from its cumulative `_bucket` samples.
+func reassembleBuckets(dp *otlptranslator.HistogramDataPoint, p *promPoint) error {
+	buckets := slices.SortedFunc(slices.Values(p.buckets), func(a, b promBucket) int {
+		return compareFloats(a.upperBound, b.upperBound)
+	})
+	if len(buckets) == 0 || !math.IsInf(buckets[len(buckets)-1].upperBound, 1) {
+		return errors.New("no +Inf bucket")
+	}
+	var previous uint64
+	for _, bucket := range buckets {
+		cumulative, err := toCount(bucket.count)
+		if err != nil {
+			return err
+		}
+		if cumulative < previous {
+			return fmt.Errorf("bucket counts are not cumulative at %s=%g", otlptranslator.BucketLabel, bucket.upperBound)
+		}
+		if !math.IsInf(bucket.upperBound, 1) {
+			dp.ExplicitBounds = append(dp.ExplicitBounds, bucket.upperBound)
+		}
+		dp.BucketCounts = append(dp.BucketCounts, cumulative-previous)
+		previous = cumulative
+	}
+	dp.Count = previous
+	if p.hasCount {
+		count, err := toCount(p.count)
+		if err != nil {
+			return err
+		}
+		dp.Count = count
+	}
+	return nil
+}
+
+// toCount converts a sample value holding a count.
+func toCount(v float64) (uint64, error) {
+	if !(v >= 0) || v > math.MaxUint64 {
+		return 0, fmt.Errorf("invalid count %g", v)
+	}
+	return uint64(v), nil
+}
+
+func compareFloats(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// pointAttributes returns the attributes of a data point, from the labels of
+// its series minus the ones identifying its resource and scope.
+func pointAttributes(ls otlptranslator.Labels) []otlptranslator.Attribute {
+	var attrs []otlptranslator.Attribute
+	for _, l := range ls {
+		switch l.Name {
+		case jobLabel, instanceLabel, otlptranslator.ScopeNameLabelKey, otlptranslator.ScopeVersionLabelKey:
+		default:
+			attrs = append(attrs, otlptranslator.Attribute{Key: l.Name, Value: l.Value})
+		}
+	}
+	return attrs
+}
+
+// prometheusExemplars converts Prometheus exemplars, decoding the trace_id and
+// span_id labels. IDs that are not valid hex of the right length are kept as
+// filtered attributes.
+func prometheusExemplars(exemplars []otlptranslator.SampleExemplar) []otlptranslator.Exemplar {
+	if len(exemplars) == 0 {
+		return nil
+	}
+	res := make([]otlptranslator.Exemplar, 0, len(exemplars))
+	for _, e := range exemplars {
+		ex := otlptranslator.Exemplar{Value: e.Value, TimeUnixNano: uint64(e.Timestamp) * uint64(time.Millisecond)}
+		for _, l := range e.Labels {
+			switch {
+			case l.Name == otlptranslator.ExemplarTraceIDKey && decodeID(ex.TraceID[:], l.Value):
+			case l.Name == otlptranslator.ExemplarSpanIDKey && decodeID(ex.SpanID[:], l.Value):
+			default:
+				ex.FilteredAttributes = append(ex.FilteredAttributes, otlptranslator.Attribute{Key: l.Name, Value: l.Value})
+			}
+		}
+		res = append(res, ex)
+	}
+	return res
+}
+
+// decodeID decodes a hex-encoded ID into dst, and reports whether it
+// succeeded.
+func decodeID(dst []byte, s string) bool {
+	if hex.DecodedLen(len(s)) != len(dst) {
+		return false
+	}
+	_, err := hex.Decode(dst, []byte(s))
+	return err == nil
+}
@@ -0,0 +1,322 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlp
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/otlptranslator"
+	"github.com/prometheus/otlptranslator/exposition"
+)
+
+func TestPrometheusConverter(t *testing.T) {
+	target := otlptranslator.NewLabels(
+		otlptranslator.Label{Name: "job", Value: "shop/api"},
+		otlptranslator.Label{Name: "instance", Value: "pod-1"},
+	)
+	with := func(ls ...string) otlptranslator.Labels {
+		res := target
+		for i := 0; i < len(ls); i += 2 {
+			res = res.Merge(otlptranslator.Labels{{Name: ls[i], Value: ls[i+1]}})
+		}
+		return res
+	}
+	families := []exposition.Family{
+		{
+			Name: "http_server_requests",
+			Type: otlptranslator.PrometheusTypeCounter,
+			Help: "Number of requests.",
+			Samples: []otlptranslator.Sample{
+				{Name: "http_server_requests_total", Labels: with("method", "GET"), Value: 42, Timestamp: 2000, Exemplars: []otlptranslator.SampleExemplar{{
+					Labels:    otlptranslator.Labels{{Name: "span_id", Value: "0102030405060708"}, {Name: "trace_id", Value: "00000000000000000000000000000001"}, {Name: "user", Value: "alice"}},
+					Value:     0.5,
+					Timestamp: 1500,
+				}}},
+				{Name: "http_server_requests_created", Labels: with("method", "GET"), Value: 1, Timestamp: 2000},
+				{Name: "http_server_requests_total", Labels: with("method", "POST"), Value: math.Float64frombits(otlptranslator.StaleNaN), Timestamp: 3000},
+			},
+		},
+		{
+			Name: "target",
+			Type: "info",
+			Samples: []otlptranslator.Sample{
+				{Name: "target_info", Labels: with("k8s_namespace_name", "prod"), Value: 1},
+			},
+		},
+		{
+			Name: "http_server_duration_seconds",
+			Type: otlptranslator.PrometheusTypeHistogram,
+			Unit: "seconds",
+			Samples: []otlptranslator.Sample{
+				{Name: "http_server_duration_seconds_bucket", Labels: with("le", "0.5"), Value: 1, Timestamp: 2000},
+				{Name: "http_server_duration_seconds_bucket", Labels: with("le", "+Inf"), Value: 3, Timestamp: 2000},
+				{Name: "http_server_duration_seconds_bucket", Labels: with("le", "1"), Value: 3, Timestamp: 2000},
+				{Name: "http_server_duration_seconds_sum", Labels: target, Value: 1.5, Timestamp: 2000},
+				{Name: "http_server_duration_seconds_count", Labels: target, Value: 3, Timestamp: 2000},
+				{Name: "http_server_duration_seconds_created", Labels: target, Value: 1.5, Timestamp: 2000},
+			},
+		},
+		{
+			Name: "queue_depth",
+			Type: otlptranslator.PrometheusTypeGaugeHistogram,
+			Samples: []otlptranslator.Sample{
+				{Name: "queue_depth_bucket", Labels: with("le", "10"), Value: 2, Timestamp: 2000},
+				{Name: "queue_depth_bucket", Labels: with("le", "+Inf"), Value: 4, Timestamp: 2000},
+				{Name: "queue_depth_gsum", Labels: target, Value: 25, Timestamp: 2000},
+				{Name: "queue_depth_gcount", Labels: target, Value: 4, Timestamp: 2000},
+			},
+		},
+		{
+			Name: "gc_pause_seconds",
+			Type: otlptranslator.PrometheusTypeSummary,
+			Samples: []otlptranslator.Sample{
+				{Name: "gc_pause_seconds", Labels: with("otel_scope_name", "runtime", "otel_scope_version", "1.0", "quantile", "0.99"), Value: 0.5, Timestamp: 2000},
+				{Name: "gc_pause_seconds", Labels: with("otel_scope_name", "runtime", "otel_scope_version", "1.0", "quantile", "0.5"), Value: 0.1, Timestamp: 2000},
+				{Name: "gc_pause_seconds_sum", Labels: with("otel_scope_name", "runtime", "otel_scope_version", "1.0"), Value: 2, Timestamp: 2000},
+				{Name: "gc_pause_seconds_count", Labels: with("otel_scope_name", "runtime", "otel_scope_version", "1.0"), Value: 10, Timestamp: 2000},
+			},
+		},
+		{
+			Name: "cpu_utilization_ratio",
+			Type: otlptranslator.PrometheusTypeGauge,
+			Samples: []otlptranslator.Sample{
+				{Name: "cpu_utilization_ratio", Labels: otlptranslator.Labels{{Name: "cpu", Value: "0"}}, Value: 0.25},
+			},
+		},
+	}
+
+	c := PrometheusConverter{ScrapeTime: time.Unix(5, 0)}
+	got, err := c.Convert(families)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &MetricsRequest{ResourceMetrics: []ResourceMetrics{
+		{
+			Resource: Resource{Attributes: []otlptranslator.Attribute{
+				{Key: "service.namespace", Value: "shop"},
+				{Key: "service.name", Value: "api"},
+				{Key: "service.instance.id", Value: "pod-1"},
+				{Key: "k8s_namespace_name", Value: "prod"},
+			}},
+			ScopeMetrics: []ScopeMetrics{
+				{Metrics: []Metric{
+					{
+						Metric: otlptranslator.Metric{Name: "http_server_requests", Type: otlptranslator.MetricTypeMonotonicCounter, Temporality: otlptranslator.TemporalityCumulative, Description: "Number of requests."},
+						NumberDataPoints: []otlptranslator.NumberDataPoint{
+							{
+								Attributes:        []otlptranslator.Attribute{{Key: "method", Value: "GET"}},
+								StartTimeUnixNano: 1_000_000_000,
+								TimeUnixNano:      2_000_000_000,
+								Value:             42,
+								Exemplars: []otlptranslator.Exemplar{{
+									FilteredAttributes: []otlptranslator.Attribute{{Key: "user", Value: "alice"}},
+									TimeUnixNano:       1_500_000_000,
+									Value:              0.5,
+									TraceID:            [16]byte{15: 1},
+									SpanID:             [8]byte{1, 2, 3, 4, 5, 6, 7, 8},
+								}},
+							},
+							{
+								Attributes:   []otlptranslator.Attribute{{Key: "method", Value: "POST"}},
+								TimeUnixNano: 3_000_000_000,
+								Flags:        otlptranslator.DataPointFlagNoRecordedValue,
+							},
+						},
+					},
+					{
+						Metric: otlptranslator.Metric{Name: "http_server_duration", Unit: "s", Type: otlptranslator.MetricTypeHistogram, Temporality: otlptranslator.TemporalityCumulative},
+						HistogramDataPoints: []otlptranslator.HistogramDataPoint{{
+							StartTimeUnixNano: 1_500_000_000,
+							TimeUnixNano:      2_000_000_000,
+							Count:             3,
+							Sum:               1.5,
+							BucketCounts:      []uint64{1, 2, 0},
+							ExplicitBounds:    []float64{0.5, 1},
+						}},
+					},
+					{
+						Metric: otlptranslator.Metric{Name: "queue_depth", Type: otlptranslator.MetricTypeHistogram, Temporality: otlptranslator.TemporalityDelta},
+						HistogramDataPoints: []otlptranslator.HistogramDataPoint{{
+							TimeUnixNano:   2_000_000_000,
+							Count:          4,
+							Sum:            25,
+							BucketCounts:   []uint64{2, 2},
+							ExplicitBounds: []float64{10},
+						}},
+					},
+				}},
+				{
+					Scope: Scope{Name: "runtime", Version: "1.0"},
+					Metrics: []Metric{{
+						Metric: otlptranslator.Metric{Name: "gc_pause", Unit: "s", Type: otlptranslator.MetricTypeSummary},
+						SummaryDataPoints: []otlptranslator.SummaryDataPoint{{
+							TimeUnixNano:   2_000_000_000,
+							Count:          10,
+							Sum:            2,
+							QuantileValues: []otlptranslator.ValueAtQuantile{{Quantile: 0.5, Value: 0.1}, {Quantile: 0.99, Value: 0.5}},
+						}},
+					}},
+				},
+			},
+		},
+		{
+			ScopeMetrics: []ScopeMetrics{{Metrics: []Metric{{
+				Metric: otlptranslator.Metric{Name: "cpu_utilization", Unit: "1", Type: otlptranslator.MetricTypeGauge},
+				NumberDataPoints: []otlptranslator.NumberDataPoint{{
+					Attributes:   []otlptranslator.Attribute{{Key: "cpu", Value: "0"}},
+					TimeUnixNano: 5_000_000_000,
+					Value:        0.25,
+				}},
+			}}}},
+		},
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Convert():\ngot  %+v\nwant %+v", got, want)
+	}
+}
+
+func TestPrometheusConverter_Parsed(t *testing.T) {
+	in := `# TYPE http_server_duration_seconds histogram
+# UNIT http_server_duration_seconds seconds
+http_server_duration_seconds_bucket{job="api",le="1"} 1 2
+http_server_duration_seconds_bucket{job="api",le="+Inf"} 3 2
+http_server_duration_seconds_sum{job="api"} 2.5 2
+http_server_duration_seconds_count{job="api"} 3 2
+http_server_duration_seconds_created{job="api"} 1 2
+# EOF
+`
+	p := exposition.NewOpenMetricsParser(strings.NewReader(in))
+	f, err := p.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := PrometheusConverter{}.Convert([]exposition.Family{f})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &MetricsRequest{ResourceMetrics: []ResourceMetrics{{
+		Resource: Resource{Attributes: []otlptranslator.Attribute{{Key: "service.name", Value: "api"}}},
+		ScopeMetrics: []ScopeMetrics{{Metrics: []Metric{{
+			Metric: otlptranslator.Metric{Name: "http_server_duration", Unit: "s", Type: otlptranslator.MetricTypeHistogram, Temporality: otlptranslator.TemporalityCumulative},
+			HistogramDataPoints: []otlptranslator.HistogramDataPoint{{
+				StartTimeUnixNano: 1_000_000_000,
+				TimeUnixNano:      2_000_000_000,
+				Count:             3,
+				Sum:               2.5,
+				BucketCounts:      []uint64{1, 2},
+				ExplicitBounds:    []float64{1},
+			}},
+		}}}},
+	}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Convert():\ngot  %+v\nwant %+v", got, want)
+	}
+}
+
+// TestPrometheusConverter_RoundTrip checks that the metadata of translated
+// families converts back to metrics translating to the same families.
+func TestPrometheusConverter_RoundTrip(t *testing.T) {
+	metrics := []otlptranslator.Metric{
+		{Name: "http.server.requests", Type: otlptranslator.MetricTypeMonotonicCounter, Temporality: otlptranslator.TemporalityCumulative},
+		{Name: "http.server.duration", Unit: "s", Type: otlptranslator.MetricTypeHistogram, Temporality: otlptranslator.TemporalityCumulative},
+		{Name: "network.io", Unit: "By/s", Type: otlptranslator.MetricTypeGauge},
+		{Name: "gc.pause", Unit: "ms", Type: otlptranslator.MetricTypeSummary},
+	}
+	namer := otlptranslator.NewMetricNamer("", otlptranslator.UnderscoreEscapingWithSuffixes)
+	metadata := otlptranslator.MetadataBuilder{MetricNamer: namer}
+	for _, m := range metrics {
+		md, err := metadata.Build(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		meta, _, err := prometheusMetric(exposition.Family{Name: md.FamilyName, Type: md.Type})
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Names stay escaped, but translate back to the same family.
+		if meta.Unit != m.Unit || meta.Type != m.Type || meta.Temporality != m.Temporality {
+			t.Errorf("%s: got %+v", md.FamilyName, meta)
+		}
+		if md2, _ := metadata.Build(meta); md2.FamilyName != md.FamilyName {
+			t.Errorf("%s translates back to %s", md.FamilyName, md2.FamilyName)
+		}
+	}
+}
+
+func TestPrometheusConverter_Errors(t *testing.T) {
+	gauge := exposition.Family{Name: "up", Type: otlptranslator.PrometheusTypeGauge, Samples: []otlptranslator.Sample{{Name: "up", Value: 1}}}
+	for _, tc := range []struct {
+		name    string
+		family  exposition.Family
+		wantErr string
+	}{
+		{
+			name: "foreign sample",
+			family: exposition.Family{Name: "requests", Type: otlptranslator.PrometheusTypeCounter, Samples: []otlptranslator.Sample{
+				{Name: "responses_total", Value: 1},
+			}},
+			wantErr: `family "requests": sample "responses_total" does not belong to the family`,
+		},
+		{
+			name:    "unsupported type",
+			family:  exposition.Family{Name: "requests", Type: "nativehistogram"},
+			wantErr: `family "requests": unsupported type "nativehistogram"`,
+		},
+		{
+			name: "missing +Inf bucket",
+			family: exposition.Family{Name: "latency", Type: otlptranslator.PrometheusTypeHistogram, Samples: []otlptranslator.Sample{
+				{Name: "latency_bucket", Labels: otlptranslator.Labels{{Name: "le", Value: "1"}}, Value: 1},
+			}},
+			wantErr: `family "latency": series {}: no +Inf bucket`,
+		},
+		{
+			name: "decreasing buckets",
+			family: exposition.Family{Name: "latency", Type: otlptranslator.PrometheusTypeHistogram, Samples: []otlptranslator.Sample{
+				{Name: "latency_bucket", Labels: otlptranslator.Labels{{Name: "le", Value: "1"}}, Value: 2},
+				{Name: "latency_bucket", Labels: otlptranslator.Labels{{Name: "le", Value: "+Inf"}}, Value: 1},
+			}},
+			wantErr: `family "latency": series {}: bucket counts are not cumulative at le=+Inf`,
+		},
+		{
+			name: "invalid le",
+			family: exposition.Family{Name: "latency", Type: otlptranslator.PrometheusTypeHistogram, Samples: []otlptranslator.Sample{
+				{Name: "latency_bucket", Labels: otlptranslator.Labels{{Name: "le", Value: "x"}}, Value: 1},
+			}},
+			wantErr: `family "latency": sample latency_bucket{le="x"}: invalid le label "x"`,
+		},
+		{
+			name: "negative count",
+			family: exposition.Family{Name: "rpc", Type: otlptranslator.PrometheusTypeSummary, Samples: []otlptranslator.Sample{
+				{Name: "rpc_count", Value: -1},
+			}},
+			wantErr: `family "rpc": series {}: invalid count -1`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := PrometheusConverter{}.Convert([]exposition.Family{tc.family, gauge})
+			if err == nil || err.Error() != tc.wantErr {
+				t.Errorf("Convert() error = %v, want %s", err, tc.wantErr)
+			}
+			// Other families are still converted.
+			if len(got.ResourceMetrics) != 1 || got.ResourceMetrics[0].ScopeMetrics[0].Metrics[0].Name != "up" {
+				t.Errorf("Convert() = %+v", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,705 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlp
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/prometheus/otlptranslator"
+	"github.com/prometheus/otlptranslator/internal/protobuf"
+)
+
+// ProtobufContentType is the content type of protobuf-encoded OTLP/HTTP
+// requests.
+const ProtobufContentType = "application/x-protobuf"
+
+// DecodeMetricsRequest decodes a protobuf-encoded
+// opentelemetry.proto.collector.metrics.v1.ExportMetricsServiceRequest, as
+// sent by OTLP/HTTP exporters.
+//
+// Unknown fields are skipped, so that requests produced by newer versions of
+// the protocol can still be decoded. Integer values of number data points and
+// exemplars are converted to float64, and attribute values to strings, as
+// described by the OpenTelemetry to Prometheus compatibility specification.
+// Data point min and max, and metric metadata, are dropped as they have no
+// Prometheus equivalent.
+//
+// Example usage:
+//
+//	req, err := otlp.DecodeMetricsRequest(body)
+//	if err != nil {
+//		// handle err
+//	}
+//	for _, rm := range req.ResourceMetrics {
+//		for _, sm := range rm.ScopeMetrics {
+//			for _, m := range sm.Metrics {
+//				name, err := namer.Build(m.Metric)
+//				// ...
+//			}
+//		}
+//	}
+func DecodeMetricsRequest(b []byte) (*MetricsRequest, error) {
+	req := &MetricsRequest{}
+	err := forEachField(b, "ExportMetricsServiceRequest", func(f protobuf.Field) error {
+		if f.Num != 1 {
+			return nil
+		}
+		if err := checkType(f, protobuf.BytesType, "ExportMetricsServiceRequest"); err != nil {
+			return err
+		}
+		rm, err := decodeResourceMetrics(f.Bytes)
+		req.ResourceMetrics = append(req.ResourceMetrics, rm)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// forEachField calls fn for every field of the message b, and wraps errors of
+// the wire format with the name of the message.
+func forEachField(b []byte, msg string, fn func(protobuf.Field) error) error {
+	var fnErr error
+	err := protobuf.ForEachField(b, func(f protobuf.Field) error {
+		fnErr = fn(f)
+		return fnErr
+	})
+	if err != nil && fnErr == nil {
+		return fmt.Errorf("otlp: decoding %s: %w", msg, err)
+	}
+	return err
+}
+
+// checkType returns an error if a known field does not have the wire type
+// of its protobuf type.
+func checkType(f protobuf.Field, typ protobuf.Type, msg string) error {
+	if f.Type != typ {
+		return fmt.Errorf("otlp: decoding %s: field %d has wire type %d, want %d", msg, f.Num, f.Type, typ)
+	}
+	return nil
+}
+
+func decodeResourceMetrics(b []byte) (ResourceMetrics, error) {
+	const msg = "ResourceMetrics"
+	var rm ResourceMetrics
+	err := forEachField(b, msg, func(f protobuf.Field) error {
+		switch f.Num {
+		case 1:
+			if err := checkType(f, protobuf.BytesType, msg); err != nil {
+				return err
+			}
+			return forEachField(f.Bytes, "Resource", func(f protobuf.Field) error {
+				if f.Num != 1 {
+					return nil
+				}
+				return appendAttribute(&rm.Resource.Attributes, f, "Resource")
+			})
+		case 2:
+			if err := checkType(f, protobuf.BytesType, msg); err != nil {
+				return err
+			}
+			sm, err := decodeScopeMetrics(f.Bytes)
+			rm.ScopeMetrics = append(rm.ScopeMetrics, sm)
+			return err
+		case 3:
+			if err := checkType(f, protobuf.BytesType, msg); err != nil {
+				return err
+			}
+			rm.SchemaURL = string(f.Bytes)
+		}
+		return nil
+	})
+	return rm, err
+}
+
+func decodeScopeMetrics(b []byte) (ScopeMetrics, error) {
+	const msg = "ScopeMetrics"
+	var sm ScopeMetrics
+	err := forEachField(b, msg, func(f protobuf.Field) error {
+		switch f.Num {
+		case 1:
+			if err := checkType(f, protobuf.BytesType, msg); err != nil {
+				return err
+			}
+			return decodeScope(f.Bytes, &sm.Scope)
+		case 2:
+			if err := checkType(f, protobuf.BytesType, msg); err != nil {
+				return err
+			}
+			m, err := decodeMetric(f.Bytes)
+			sm.Metrics = append(sm.Metrics, m)
+			return err
+		case 3:
+			if err := checkType(f, protobuf.BytesType, msg); err != nil {
+				return err
+			}
+			sm.SchemaURL = string(f.Bytes)
+		}
+		return nil
+	})
+	return sm, err
+}
+
+func decodeScope(b []byte, s *Scope) error {
+	const msg = "InstrumentationScope"
+	return forEachField(b, msg, func(f protobuf.Field) error {
+		switch f.Num {
+		case 1:
+			if err := checkType(f, protobuf.BytesType, msg); err != nil {
+				return err
+			}
+			s.Name = string(f.Bytes)
+		case 2:
+			if err := checkType(f, protobuf.BytesType, msg); err != nil {
+				return err
+			}
+			s.Version = string(f.Bytes)
+		case 3:
+			return appendAttribute(&s.Attributes, f, msg)
+		}
+		return nil
+	})
+}
+
+func decodeMetric(b []byte) (Metric, error) {
+	const msg = "Metric"
+	var m Metric
+	err := forEachField(b, msg, func(f protobuf.Field) error {
+		switch f.Num {
+		case 1, 2, 3, 5, 7, 9, 10, 11:
+			if err := checkType(f, protobuf.BytesType, msg); err != nil {
+				return err
+			}
+		default:
+			return nil
+		}
+		switch f.Num {
+		case 1:
+			m.Name = string(f.Bytes)
+		case 2:
+			m.Description = string(f.Bytes)
+		case 3:
+			m.Unit = string(f.Bytes)
+		case 5:
+			m.Type = otlptranslator.MetricTypeGauge
+			return decodeDataPoints(f.Bytes, "Gauge", &m.Metric, func(b []byte) error {
+				dp, err := decodeNumberDataPoint(b)
+				m.NumberDataPoints = append(m.NumberDataPoints, dp)
+				return err
+			})
+		case 7:
+			// Non-monotonic unless is_monotonic is set.
+			m.Type = otlptranslator.MetricTypeNonMonotonicCounter
+			return decodeDataPoints(f.Bytes, "Sum", &m.Metric, func(b []byte) error {
+				dp, err := decodeNumberDataPoint(b)
+				m.NumberDataPoints = append(m.NumberDataPoints, dp)
+				return err
+			})
+		case 9:
+			m.Type = otlptranslator.MetricTypeHistogram
+			return decodeDataPoints(f.Bytes, "Histogram", &m.Metric, func(b []byte) error {
+				dp, err := decodeHistogramDataPoint(b)
+				m.HistogramDataPoints = append(m.HistogramDataPoints, dp)
+				return err
+			})
+		case 10:
+			m.Type = otlptranslator.MetricTypeExponentialHistogram
+			return decodeDataPoints(f.Bytes, "ExponentialHistogram", &m.Metric, func(b []byte) error {
+				dp, err := decodeExponentialHistogramDataPoint(b)
+				m.ExponentialHistogramDataPoints = append(m.ExponentialHistogramDataPoints, dp)
+				return err
+			})
+		case 11:
+			m.Type = otlptranslator.MetricTypeSummary
+			return decodeDataPoints(f.Bytes, "Summary", &m.Metric, func(b []byte) error {
+				dp, err := decodeSummaryDataPoint(b)
+				m.SummaryDataPoints = append(m.SummaryDataPoints, dp)
+				return err
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return m, err
+	}
+	if n := dataPointKinds(m); n > 1 {
+		return m, fmt.Errorf("otlp: metric %q holds %d kinds of data points", m.Name, n)
+	}
+	return m, nil
+}
+
+// dataPointKinds returns the number of data point slices populated for the
+// metric, which must not be more than one, as the data field of an OTLP
+// metric is a oneof.
+func dataPointKinds(m Metric) int {
+	var n int
+	for _, l := range []int{len(m.NumberDataPoints), len(m.HistogramDataPoints), len(m.ExponentialHistogramDataPoints), len(m.SummaryDataPoints)} {
+		if l > 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// decodeDataPoints decodes a Gauge, Sum, Histogram, ExponentialHistogram or
+// Summary message, setting the temporality and monotonicity of the metric.
+// All of them hold their data points in field 1, and their aggregation
+// temporality, if any, in field 2.
+func decodeDataPoints(b []byte, msg string, m *otlptranslator.Metric, decode func([]byte) error) error {
+	return forEachField(b, msg, func(f protobuf.Field) error {
+		switch {
+		case f.Num == 1:
+			if err := checkType(f, protobuf.BytesType, msg); err != nil {
+				return err
+			}
+			return decode(f.Bytes)
+		case f.Num == 2 && msg != "Gauge" && msg != "Summary":
+			if err := checkType(f, protobuf.VarintType, msg); err != nil {
+				return err
+			}
+			m.Temporality = temporality(f.Varint)
+		case f.Num == 3 && msg == "Sum":
+			if err := checkType(f, protobuf.VarintType, msg); err != nil {
+				return err
+			}
+			if f.Varint != 0 {
+				m.Type = otlptranslator.MetricTypeMonotonicCounter
+			} else {
+				m.Type = otlptranslator.MetricTypeNonMonotonicCounter
+			}
+		}
+		return nil
+	})
+}
+
+// temporality maps an OTLP AggregationTemporality value. Unknown values are
+// unspecified.
+func temporality(v uint64) otlptranslator.Temporality {
+	switch v {
+	case 1:
+		return otlptranslator.TemporalityDelta
+	case 2:
+		return otlptranslator.TemporalityCumulative
+	default:
+		return otlptranslator.TemporalityUnspecified
+	}
+}
+
+// dataPointCommon decodes the fields shared by all data points: start and
+// end times, and flags. It reports whether the field was one of them.
+func dataPointCommon(f protobuf.Field, flagsNum int, msg string, start, end *uint64, flags *otlptranslator.DataPointFlags) (bool, error) {
+	switch f.Num {
+	case 2, 3:
+		if err := checkType(f, protobuf.Fixed64Type, msg); err != nil {
+			return true, err
+		}
+		if f.Num == 2 {
+			*start = f.Fixed
+		} else {
+			*end = f.Fixed
+		}
+		return true, nil
+	case flagsNum:
+		if err := checkType(f, protobuf.VarintType, msg); err != nil {
+			return true, err
+		}
+		*flags = otlptranslator.DataPointFlags(f.Varint)
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+func decodeNumberDataPoint(b []byte) (otlptranslator.NumberDataPoint, error) {
+	const msg = "NumberDataPoint"
+	var dp otlptranslator.NumberDataPoint
+	err := forEachField(b, msg, func(f protobuf.Field) error {
+		if ok, err := dataPointCommon(f, 8, msg, &dp.StartTimeUnixNano, &dp.TimeUnixNano, &dp.Flags); ok {
+			return err
+		}
+		switch f.Num {
+		case 4, 6:
+			if err := checkType(f, protobuf.Fixed64Type, msg); err != nil {
+				return err
+			}
+			dp.Value = numberValue(f)
+		case 5:
+			if err := checkType(f, protobuf.BytesType, msg); err != nil {
+				return err
+			}
+			e, err := decodeExemplar(f.Bytes)
+			dp.Exemplars = append(dp.Exemplars, e)
+			return err
+		case 7:
+			return appendAttribute(&dp.Attributes, f, msg)
+		}
+		return nil
+	})
+	return dp, err
+}
+
+// numberValue returns the value of an as_double or as_int field, which are
+// respectively fields 4 and 6 of NumberDataPoint and 3 and 6 of Exemplar.
+func numberValue(f protobuf.Field) float64 {
+	if f.Num == 6 {
+		return float64(int64(f.Fixed))
+	}
+	return f.Double()
+}
+
+func decodeHistogramDataPoint(b []byte) (otlptranslator.HistogramDataPoint, error) {
+	const msg = "HistogramDataPoint"
+	var dp otlptranslator.HistogramDataPoint
+	err := forEachField(b, msg, func(f protobuf.Field) error {
+		if ok, err := dataPointCommon(f, 10, msg, &dp.StartTimeUnixNano, &dp.TimeUnixNano, &dp.Flags); ok {
+			return err
+		}
+		switch f.Num {
+		case 4:
+			if err := checkType(f, protobuf.Fixed64Type, msg); err != nil {
+				return err
+			}
+			dp.Count = f.Fixed
+		case 5:
+			if err := checkType(f, protobuf.Fixed64Type, msg); err != nil {
+				return err
+			}
+			dp.Sum = f.Double()
+		case 6:
+			var err error
+			dp.BucketCounts, err = protobuf.AppendFixed64s(dp.BucketCounts, f)
+			return wrapError(err, msg)
+		case 7:
+			bounds, err := protobuf.AppendFixed64s(nil, f)
+			for _, v := range bounds {
+				dp.ExplicitBounds = append(dp.ExplicitBounds, math.Float64frombits(v))
+			}
+			return wrapError(err, msg)
+		case 8:
+			if err := checkType(f, protobuf.BytesType, msg); err != nil {
+				return err
+			}
+			e, err := decodeExemplar(f.Bytes)
+			dp.Exemplars = append(dp.Exemplars, e)
+			return err
+		case 9:
+			return appendAttribute(&dp.Attributes, f, msg)
+		}
+		return nil
+	})
+	if err != nil {
+		return dp, err
+	}
+	return dp, checkBucketCounts(dp)
+}
+
+// checkBucketCounts returns an error if a histogram data point has buckets,
+// but not one more than its explicit bounds.
+func checkBucketCounts(dp otlptranslator.HistogramDataPoint) error {
+	if len(dp.BucketCounts) > 0 && len(dp.BucketCounts) != len(dp.ExplicitBounds)+1 {
+		return fmt.Errorf("otlp: decoding HistogramDataPoint: %d bucket counts for %d explicit bounds", len(dp.BucketCounts), len(dp.ExplicitBounds))
+	}
+	return nil
+}
+
+func decodeExponentialHistogramDataPoint(b []byte) (otlptranslator.ExponentialHistogramDataPoint, error) {
+	const msg = "ExponentialHistogramDataPoint"
+	var dp otlptranslator.ExponentialHistogramDataPoint
+	err := forEachField(b, msg, func(f protobuf.Field) error {
+		if ok, err := dataPointCommon(f, 10, msg, &dp.StartTimeUnixNano, &dp.TimeUnixNano, &dp.Flags); ok {
+			return err
+		}
+		switch f.Num {
+		case 1:
+			return appendAttribute(&dp.Attributes, f, msg)
+		case 4, 7:
+			if err := checkType(f, protobuf.Fixed64Type, msg); err != nil {
+				return err
+			}
+			if f.Num == 4 {
+				dp.Count = f.Fixed
+			} else {
+				dp.ZeroCount = f.Fixed
+			}
+		case 5, 14:
+			if err := checkType(f, protobuf.Fixed64Type, msg); err != nil {
+				return err
+			}
+			if f.Num == 5 {
+				dp.Sum = f.Double()
+			} else {
+				dp.ZeroThreshold = f.Double()
+			}
+		case 6:
+			if err := checkType(f, protobuf.VarintType, msg); err != nil {
+				return err
+			}
+			dp.Scale = int32(protobuf.DecodeZigZag(f.Varint))
+		case 8, 9:
+			if err := checkType(f, protobuf.BytesType, msg); err != nil {
+				return err
+			}
+			buckets := &dp.Positive
+			if f.Num == 9 {
+				buckets = &dp.Negative
+			}
+			return decodeBuckets(f.Bytes, buckets)
+		case 11:
+			if err := checkType(f, protobuf.BytesType, msg); err != nil {
+				return err
+			}
+			e, err := decodeExemplar(f.Bytes)
+			dp.Exemplars = append(dp.Exemplars, e)
+			return err
+		}
+		return nil
+	})
+	return dp, err
+}
+
+func decodeBuckets(b []byte, buckets *otlptranslator.ExponentialHistogramBuckets) error {
+	const msg = "ExponentialHistogramDataPoint.Buckets"
+	return forEachField(b, msg, func(f protobuf.Field) error {
+		switch f.Num {
+		case 1:
+			if err := checkType(f, protobuf.VarintType, msg); err != nil {
+				return err
+			}
+			buckets.Offset = int32(protobuf.DecodeZigZag(f.Varint))
+		case 2:
+			var err error
+			buckets.BucketCounts, err = protobuf.AppendVarints(buckets.BucketCounts, f)
+			return wrapError(err, msg)
+		}
+		return nil
+	})
+}
+
+func decodeSummaryDataPoint(b []byte) (otlptranslator.SummaryDataPoint, error) {
+	const msg = "SummaryDataPoint"
+	var dp otlptranslator.SummaryDataPoint
+	err := forEachField(b, msg, func(f protobuf.Field) error {
+		if ok, err := dataPointCommon(f, 8, msg, &dp.StartTimeUnixNano, &dp.TimeUnixNano, &dp.Flags); ok {
+			return err
+		}
+		switch f.Num {
+		case 4:
+			if err := checkType(f, protobuf.Fixed64Type, msg); err != nil {
+				return err
+			}
+			dp.Count = f.Fixed
+		case 5:
+			if err := checkType(f, protobuf.Fixed64Type, msg); err != nil {
+				return err
+			}
+			dp.Sum = f.Double()
+		case 6:
+			if err := checkType(f, protobuf.BytesType, msg); err != nil {
+				return err
+			}
+			var q otlptranslator.ValueAtQuantile
+			err := forEachField(f.Bytes, "ValueAtQuantile", func(f protobuf.Field) error {
+				if f.Num != 1 && f.Num != 2 {
+					return nil
+				}
+				if err := checkType(f, protobuf.Fixed64Type, "ValueAtQuantile"); err != nil {
+					return err
+				}
+				if f.Num == 1 {
+					q.Quantile = f.Double()
+				} else {
+					q.Value = f.Double()
+				}
+				return nil
+			})
+			dp.QuantileValues = append(dp.QuantileValues, q)
+			return err
+		case 7:
+			return appendAttribute(&dp.Attributes, f, msg)
+		}
+		return nil
+	})
+	return dp, err
+}
+
+func decodeExemplar(b []byte) (otlptranslator.Exemplar, error) {
+	const msg = "Exemplar"
+	var e otlptranslator.Exemplar
+	err := forEachField(b, msg, func(f protobuf.Field) error {
+		switch f.Num {
+		case 2:
+			if err := checkType(f, protobuf.Fixed64Type, msg); err != nil {
+				return err
+			}
+			e.TimeUnixNano = f.Fixed
+		case 3, 6:
+			if err := checkType(f, protobuf.Fixed64Type, msg); err != nil {
+				return err
+			}
+			e.Value = numberValue(f)
+		case 4:
+			if err := checkType(f, protobuf.BytesType, msg); err != nil {
+				return err
+			}
+			return copyID(e.SpanID[:], f.Bytes, "span_id")
+		case 5:
+			if err := checkType(f, protobuf.BytesType, msg); err != nil {
+				return err
+			}
+			return copyID(e.TraceID[:], f.Bytes, "trace_id")
+		case 7:
+			return appendAttribute(&e.FilteredAttributes, f, msg)
+		}
+		return nil
+	})
+	return e, err
+}
+
+// copyID copies a trace or span ID, which must either be empty or have the
+// exact length of dst.
+func copyID(dst, id []byte, field string) error {
+	if len(id) != 0 && len(id) != len(dst) {
+		return fmt.Errorf("otlp: decoding Exemplar: %s has %d bytes, want %d", field, len(id), len(dst))
+	}
+	copy(dst, id)
+	return nil
+}
+
+// appendAttribute decodes a KeyValue field and appends it to attrs.
+func appendAttribute(attrs *[]otlptranslator.Attribute, f protobuf.Field, msg string) error {
+	if err := checkType(f, protobuf.BytesType, msg); err != nil {
+		return err
+	}
+	key, value, err := decodeKeyValue(f.Bytes, 0)
+	if err != nil {
+		return err
+	}
+	*attrs = append(*attrs, otlptranslator.Attribute{Key: key, Value: attributeString(value)})
+	return nil
+}
+
+func decodeKeyValue(b []byte, depth int) (string, any, error) {
+	const msg = "KeyValue"
+	var (
+		key   string
+		value any
+	)
+	err := forEachField(b, msg, func(f protobuf.Field) error {
+		if f.Num != 1 && f.Num != 2 {
+			return nil
+		}
+		if err := checkType(f, protobuf.BytesType, msg); err != nil {
+			return err
+		}
+		if f.Num == 1 {
+			key = string(f.Bytes)
+			return nil
+		}
+		var err error
+		value, err = decodeAnyValue(f.Bytes, depth)
+		return err
+	})
+	return key, value, err
+}
+
+// maxAnyValueDepth is the maximum nesting depth of array and map attribute
+// values, protecting the decoder against stack exhaustion.
+const maxAnyValueDepth = 64
+
+// decodeAnyValue decodes an AnyValue as nil, a string, a bool, an int64, a
+// float64, a []byte, a []any or a map[string]any. Depth is the number of
+// arrays and maps the value is nested in.
+func decodeAnyValue(b []byte, depth int) (any, error) {
+	const msg = "AnyValue"
+	if depth > maxAnyValueDepth {
+		return nil, fmt.Errorf("otlp: decoding %s: more than %d nested arrays and maps", msg, maxAnyValueDepth)
+	}
+	var value any
+	err := forEachField(b, msg, func(f protobuf.Field) error {
+		switch f.Num {
+		case 1:
+			if err := checkType(f, protobuf.BytesType, msg); err != nil {
+				return err
+			}
+			value = string(f.Bytes)
+		case 2, 3:
+			if err := checkType(f, protobuf.VarintType, msg); err != nil {
+				return err
+			}
+			if f.Num == 2 {
+				value = f.Varint != 0
+			} else {
+				value = int64(f.Varint)
+			}
+		case 4:
+			if err := checkType(f, protobuf.Fixed64Type, msg); err != nil {
+				return err
+			}
+			value = f.Double()
+		case 5:
+			if err := checkType(f, protobuf.BytesType, msg); err != nil {
+				return err
+			}
+			values := []any{}
+			err := forEachField(f.Bytes, "ArrayValue", func(f protobuf.Field) error {
+				if f.Num != 1 {
+					return nil
+				}
+				if err := checkType(f, protobuf.BytesType, "ArrayValue"); err != nil {
+					return err
+				}
+				v, err := decodeAnyValue(f.Bytes, depth+1)
+				values = append(values, v)
+				return err
+			})
+			value = values
+			return err
+		case 6:
+			if err := checkType(f, protobuf.BytesType, msg); err != nil {
+				return err
+			}
+			values := map[string]any{}
+			err := forEachField(f.Bytes, "KeyValueList", func(f protobuf.Field) error {
+				if f.Num != 1 {
+					return nil
+				}
+				if err := checkType(f, protobuf.BytesType, "KeyValueList"); err != nil {
+					return err
+				}
+				k, v, err := decodeKeyValue(f.Bytes, depth+1)
+				values[k] = v
+				return err
+			})
+			value = values
+			return err
+		case 7:
+			if err := checkType(f, protobuf.BytesType, msg); err != nil {
+				return err
+			}
+			value = append([]byte{}, f.Bytes...)
+		}
+		return nil
+	})
+	return value, err
+}
+
+// wrapError wraps errors of the wire format with the name of the message.
+func wrapError(err error, msg string) error {
+	if err != nil {
+		return fmt.Errorf("otlp: decoding %s: %w", msg, err)
+	}
+	return nil
+}
@@ -0,0 +1,371 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlp
+
+import (
+	"bytes"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/otlptranslator"
+	"github.com/prometheus/otlptranslator/internal/protobuf"
+)
+
+// Helpers building protobuf fields, to write requests field by field.
+
+func msgField(num int, fields ...[]byte) []byte {
+	return protobuf.AppendMessageField(nil, num, bytes.Join(fields, nil))
+}
+
+func strField(num int, s string) []byte {
+	return protobuf.AppendMessageField(nil, num, []byte(s))
+}
+
+func varintField(num int, v uint64) []byte {
+	b := protobuf.AppendTag(nil, num, protobuf.VarintType)
+	return protobuf.AppendVarint(b, v)
+}
+
+func fixedField(num int, v uint64) []byte {
+	b := protobuf.AppendTag(nil, num, protobuf.Fixed64Type)
+	return protobuf.AppendFixed64(b, v)
+}
+
+func doubleField(num int, f float64) []byte {
+	return fixedField(num, math.Float64bits(f))
+}
+
+func packedFixedField(num int, vs ...uint64) []byte {
+	var b []byte
+	for _, v := range vs {
+		b = protobuf.AppendFixed64(b, v)
+	}
+	return protobuf.AppendMessageField(nil, num, b)
+}
+
+func keyValue(num int, key string, value ...[]byte) []byte {
+	return msgField(num, strField(1, key), msgField(2, value...))
+}
+
+// nestedArray returns an AnyValue holding depth nested arrays.
+func nestedArray(depth int) []byte {
+	var v []byte
+	for range depth {
+		v = msgField(5, msgField(1, v))
+	}
+	return v
+}
+
+// testRequest returns a request holding every kind of metric.
+func testRequest() []byte {
+	return msgField(1, // ResourceMetrics.
+		msgField(1, // Resource.
+			keyValue(1, "service.name", strField(1, "api")),
+			keyValue(1, "host.cpus", varintField(3, 8)),
+		),
+		msgField(2, // ScopeMetrics.
+			msgField(1, strField(1, "io.opentelemetry.http"), strField(2, "1.2.0"), keyValue(3, "debug", varintField(2, 1))),
+			msgField(2,
+				strField(1, "http.server.requests"),
+				strField(2, "Number of requests."),
+				strField(3, "{request}"),
+				msgField(7, // Sum.
+					msgField(1,
+						keyValue(7, "http.method", strField(1, "GET")),
+						fixedField(2, 1_000_000_000),
+						fixedField(3, 2_000_000_000),
+						fixedField(6, uint64(42)), // as_int.
+						msgField(5, // Exemplar.
+							keyValue(7, "user", strField(1, "alice")),
+							fixedField(2, 1_500_000_000),
+							doubleField(3, 0.5),
+							strField(4, "\x01\x02\x03\x04\x05\x06\x07\x08"),
+							strField(5, "\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x01"),
+						),
+					),
+					msgField(1, fixedField(3, 3_000_000_000), varintField(8, 1)),
+					varintField(2, 1), // Delta.
+					varintField(3, 1), // Monotonic.
+				),
+			),
+			msgField(2,
+				strField(1, "queue.size"),
+				msgField(5, msgField(1, doubleField(4, 1.5))), // Gauge.
+				msgField(12, keyValue(1, "ignored", strField(1, "metadata"))),
+			),
+			msgField(2,
+				strField(1, "http.server.duration"),
+				strField(3, "s"),
+				msgField(9, // Histogram.
+					msgField(1,
+						fixedField(4, 3),
+						doubleField(5, 1.5),
+						packedFixedField(6, 1, 2, 0),
+						packedFixedField(7, math.Float64bits(0.5), math.Float64bits(1)),
+						doubleField(11, 0.1), // Min, dropped.
+					),
+					varintField(2, 2), // Cumulative.
+				),
+			),
+			msgField(2,
+				strField(1, "rpc.duration"),
+				msgField(10, // ExponentialHistogram.
+					msgField(1,
+						keyValue(1, "rpc.method", strField(1, "Get")),
+						fixedField(4, 7),
+						doubleField(5, -5.25),
+						varintField(6, protobuf.EncodeZigZag(-1)),
+						fixedField(7, 1),
+						msgField(8, varintField(1, protobuf.EncodeZigZag(-2)), protobuf.AppendPackedVarintsField(nil, 2, []uint64{2, 1})),
+						msgField(9, varintField(2, 3)),
+						doubleField(14, 0.001),
+					),
+				),
+			),
+			msgField(2,
+				strField(1, "gc.pause"),
+				msgField(11, // Summary.
+					msgField(1,
+						fixedField(4, 10),
+						doubleField(5, 2),
+						msgField(6, doubleField(1, 0.5), doubleField(2, 0.1)),
+						msgField(6, doubleField(1, 0.99), doubleField(2, 0.4)),
+					),
+				),
+			),
+		),
+		strField(3, "https://opentelemetry.io/schemas/1.25.0"),
+		varintField(99, 1), // Unknown field.
+	)
+}
+
+func TestDecodeMetricsRequest(t *testing.T) {
+	got, err := DecodeMetricsRequest(testRequest())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &MetricsRequest{ResourceMetrics: []ResourceMetrics{{
+		Resource: Resource{Attributes: []otlptranslator.Attribute{
+			{Key: "service.name", Value: "api"},
+			{Key: "host.cpus", Value: "8"},
+		}},
+		ScopeMetrics: []ScopeMetrics{{
+			Scope: Scope{
+				Name:       "io.opentelemetry.http",
+				Version:    "1.2.0",
+				Attributes: []otlptranslator.Attribute{{Key: "debug", Value: "true"}},
+			},
+			Metrics: []Metric{
+				{
+					Metric: otlptranslator.Metric{
+						Name:        "http.server.requests",
+						Unit:        "{request}",
+						Type:        otlptranslator.MetricTypeMonotonicCounter,
+						Temporality: otlptranslator.TemporalityDelta,
+						Description: "Number of requests.",
+					},
+					NumberDataPoints: []otlptranslator.NumberDataPoint{
+						{
+							Attributes:        []otlptranslator.Attribute{{Key: "http.method", Value: "GET"}},
+							StartTimeUnixNano: 1_000_000_000,
+							TimeUnixNano:      2_000_000_000,
+							Value:             42,
+							Exemplars: []otlptranslator.Exemplar{{
+								FilteredAttributes: []otlptranslator.Attribute{{Key: "user", Value: "alice"}},
+								TimeUnixNano:       1_500_000_000,
+								Value:              0.5,
+								TraceID:            [16]byte{15: 1},
+								SpanID:             [8]byte{1, 2, 3, 4, 5, 6, 7, 8},
+							}},
+						},
+						{TimeUnixNano: 3_000_000_000, Flags: otlptranslator.DataPointFlagNoRecordedValue},
+					},
+				},
+				{
+					Metric:           otlptranslator.Metric{Name: "queue.size", Type: otlptranslator.MetricTypeGauge},
+					NumberDataPoints: []otlptranslator.NumberDataPoint{{Value: 1.5}},
+				},
+				{
+					Metric: otlptranslator.Metric{
+						Name:        "http.server.duration",
+						Unit:        "s",
+						Type:        otlptranslator.MetricTypeHistogram,
+						Temporality: otlptranslator.TemporalityCumulative,
+					},
+					HistogramDataPoints: []otlptranslator.HistogramDataPoint{{
+						Count:          3,
+						Sum:            1.5,
+						BucketCounts:   []uint64{1, 2, 0},
+						ExplicitBounds: []float64{0.5, 1},
+					}},
+				},
+				{
+					Metric: otlptranslator.Metric{Name: "rpc.duration", Type: otlptranslator.MetricTypeExponentialHistogram},
+					ExponentialHistogramDataPoints: []otlptranslator.ExponentialHistogramDataPoint{{
+						Attributes:    []otlptranslator.Attribute{{Key: "rpc.method", Value: "Get"}},
+						Count:         7,
+						Sum:           -5.25,
+						Scale:         -1,
+						ZeroCount:     1,
+						ZeroThreshold: 0.001,
+						Positive:      otlptranslator.ExponentialHistogramBuckets{Offset: -2, BucketCounts: []uint64{2, 1}},
+						Negative:      otlptranslator.ExponentialHistogramBuckets{BucketCounts: []uint64{3}},
+					}},
+				},
+				{
+					Metric: otlptranslator.Metric{Name: "gc.pause", Type: otlptranslator.MetricTypeSummary},
+					SummaryDataPoints: []otlptranslator.SummaryDataPoint{{
+						Count:          10,
+						Sum:            2,
+						QuantileValues: []otlptranslator.ValueAtQuantile{{Quantile: 0.5, Value: 0.1}, {Quantile: 0.99, Value: 0.4}},
+					}},
+				},
+			},
+		}},
+		SchemaURL: "https://opentelemetry.io/schemas/1.25.0",
+	}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DecodeMetricsRequest():\ngot  %+v\nwant %+v", got, want)
+	}
+}
+
+func TestDecodeMetricsRequest_Errors(t *testing.T) {
+	metric := func(fields ...[]byte) []byte {
+		return msgField(1, msgField(2, msgField(2, fields...)))
+	}
+	tests := []struct {
+		name  string
+		input []byte
+		want  string
+	}{
+		{
+			name:  "truncated",
+			input: testRequest()[:100],
+			want:  "protobuf: truncated input",
+		},
+		{
+			name:  "wrong wire type",
+			input: varintField(1, 1),
+			want:  "otlp: decoding ExportMetricsServiceRequest: field 1 has wire type 0, want 2",
+		},
+		{
+			name:  "wrong temporality wire type",
+			input: metric(msgField(7, strField(2, "delta"))),
+			want:  "otlp: decoding Sum: field 2 has wire type 2, want 0",
+		},
+		{
+			name:  "several kinds of data points",
+			input: metric(strField(1, "up"), msgField(5, msgField(1)), msgField(11, msgField(1))),
+			want:  `otlp: metric "up" holds 2 kinds of data points`,
+		},
+		{
+			name:  "bucket counts mismatch",
+			input: metric(msgField(9, msgField(1, packedFixedField(6, 1, 2, 3), packedFixedField(7, 0)))),
+			want:  "otlp: decoding HistogramDataPoint: 3 bucket counts for 1 explicit bounds",
+		},
+		{
+			name:  "misaligned explicit bounds",
+			input: metric(msgField(9, msgField(1, strField(7, "\x00")))),
+			want:  "otlp: decoding HistogramDataPoint: protobuf: truncated input",
+		},
+		{
+			name:  "deeply nested attribute",
+			input: metric(msgField(5, msgField(1, keyValue(7, "a", nestedArray(100))))),
+			want:  "otlp: decoding AnyValue: more than 64 nested arrays and maps",
+		},
+		{
+			name:  "invalid trace id",
+			input: metric(msgField(5, msgField(1, msgField(5, strField(5, "\x01"))))),
+			want:  "otlp: decoding Exemplar: trace_id has 1 bytes, want 16",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecodeMetricsRequest(tt.input)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("DecodeMetricsRequest() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestAttributeString(t *testing.T) {
+	tests := []struct {
+		value []byte
+		want  string
+	}{
+		{value: nil, want: ""},
+		{value: strField(1, "a"), want: "a"},
+		{value: varintField(2, 0), want: "false"},
+		{value: varintField(3, uint64(math.MaxUint64)), want: "-1"},
+		{value: doubleField(4, 1.0), want: "1"},
+		{value: doubleField(4, 1e21), want: "1e+21"},
+		{value: doubleField(4, 1e-7), want: "1e-7"},
+		{value: doubleField(4, -2.5e-10), want: "-2.5e-10"},
+		{value: doubleField(4, math.Inf(1)), want: "+Inf"},
+		{value: strField(7, "\x01\x02"), want: "AQI="},
+		{
+			value: msgField(5, msgField(1, strField(1, "a")), msgField(1, varintField(3, 2)), msgField(1), msgField(1, doubleField(4, math.NaN()))),
+			want:  `["a",2,null,"NaN"]`,
+		},
+		{
+			value: msgField(6, keyValue(1, "b", varintField(2, 1)), keyValue(1, "a", strField(7, "\x01"))),
+			want:  `{"a":"AQ==","b":true}`,
+		},
+	}
+	for _, tt := range tests {
+		v, err := decodeAnyValue(tt.value, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := attributeString(v); got != tt.want {
+			t.Errorf("attributeString(%x) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+// FuzzDecodeMetricsRequest checks that decoding arbitrary input never panics,
+// and that decoded metrics are consistent with their type.
+func FuzzDecodeMetricsRequest(f *testing.F) {
+	f.Add([]byte{})
+	f.Fuzz(func(t *testing.T, b []byte) {
+		req, err := DecodeMetricsRequest(b)
+		if err != nil {
+			return
+		}
+		for _, rm := range req.ResourceMetrics {
+			for _, sm := range rm.ScopeMetrics {
+				for _, m := range sm.Metrics {
+					if dataPointKinds(m) > 1 {
+						t.Errorf("metric %q holds several kinds of data points", m.Name)
+					}
+					kind := m.Type.Kind()
+					if len(m.NumberDataPoints) > 0 && kind != otlptranslator.MetricKindGauge && kind != otlptranslator.MetricKindSum ||
+						len(m.HistogramDataPoints) > 0 && kind != otlptranslator.MetricKindHistogram ||
+						len(m.ExponentialHistogramDataPoints) > 0 && kind != otlptranslator.MetricKindExponentialHistogram ||
+						len(m.SummaryDataPoints) > 0 && kind != otlptranslator.MetricKindSummary {
+						t.Errorf("metric %q of type %v holds data points of another kind", m.Name, m.Type)
+					}
+					for _, dp := range m.HistogramDataPoints {
+						if len(dp.BucketCounts) > 0 && len(dp.BucketCounts) != len(dp.ExplicitBounds)+1 {
+							t.Errorf("metric %q has %d bucket counts for %d bounds", m.Name, len(dp.BucketCounts), len(dp.ExplicitBounds))
+						}
+					}
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,22 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package promql rewrites PromQL queries written against OpenTelemetry
+// metric and attribute names into queries selecting the names translated by
+// otlptranslator.
+//
+// Main components:
+//   - Rewriter: Translates the metric selectors, label matchers and grouping labels of queries
+//   - Translator: Translates names for a Rewriter, to map names other than OpenTelemetry ones
+//   - Unresolved: A selector the Rewriter could not translate
+package promql
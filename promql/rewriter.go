@@ -0,0 +1,633 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package promql
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/otlptranslator"
+	"github.com/prometheus/otlptranslator/internal/legacy"
+)
+
+// Unresolved is a metric selector, or a grouping label, that a Rewriter could
+// not translate and left unchanged.
+type Unresolved struct {
+	// Selector is the text of the selector in the query.
+	Selector string
+	// Pos is the byte offset of the selector in the query.
+	Pos int
+	// Reason explains why the selector could not be translated.
+	Reason string
+}
+
+// String returns the position, text and reason of the unresolved selector.
+func (u Unresolved) String() string {
+	return fmt.Sprintf("%d: %s: %s", u.Pos, u.Selector, u.Reason)
+}
+
+// Rewriter rewrites PromQL queries written against OpenTelemetry names, such
+// as {"http.server.request.duration"}, into queries selecting the names built
+// by a MetricNamer and a LabelNamer.
+//
+// Metric names are taken from bare identifiers, from quoted names inside the
+// braces of UTF-8 selectors and from `__name__` equality matchers. Adding
+// suffixes requires the type and unit of a metric: names are first looked up
+// in the known metrics, either as is or, for histograms, summaries and
+// counters, followed by a `_bucket`, `_sum`, `_count` or `_created` suffix,
+// which is kept. Names of unknown metrics are only translated if the
+// MetricNamer does not add suffixes, and are reported as unresolved
+// otherwise. Label names in matchers and in by, without, on, ignoring,
+// group_left and group_right clauses are translated by the LabelNamer, except
+// for reserved ones starting with `__`.
+//
+// Rewritten selectors use the classic syntax when the translated name allows
+// it, and the quoted UTF-8 syntax otherwise. The rest of the query, including
+// whitespace and comments, is left untouched, as are selectors that cannot be
+// translated. Label names in function arguments, such as the ones of
+// label_replace, are not translated. Rewriters created by
+// NewTranslatorRewriter translate names with a Translator instead.
+//
+// Example usage:
+//
+//	r := promql.NewRewriter(
+//		otlptranslator.NewMetricNamer("", otlptranslator.UnderscoreEscapingWithSuffixes),
+//		otlptranslator.LabelNamer{},
+//		[]otlptranslator.Metric{{Name: "http.server.request.duration", Unit: "s", Type: otlptranslator.MetricTypeHistogram}},
+//	)
+//	query, unresolved, err := r.Rewrite(`histogram_quantile(0.9, sum by (le, "http.route") (rate({"http.server.request.duration_bucket"}[5m])))`)
+//	// query == `histogram_quantile(0.9, sum by (le, http_route) (rate(http_server_request_duration_seconds_bucket[5m])))`
+type Rewriter struct {
+	translator Translator
+}
+
+// Translator translates the names found in queries.
+type Translator interface {
+	// MetricName translates the metric name of a selector. On error, the
+	// selector is left unchanged and reported as unresolved.
+	MetricName(name string) (string, error)
+	// LabelName translates a label name. It is not called for reserved names
+	// starting with `__`.
+	LabelName(name string) (string, error)
+}
+
+// NewRewriter creates a Rewriter translating names with the given namers,
+// and looking up the type and unit of the given metrics by their OTLP name.
+func NewRewriter(metricNamer otlptranslator.MetricNamer, labelNamer otlptranslator.LabelNamer, metrics []otlptranslator.Metric) *Rewriter {
+	t := &otelTranslator{
+		metricNamer: metricNamer,
+		labelNamer:  labelNamer,
+		metrics:     make(map[string]otlptranslator.Metric, len(metrics)),
+	}
+	for _, m := range metrics {
+		t.metrics[m.Name] = m
+	}
+	return NewTranslatorRewriter(t)
+}
+
+// NewTranslatorRewriter creates a Rewriter translating names with a custom
+// Translator, for example to map names between translation strategies.
+func NewTranslatorRewriter(t Translator) *Rewriter {
+	return &Rewriter{translator: t}
+}
+
+// Rewrite returns the rewritten query, along with the selectors that could
+// not be translated. It returns an error if the query cannot be tokenized,
+// for example because of an unterminated string or label set.
+func (r *Rewriter) Rewrite(query string) (string, []Unresolved, error) {
+	p := &parser{r: r, q: query}
+	if err := p.parse(); err != nil {
+		return "", nil, err
+	}
+	var b strings.Builder
+	last := 0
+	for _, e := range p.edits {
+		b.WriteString(query[last:e.start])
+		b.WriteString(e.text)
+		last = e.end
+	}
+	b.WriteString(query[last:])
+	return b.String(), p.unresolved, nil
+}
+
+// structuralSuffixes are the suffixes of the series of histograms, summaries
+// and counters, by the kinds of metrics having them.
+var structuralSuffixes = []struct {
+	suffix string
+	kinds  []otlptranslator.MetricKind
+}{
+	{"_bucket", []otlptranslator.MetricKind{otlptranslator.MetricKindHistogram}},
+	{"_sum", []otlptranslator.MetricKind{otlptranslator.MetricKindHistogram, otlptranslator.MetricKindExponentialHistogram, otlptranslator.MetricKindSummary}},
+	{"_count", []otlptranslator.MetricKind{otlptranslator.MetricKindHistogram, otlptranslator.MetricKindExponentialHistogram, otlptranslator.MetricKindSummary}},
+	{"_created", []otlptranslator.MetricKind{otlptranslator.MetricKindSum, otlptranslator.MetricKindHistogram, otlptranslator.MetricKindExponentialHistogram, otlptranslator.MetricKindSummary}},
+}
+
+// otelTranslator translates OpenTelemetry names.
+type otelTranslator struct {
+	metricNamer otlptranslator.MetricNamer
+	labelNamer  otlptranslator.LabelNamer
+	metrics     map[string]otlptranslator.Metric
+}
+
+// MetricName implements Translator.
+func (r *otelTranslator) MetricName(name string) (string, error) {
+	if m, ok := r.metrics[name]; ok {
+		return r.metricNamer.Build(m)
+	}
+	for _, s := range structuralSuffixes {
+		base, ok := strings.CutSuffix(name, s.suffix)
+		if !ok {
+			continue
+		}
+		m, ok := r.metrics[base]
+		if !ok || !slices.Contains(s.kinds, m.Type.Kind()) {
+			continue
+		}
+		if s.suffix == "_created" {
+			return r.metricNamer.BuildCreated(m)
+		}
+		built, err := r.metricNamer.Build(m)
+		if err != nil {
+			return "", err
+		}
+		return built + s.suffix, nil
+	}
+	if r.metricNamer.WithMetricSuffixes {
+		return "", errors.New("unknown metric, whose type and unit are needed to add suffixes")
+	}
+	return r.metricNamer.Build(otlptranslator.Metric{Name: name, Type: otlptranslator.MetricTypeGauge})
+}
+
+// LabelName implements Translator.
+func (r *otelTranslator) LabelName(name string) (string, error) {
+	return r.labelNamer.Build(name)
+}
+
+// labelName translates a label name, keeping reserved ones.
+func (r *Rewriter) labelName(name string) (string, error) {
+	if strings.HasPrefix(name, "__") {
+		return name, nil
+	}
+	return r.translator.LabelName(name)
+}
+
+// edit replaces the query bytes in [start, end) with text.
+type edit struct {
+	start, end int
+	text       string
+}
+
+// parser scans a query for selectors and grouping clauses.
+type parser struct {
+	r          *Rewriter
+	q          string
+	pos        int
+	edits      []edit
+	unresolved []Unresolved
+}
+
+var (
+	// groupingKeywords introduce a list of label names.
+	groupingKeywords = map[string]bool{
+		"by": true, "without": true, "on": true, "ignoring": true, "group_left": true, "group_right": true,
+	}
+	// aggregations can be followed by a by or without clause before their
+	// arguments.
+	aggregations = map[string]bool{
+		"sum": true, "avg": true, "count": true, "min": true, "max": true, "group": true,
+		"stddev": true, "stdvar": true, "topk": true, "bottomk": true, "quantile": true,
+		"count_values": true, "limitk": true, "limit_ratio": true,
+	}
+	// keywords are the identifiers that are never metric names.
+	keywords = map[string]bool{
+		"and": true, "or": true, "unless": true, "atan2": true, "bool": true,
+		"offset": true, "inf": true, "nan": true,
+	}
+)
+
+func (p *parser) parse() error {
+	for p.pos < len(p.q) {
+		c := p.q[p.pos]
+		switch {
+		case c == '#':
+			p.skipComment()
+		case c == '"' || c == '\'' || c == '`':
+			if _, _, err := p.str(); err != nil {
+				return err
+			}
+		case c == '[':
+			end := strings.IndexByte(p.q[p.pos:], ']')
+			if end < 0 {
+				return p.errorf("unterminated range")
+			}
+			p.pos += end + 1
+		case isDigit(c) || c == '.' && p.pos+1 < len(p.q) && isDigit(p.q[p.pos+1]):
+			p.number()
+		case isNameStart(c):
+			if err := p.identifier(); err != nil {
+				return err
+			}
+		case c == '{':
+			if err := p.selector(p.pos, ""); err != nil {
+				return err
+			}
+		case c == '}':
+			return p.errorf("unexpected }")
+		default:
+			p.pos++
+		}
+	}
+	return nil
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	return fmt.Errorf("promql: position %d: %s", p.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) skipComment() {
+	if end := strings.IndexByte(p.q[p.pos:], '\n'); end >= 0 {
+		p.pos += end + 1
+	} else {
+		p.pos = len(p.q)
+	}
+}
+
+func (p *parser) skipSpaces() {
+	for p.pos < len(p.q) {
+		switch p.q[p.pos] {
+		case ' ', '\t', '\n', '\r':
+			p.pos++
+		case '#':
+			p.skipComment()
+		default:
+			return
+		}
+	}
+}
+
+// peek returns the next non-space byte, without consuming anything.
+func (p *parser) peek() byte {
+	pos := p.pos
+	p.skipSpaces()
+	defer func() { p.pos = pos }()
+	if p.pos == len(p.q) {
+		return 0
+	}
+	return p.q[p.pos]
+}
+
+// peekWord returns the next identifier, without consuming anything.
+func (p *parser) peekWord() string {
+	pos := p.pos
+	p.skipSpaces()
+	defer func() { p.pos = pos }()
+	return p.name()
+}
+
+// name consumes an identifier, which may hold colons.
+func (p *parser) name() string {
+	start := p.pos
+	for p.pos < len(p.q) && (isNameStart(p.q[p.pos]) || isDigit(p.q[p.pos]) && p.pos > start) {
+		p.pos++
+	}
+	return p.q[start:p.pos]
+}
+
+// number consumes a number or a duration.
+func (p *parser) number() {
+	for p.pos < len(p.q) {
+		c := p.q[p.pos]
+		switch {
+		case isDigit(c) || isNameStart(c) && c != ':' || c == '.':
+			p.pos++
+			if (c == 'e' || c == 'E') && p.pos < len(p.q) && (p.q[p.pos] == '+' || p.q[p.pos] == '-') {
+				p.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+// str consumes a string literal, and returns its unquoted value and its raw
+// text.
+func (p *parser) str() (string, string, error) {
+	start := p.pos
+	quote := p.q[p.pos]
+	for p.pos++; p.pos < len(p.q); p.pos++ {
+		switch p.q[p.pos] {
+		case '\\':
+			if quote != '`' {
+				p.pos++
+			}
+		case quote:
+			p.pos++
+			raw := p.q[start:p.pos]
+			s, err := unquote(raw)
+			if err != nil {
+				return "", "", fmt.Errorf("promql: position %d: invalid string %s: %w", start, raw, err)
+			}
+			return s, raw, nil
+		}
+	}
+	return "", "", fmt.Errorf("promql: position %d: unterminated string", start)
+}
+
+// unquote unquotes a PromQL string literal, which follows the Go syntax
+// except that single quotes delimit strings too.
+func unquote(raw string) (string, error) {
+	if raw[0] != '\'' {
+		return strconv.Unquote(raw)
+	}
+	var b strings.Builder
+	b.WriteByte('"')
+	inner := raw[1 : len(raw)-1]
+	for i := 0; i < len(inner); i++ {
+		switch c := inner[i]; {
+		case c == '\\' && i+1 < len(inner) && inner[i+1] == '\'':
+			b.WriteByte('\'')
+			i++
+		case c == '\\' && i+1 < len(inner):
+			b.WriteString(inner[i : i+2])
+			i++
+		case c == '"':
+			b.WriteString(`\"`)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte('"')
+	return strconv.Unquote(b.String())
+}
+
+// identifier consumes an identifier and what it introduces.
+func (p *parser) identifier() error {
+	start := p.pos
+	ident := p.name()
+	lower := strings.ToLower(ident)
+	next := p.peek()
+	switch {
+	case groupingKeywords[lower] && next == '(':
+		return p.labelList()
+	case next == '(':
+		// Function or aggregation call.
+		return nil
+	case aggregations[lower] && groupingKeywords[strings.ToLower(p.peekWord())]:
+		return nil
+	case keywords[lower] || groupingKeywords[lower]:
+		return nil
+	default:
+		return p.selector(start, ident)
+	}
+}
+
+// labelList consumes the parenthesized label names of a grouping clause.
+func (p *parser) labelList() error {
+	p.skipSpaces()
+	p.pos++ // (
+	for {
+		p.skipSpaces()
+		if p.pos == len(p.q) {
+			return p.errorf("unterminated label list")
+		}
+		if p.q[p.pos] == ')' {
+			p.pos++
+			return nil
+		}
+		start := p.pos
+		var label string
+		if c := p.q[p.pos]; c == '"' || c == '\'' || c == '`' {
+			s, _, err := p.str()
+			if err != nil {
+				return err
+			}
+			label = s
+		} else if isNameStart(c) {
+			label = p.name()
+		} else {
+			return p.errorf("unexpected %q in label list", c)
+		}
+		translated, err := p.r.labelName(label)
+		if err != nil {
+			p.unresolved = append(p.unresolved, Unresolved{Selector: p.q[start:p.pos], Pos: start, Reason: err.Error()})
+		} else if text := formatLabelName(translated); text != p.q[start:p.pos] {
+			p.edits = append(p.edits, edit{start: start, end: p.pos, text: text})
+		}
+		p.skipSpaces()
+		if p.pos < len(p.q) && p.q[p.pos] == ',' {
+			p.pos++
+		}
+	}
+}
+
+// nameForm is the way a selector holds its metric name.
+type nameForm int
+
+const (
+	noName nameForm = iota
+	// bareName is a name written before the braces.
+	bareName
+	// quotedName is a quoted name inside the braces.
+	quotedName
+	// matcherName is a `__name__` equality matcher.
+	matcherName
+)
+
+type selector struct {
+	start, end int
+	name       string
+	form       nameForm
+	// nameStart and nameEnd delimit the text holding the name.
+	nameStart, nameEnd int
+	matchers           []matcher
+}
+
+type matcher struct {
+	label      string
+	labelStart int
+	labelEnd   int
+	op         string
+	// value is the raw text of the value.
+	value string
+}
+
+// selector consumes a selector, starting at start with the given bare name,
+// or at an opening brace if name is empty.
+func (p *parser) selector(start int, name string) error {
+	sel := selector{start: start}
+	if name != "" {
+		sel.name, sel.form, sel.nameStart, sel.nameEnd = name, bareName, start, p.pos
+		if p.peek() != '{' {
+			sel.end = p.pos
+			p.rewriteSelector(sel)
+			return nil
+		}
+		p.skipSpaces()
+	}
+	p.pos++ // {
+	for {
+		p.skipSpaces()
+		if p.pos == len(p.q) {
+			return p.errorf("unterminated label set")
+		}
+		if p.q[p.pos] == '}' {
+			p.pos++
+			break
+		}
+		m := matcher{labelStart: p.pos}
+		switch c := p.q[p.pos]; {
+		case c == '"' || c == '\'' || c == '`':
+			s, _, err := p.str()
+			if err != nil {
+				return err
+			}
+			m.label = s
+		case isNameStart(c):
+			m.label = p.name()
+		default:
+			return p.errorf("unexpected %q in label set", c)
+		}
+		m.labelEnd = p.pos
+		p.skipSpaces()
+		op := p.operator()
+		switch {
+		case op == "" && sel.form == noName && !isNameStart(p.q[m.labelStart]):
+			sel.name, sel.form, sel.nameStart, sel.nameEnd = m.label, quotedName, m.labelStart, m.labelEnd
+		case op == "":
+			return p.errorf("missing matcher operator")
+		default:
+			p.skipSpaces()
+			if p.pos == len(p.q) || !strings.ContainsRune("\"'`", rune(p.q[p.pos])) {
+				return p.errorf("missing matcher value")
+			}
+			valueStart := p.pos
+			value, raw, err := p.str()
+			if err != nil {
+				return err
+			}
+			m.op, m.value = op, raw
+			if m.label == otlptranslator.MetricNameLabel && op == "=" && sel.form == noName {
+				sel.name, sel.form, sel.nameStart, sel.nameEnd = value, matcherName, valueStart, p.pos
+			}
+			sel.matchers = append(sel.matchers, m)
+		}
+		p.skipSpaces()
+		if p.pos < len(p.q) && p.q[p.pos] == ',' {
+			p.pos++
+		} else if p.pos < len(p.q) && p.q[p.pos] != '}' {
+			return p.errorf("expected , or } in label set")
+		}
+	}
+	sel.end = p.pos
+	p.rewriteSelector(sel)
+	return nil
+}
+
+// operator consumes a label matcher operator.
+func (p *parser) operator() string {
+	for _, op := range []string{"!=", "=~", "!~", "="} {
+		if strings.HasPrefix(p.q[p.pos:], op) {
+			p.pos += len(op)
+			return op
+		}
+	}
+	return ""
+}
+
+// rewriteSelector records the edits translating a selector, or reports it as
+// unresolved.
+func (p *parser) rewriteSelector(sel selector) {
+	unresolved := func(reason string) {
+		p.unresolved = append(p.unresolved, Unresolved{Selector: p.q[sel.start:sel.end], Pos: sel.start, Reason: reason})
+	}
+	var name string
+	if sel.form != noName {
+		var err error
+		if name, err = p.r.translator.MetricName(sel.name); err != nil {
+			unresolved(err.Error())
+			return
+		}
+	}
+	labels := make([]string, len(sel.matchers))
+	for i, m := range sel.matchers {
+		if m.label == otlptranslator.MetricNameLabel && sel.form == noName && m.op != "!=" {
+			unresolved("metric name matchers other than equality cannot be translated")
+			return
+		}
+		var err error
+		if labels[i], err = p.r.labelName(m.label); err != nil {
+			unresolved(err.Error())
+			return
+		}
+	}
+
+	legacyName := legacy.IsMetricName(name)
+	if sel.form == bareName && !legacyName || sel.form == quotedName && legacyName {
+		// The name moves between the bare and quoted syntaxes: the whole
+		// selector is rewritten.
+		var items []string
+		if !legacyName {
+			items = append(items, strconv.Quote(name))
+		}
+		for i, m := range sel.matchers {
+			items = append(items, formatLabelName(labels[i])+m.op+m.value)
+		}
+		text := name
+		if !legacyName {
+			text = ""
+		}
+		if len(items) > 0 {
+			text += "{" + strings.Join(items, ", ") + "}"
+		}
+		p.edits = append(p.edits, edit{start: sel.start, end: sel.end, text: text})
+		return
+	}
+
+	var edits []edit
+	switch sel.form {
+	case bareName:
+		edits = append(edits, edit{start: sel.nameStart, end: sel.nameEnd, text: name})
+	case quotedName, matcherName:
+		edits = append(edits, edit{start: sel.nameStart, end: sel.nameEnd, text: strconv.Quote(name)})
+	}
+	for i, m := range sel.matchers {
+		edits = append(edits, edit{start: m.labelStart, end: m.labelEnd, text: formatLabelName(labels[i])})
+	}
+	slices.SortFunc(edits, func(a, b edit) int { return a.start - b.start })
+	for _, e := range edits {
+		if e.text != p.q[e.start:e.end] {
+			p.edits = append(p.edits, e)
+		}
+	}
+}
+
+// formatLabelName returns a label name as written in a query, quoted if it is
+// not valid under the classic scheme.
+func formatLabelName(name string) string {
+	if legacy.IsLabelName(name) {
+		return name
+	}
+	return strconv.Quote(name)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isNameStart(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_' || c == ':'
+}
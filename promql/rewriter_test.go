@@ -0,0 +1,157 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package promql
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/prometheus/otlptranslator"
+)
+
+var testMetrics = []otlptranslator.Metric{
+	{Name: "http.server.request.duration", Unit: "s", Type: otlptranslator.MetricTypeHistogram},
+	{Name: "http.server.requests", Type: otlptranslator.MetricTypeMonotonicCounter},
+	{Name: "rpc.duration", Unit: "ms", Type: otlptranslator.MetricTypeSummary},
+	{Name: "system.memory.usage", Unit: "By", Type: otlptranslator.MetricTypeNonMonotonicCounter},
+}
+
+func TestRewriter(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		strategy otlptranslator.TranslationStrategyOption
+		query    string
+		want     string
+	}{
+		{
+			name:     "quoted name",
+			strategy: otlptranslator.UnderscoreEscapingWithSuffixes,
+			query:    `{"http.server.requests"}`,
+			want:     `http_server_requests_total`,
+		},
+		{
+			name:     "quoted name with matchers",
+			strategy: otlptranslator.UnderscoreEscapingWithSuffixes,
+			query:    `rate({"http.server.requests", "http.route"="/api", code=~"5.."}[5m])`,
+			want:     `rate(http_server_requests_total{http_route="/api", code=~"5.."}[5m])`,
+		},
+		{
+			name:     "histogram bucket",
+			strategy: otlptranslator.UnderscoreEscapingWithSuffixes,
+			query:    `histogram_quantile(0.9, sum by (le, "http.route") (rate({"http.server.request.duration_bucket"}[5m])))`,
+			want:     `histogram_quantile(0.9, sum by (le, http_route) (rate(http_server_request_duration_seconds_bucket[5m])))`,
+		},
+		{
+			name:     "summary count and counter created",
+			strategy: otlptranslator.UnderscoreEscapingWithSuffixes,
+			query:    `{"rpc.duration_count"} / {"http.server.requests_created"}`,
+			want:     `rpc_duration_milliseconds_count / http_server_requests_created`,
+		},
+		{
+			name:     "name matcher",
+			strategy: otlptranslator.UnderscoreEscapingWithSuffixes,
+			query:    `{__name__="system.memory.usage", 'system.memory.state'="used"}`,
+			want:     `{__name__="system_memory_usage_bytes", system_memory_state="used"}`,
+		},
+		{
+			name:     "bare name and layout preserved",
+			strategy: otlptranslator.UnderscoreEscapingWithoutSuffixes,
+			query:    "sum without (\"k8s.pod.name\") (\n  up{job=\"api\"} # comment {\"x\"}\n) * on (\"service.name\") group_left topk(3, x:y offset 5m)",
+			want:     "sum without (k8s_pod_name) (\n  up{job=\"api\"} # comment {\"x\"}\n) * on (service_name) group_left topk(3, x:y offset 5m)",
+		},
+		{
+			name:     "unknown metric without suffixes",
+			strategy: otlptranslator.UnderscoreEscapingWithoutSuffixes,
+			query:    `{"k8s.pod.cpu.time"}[1h:5m] > bool 2e-3`,
+			want:     `k8s_pod_cpu_time[1h:5m] > bool 2e-3`,
+		},
+		{
+			name:     "UTF-8 names stay quoted",
+			strategy: otlptranslator.NoUTF8EscapingWithSuffixes,
+			query:    `count({"http.server.requests", "http.route"="/"}) by ("http.route")`,
+			want:     `count({"http.server.requests_total", "http.route"="/"}) by ("http.route")`,
+		},
+		{
+			name:     "unknown bare name is kept",
+			strategy: otlptranslator.NoUTF8EscapingWithSuffixes,
+			query:    `sum(rpc_count{a="1"})`,
+			want:     `sum(rpc_count{a="1"})`,
+		},
+		{
+			name:     "keywords and functions",
+			strategy: otlptranslator.UnderscoreEscapingWithoutSuffixes,
+			query:    `count by (x) (a) and Inf unless NaN or vector(1) @ start()`,
+			want:     `count by (x) (a) and Inf unless NaN or vector(1) @ start()`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := NewRewriter(
+				otlptranslator.NewMetricNamer("", tc.strategy),
+				otlptranslator.LabelNamer{UTF8Allowed: !tc.strategy.ShouldEscape()},
+				testMetrics,
+			)
+			got, unresolved, err := r.Rewrite(tc.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tc.want {
+				t.Errorf("Rewrite(%q) =\n%s\nwant\n%s", tc.query, got, tc.want)
+			}
+			if tc.strategy != otlptranslator.NoUTF8EscapingWithSuffixes && len(unresolved) > 0 {
+				t.Errorf("unresolved: %v", unresolved)
+			}
+		})
+	}
+}
+
+func TestRewriter_Unresolved(t *testing.T) {
+	r := NewRewriter(otlptranslator.NewMetricNamer("", otlptranslator.UnderscoreEscapingWithSuffixes), otlptranslator.LabelNamer{}, testMetrics)
+	query := `{"k8s.pod.cpu.time", "k8s.pod.name"="a"} + on(".") {__name__=~"http.*"} + {"http.server.requests_bucket"}`
+	got, unresolved, err := r.Rewrite(query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != query {
+		t.Errorf("Rewrite() = %s, want the query unchanged", got)
+	}
+	want := []Unresolved{
+		{Selector: `{"k8s.pod.cpu.time", "k8s.pod.name"="a"}`, Pos: 0, Reason: "unknown metric, whose type and unit are needed to add suffixes"},
+		{Selector: `"."`, Pos: 46, Reason: `normalization for label name "." resulted in invalid name "_"`},
+		{Selector: `{__name__=~"http.*"}`, Pos: 51, Reason: "metric name matchers other than equality cannot be translated"},
+		{Selector: `{"http.server.requests_bucket"}`, Pos: 74, Reason: "unknown metric, whose type and unit are needed to add suffixes"},
+	}
+	if !reflect.DeepEqual(unresolved, want) {
+		t.Errorf("unresolved:\ngot  %v\nwant %v", unresolved, want)
+	}
+}
+
+func TestRewriter_Errors(t *testing.T) {
+	r := NewRewriter(otlptranslator.NewMetricNamer("", otlptranslator.UnderscoreEscapingWithoutSuffixes), otlptranslator.LabelNamer{}, nil)
+	for query, want := range map[string]string{
+		`up{job="api"`:      "promql: position 12: unterminated label set",
+		`up{job="api}`:      "promql: position 7: unterminated string",
+		`rate(up[5m)`:       "promql: position 7: unterminated range",
+		`up{job}`:           "promql: position 6: missing matcher operator",
+		`up{job=api}`:       "promql: position 7: missing matcher value",
+		`sum by (a`:         "promql: position 9: unterminated label list",
+		`up}`:               "promql: position 2: unexpected }",
+		`{"a"="\q"}`:        `promql: position 5: invalid string "\q": invalid syntax`,
+		`up{a="1" b="2"}`:   "promql: position 9: expected , or } in label set",
+		`sum by (a, 1) (x)`: `promql: position 11: unexpected '1' in label list`,
+	} {
+		if _, _, err := r.Rewrite(query); err == nil || err.Error() != want {
+			t.Errorf("Rewrite(%s) error = %v, want %s", query, err, want)
+		}
+	}
+}
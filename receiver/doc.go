@@ -0,0 +1,22 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package receiver implements a push-to-pull bridge: an OTLP/HTTP receiver
+// accepting metric pushes, whose translated and accumulated state is served
+// in the Prometheus exposition formats.
+//
+// Main components:
+//   - Receiver: Holds the translated state of the received metrics
+//   - Receiver.OTLPHandler: Accepts OTLP/HTTP metrics export requests
+//   - Receiver.MetricsHandler: Serves the state on a /metrics endpoint
+package receiver
@@ -0,0 +1,251 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package receiver
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/otlptranslator"
+	"github.com/prometheus/otlptranslator/exposition"
+	"github.com/prometheus/otlptranslator/otlp"
+)
+
+const (
+	// DefaultTTL is the default time after which series that received no
+	// data point are expired.
+	DefaultTTL = 5 * time.Minute
+	// DefaultMaxRequestBodySize is the default maximum size of OTLP request
+	// bodies, after decompression.
+	DefaultMaxRequestBodySize = 16 << 20
+)
+
+// Options configures a Receiver.
+type Options struct {
+	// Converter translates the received metrics. Its SampleBuilder always
+	// builds created series, which are only exposed in OpenMetrics, and its
+	// Accumulator is set by the receiver.
+	Converter otlp.Converter
+	// TTL is the time after which a series that received no data point is
+	// removed, along with its delta-to-cumulative state. It defaults to
+	// DefaultTTL.
+	TTL time.Duration
+	// MaxRequestBodySize is the maximum size of a request body, after
+	// decompression. It defaults to DefaultMaxRequestBodySize.
+	MaxRequestBodySize int64
+	// Now returns the current time. It defaults to time.Now.
+	Now func() time.Time
+}
+
+// Receiver bridges OTLP pushes to Prometheus scrapes.
+//
+// The OTLP handler translates every received data point with the converter,
+// delta sums and histograms being accumulated into cumulative ones, and
+// keeps the latest samples of every series. The metrics handler exposes
+// them, without timestamps as for any scraped target, in the Prometheus text
+// format or in OpenMetrics, depending on the Accept header of the scrape.
+// Exponential histograms are exposed as classic histograms, following the
+// layout of the converter.
+//
+// A data point flagged with no recorded value removes its series, and series
+// that did not receive any data point for the TTL are expired.
+//
+// Example usage:
+//
+//	namer := otlptranslator.NewMetricNamer("", otlptranslator.UnderscoreEscapingWithSuffixes)
+//	r := receiver.NewReceiver(receiver.Options{
+//		Converter: otlp.Converter{SampleBuilder: otlptranslator.SampleBuilder{MetricNamer: namer}},
+//	})
+//	http.Handle("/v1/metrics", r.OTLPHandler())
+//	http.Handle("/metrics", r.MetricsHandler())
+type Receiver struct {
+	opts Options
+
+	mtx   sync.Mutex
+	store *store
+}
+
+// NewReceiver creates a Receiver.
+func NewReceiver(opts Options) *Receiver {
+	if opts.TTL <= 0 {
+		opts.TTL = DefaultTTL
+	}
+	if opts.MaxRequestBodySize <= 0 {
+		opts.MaxRequestBodySize = DefaultMaxRequestBodySize
+	}
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+	sb := &opts.Converter.SampleBuilder
+	sb.CreatedSeries = true
+	opts.Converter.Accumulator = otlptranslator.NewDeltaAccumulator(sb.MetricNamer, sb.LabelNamer, otlptranslator.DeltaAccumulatorOptions{
+		TTL: opts.TTL,
+		Now: opts.Now,
+	})
+	return &Receiver{
+		opts:  opts,
+		store: newStore(sb.MetricNamer),
+	}
+}
+
+// OTLPHandler returns the handler accepting OTLP/HTTP metrics export
+// requests, encoded in protobuf or JSON, optionally gzip-compressed. It
+// responds with an ExportMetricsServiceResponse, reporting a partial success
+// if some data points were rejected, or with a google.rpc.Status on errors,
+// in the encoding of the request.
+func (r *Receiver) OTLPHandler() http.Handler {
+	return http.HandlerFunc(r.serveOTLP)
+}
+
+// MetricsHandler returns the handler serving the translated state of the
+// received metrics.
+func (r *Receiver) MetricsHandler() http.Handler {
+	return http.HandlerFunc(r.serveMetrics)
+}
+
+func (r *Receiver) serveOTLP(w http.ResponseWriter, req *http.Request) {
+	contentType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if contentType != otlp.JSONContentType {
+		// Errors are reported in protobuf unless the request is JSON.
+		contentType = otlp.ProtobufContentType
+	}
+	if req.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeStatus(w, contentType, http.StatusMethodNotAllowed, fmt.Sprintf("method %s not allowed", req.Method))
+		return
+	}
+	if ct, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type")); ct != otlp.ProtobufContentType && ct != otlp.JSONContentType {
+		writeStatus(w, contentType, http.StatusUnsupportedMediaType, fmt.Sprintf("unsupported content type %q", ct))
+		return
+	}
+
+	body, status, err := r.readBody(req)
+	if err != nil {
+		writeStatus(w, contentType, status, err.Error())
+		return
+	}
+	var mr *otlp.MetricsRequest
+	if contentType == otlp.JSONContentType {
+		mr, err = otlp.DecodeMetricsRequestJSON(body)
+	} else {
+		mr, err = otlp.DecodeMetricsRequest(body)
+	}
+	if err != nil {
+		writeStatus(w, contentType, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	r.mtx.Lock()
+	r.expire()
+	r.store.now = r.opts.Now()
+	r.store.accepted = 0
+	err = r.opts.Converter.Convert(mr, r.store)
+	rejected := dataPoints(mr) - r.store.accepted
+	r.mtx.Unlock()
+
+	var msg string
+	if err != nil {
+		msg = err.Error()
+	}
+	writeResponse(w, contentType, rejected, msg)
+}
+
+// readBody reads the, possibly compressed, request body. On error, it also
+// returns the HTTP status to respond with.
+func (r *Receiver) readBody(req *http.Request) ([]byte, int, error) {
+	body := io.Reader(req.Body)
+	switch enc := strings.ToLower(req.Header.Get("Content-Encoding")); enc {
+	case "", "identity":
+	case "gzip":
+		gz, err := gzip.NewReader(req.Body)
+		if err != nil {
+			return nil, http.StatusBadRequest, fmt.Errorf("invalid gzip body: %w", err)
+		}
+		defer gz.Close()
+		body = gz
+	default:
+		return nil, http.StatusUnsupportedMediaType, fmt.Errorf("unsupported content encoding %q", enc)
+	}
+	b, err := io.ReadAll(io.LimitReader(body, r.opts.MaxRequestBodySize+1))
+	switch {
+	case err != nil:
+		return nil, http.StatusBadRequest, fmt.Errorf("reading body: %w", err)
+	case int64(len(b)) > r.opts.MaxRequestBodySize:
+		return nil, http.StatusRequestEntityTooLarge, fmt.Errorf("request body exceeds %d bytes", r.opts.MaxRequestBodySize)
+	}
+	return b, 0, nil
+}
+
+func (r *Receiver) serveMetrics(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, fmt.Sprintf("method %s not allowed", req.Method), http.StatusMethodNotAllowed)
+		return
+	}
+	namer := r.opts.Converter.SampleBuilder.MetricNamer
+	var out interface {
+		otlp.Appender
+		ContentType() string
+		WriteTo(io.Writer) (int64, error)
+	}
+	if strings.Contains(req.Header.Get("Accept"), "application/openmetrics-text") {
+		out = exposition.NewOpenMetricsWriter(namer)
+	} else {
+		out = exposition.NewTextWriter(namer)
+	}
+
+	r.mtx.Lock()
+	r.expire()
+	r.store.writeTo(out)
+	r.mtx.Unlock()
+
+	w.Header().Set("Content-Type", out.ContentType())
+	if req.Method == http.MethodHead {
+		return
+	}
+	_, _ = out.WriteTo(w)
+}
+
+// Len returns the number of series currently exposed.
+func (r *Receiver) Len() int {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.expire()
+	return r.store.len()
+}
+
+// expire removes the series that expired. r.mtx must be held.
+func (r *Receiver) expire() {
+	r.store.expire(r.opts.Now().Add(-r.opts.TTL))
+	r.opts.Converter.Accumulator.Expire()
+}
+
+// dataPoints returns the number of data points of a request.
+func dataPoints(mr *otlp.MetricsRequest) int {
+	var n int
+	for _, rm := range mr.ResourceMetrics {
+		for _, sm := range rm.ScopeMetrics {
+			for _, m := range sm.Metrics {
+				n += len(m.NumberDataPoints) + len(m.HistogramDataPoints) + len(m.ExponentialHistogramDataPoints) + len(m.SummaryDataPoints)
+			}
+		}
+	}
+	return n
+}
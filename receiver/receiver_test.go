@@ -0,0 +1,299 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package receiver
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/otlptranslator"
+	"github.com/prometheus/otlptranslator/internal/protobuf"
+	"github.com/prometheus/otlptranslator/otlp"
+)
+
+// fakeClock is a settable clock.
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func newTestReceiver(clock *fakeClock) *Receiver {
+	namer := otlptranslator.NewMetricNamer("", otlptranslator.UnderscoreEscapingWithSuffixes)
+	return NewReceiver(Options{
+		Converter: otlp.Converter{SampleBuilder: otlptranslator.SampleBuilder{MetricNamer: namer}},
+		TTL:       time.Minute,
+		Now:       clock.Now,
+	})
+}
+
+// sumRequest returns a JSON request holding a delta counter data point.
+func sumRequest(method string, value, start, end int) string {
+	return `{"resourceMetrics":[{"resource":{"attributes":[{"key":"service.name","value":{"stringValue":"api"}}]},` +
+		`"scopeMetrics":[{"metrics":[{"name":"http.requests","description":"Number of requests.","sum":{"dataPoints":[` +
+		`{"attributes":[{"key":"http.method","value":{"stringValue":"` + method + `"}}],` +
+		`"startTimeUnixNano":"` + strconv.Itoa(start) + `000000000","timeUnixNano":"` + strconv.Itoa(end) + `000000000","asInt":"` + strconv.Itoa(value) + `"}],` +
+		`"aggregationTemporality":1,"isMonotonic":true}}]}]}]}`
+}
+
+func push(t *testing.T, h http.Handler, contentType string, body []byte, header http.Header) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/v1/metrics", bytes.NewReader(body))
+	req.Header.Set("Content-Type", contentType)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func scrape(t *testing.T, h http.Handler, accept string) (string, string) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	req.Header.Set("Accept", accept)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("scrape status = %d, want 200", rec.Code)
+	}
+	return rec.Header().Get("Content-Type"), rec.Body.String()
+}
+
+func TestReceiver(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(100, 0)}
+	r := newTestReceiver(clock)
+	otlpHandler, metricsHandler := r.OTLPHandler(), r.MetricsHandler()
+
+	for _, body := range []string{sumRequest("GET", 3, 1, 2), sumRequest("GET", 2, 2, 3), sumRequest("POST", 1, 1, 2)} {
+		rec := push(t, otlpHandler, otlp.JSONContentType, []byte(body), nil)
+		if rec.Code != http.StatusOK || rec.Body.String() != "{}" {
+			t.Fatalf("push: %d %s", rec.Code, rec.Body)
+		}
+	}
+	if r.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", r.Len())
+	}
+
+	contentType, got := scrape(t, metricsHandler, "text/plain")
+	if contentType != "text/plain; version=0.0.4; charset=utf-8" {
+		t.Errorf("Content-Type = %q", contentType)
+	}
+	want := `# HELP http_requests_total Number of requests.
+# TYPE http_requests_total counter
+http_requests_total{http_method="GET",job="api"} 5
+http_requests_total{http_method="POST",job="api"} 1
+`
+	if got != want {
+		t.Errorf("text scrape:\ngot\n%s\nwant\n%s", got, want)
+	}
+
+	contentType, got = scrape(t, metricsHandler, "application/openmetrics-text;version=1.0.0,text/plain;q=0.5")
+	if !strings.HasPrefix(contentType, "application/openmetrics-text") {
+		t.Errorf("Content-Type = %q", contentType)
+	}
+	want = `# TYPE http_requests counter
+# HELP http_requests Number of requests.
+http_requests_total{http_method="GET",job="api"} 5
+http_requests_created{http_method="GET",job="api"} 1
+http_requests_total{http_method="POST",job="api"} 1
+http_requests_created{http_method="POST",job="api"} 1
+# EOF
+`
+	if got != want {
+		t.Errorf("OpenMetrics scrape:\ngot\n%s\nwant\n%s", got, want)
+	}
+
+	// Only the POST series is refreshed before the TTL elapses.
+	clock.now = clock.now.Add(45 * time.Second)
+	push(t, otlpHandler, otlp.JSONContentType, []byte(sumRequest("POST", 1, 2, 3)), nil)
+	clock.now = clock.now.Add(30 * time.Second)
+	_, got = scrape(t, metricsHandler, "")
+	want = `# HELP http_requests_total Number of requests.
+# TYPE http_requests_total counter
+http_requests_total{http_method="POST",job="api"} 2
+`
+	if got != want {
+		t.Errorf("scrape after expiry:\ngot\n%s\nwant\n%s", got, want)
+	}
+
+	// The accumulated state of the expired series was dropped too.
+	clock.now = clock.now.Add(2 * time.Minute)
+	if r.Len() != 0 {
+		t.Errorf("Len() = %d after expiry, want 0", r.Len())
+	}
+	push(t, otlpHandler, otlp.JSONContentType, []byte(sumRequest("GET", 4, 3, 4)), nil)
+	if _, got = scrape(t, metricsHandler, ""); !strings.Contains(got, `{http_method="GET",job="api"} 4`) {
+		t.Errorf("scrape after restart:\n%s", got)
+	}
+}
+
+func TestReceiver_Stale(t *testing.T) {
+	r := newTestReceiver(&fakeClock{now: time.Unix(100, 0)})
+	gauge := func(flags string) string {
+		return `{"resourceMetrics":[{"scopeMetrics":[{"metrics":[{"name":"queue.size","gauge":{"dataPoints":[{"asDouble":2,"timeUnixNano":"1000000000"` + flags + `}]}}]}]}]}`
+	}
+	push(t, r.OTLPHandler(), otlp.JSONContentType, []byte(gauge("")), nil)
+	if _, got := scrape(t, r.MetricsHandler(), ""); got != "# TYPE queue_size gauge\nqueue_size 2\n" {
+		t.Errorf("scrape:\n%s", got)
+	}
+	push(t, r.OTLPHandler(), otlp.JSONContentType, []byte(gauge(`,"flags":1`)), nil)
+	if _, got := scrape(t, r.MetricsHandler(), ""); got != "" {
+		t.Errorf("scrape after staleness marker:\n%s", got)
+	}
+}
+
+// gaugeRequest returns the protobuf encoding of a request holding a single
+// gauge data point.
+func gaugeRequest(name string, value float64) []byte {
+	var dp []byte
+	dp = protobuf.AppendTag(dp, 3, protobuf.Fixed64Type)
+	dp = protobuf.AppendFixed64(dp, 1_000_000_000)
+	dp = protobuf.AppendDoubleField(dp, 4, value)
+	gauge := protobuf.AppendMessageField(nil, 1, dp)
+	metric := protobuf.AppendStringField(nil, 1, name)
+	metric = protobuf.AppendMessageField(metric, 5, gauge)
+	scope := protobuf.AppendMessageField(nil, 2, metric)
+	resource := protobuf.AppendMessageField(nil, 2, scope)
+	return protobuf.AppendMessageField(nil, 1, resource)
+}
+
+func gzipped(b []byte) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	_, _ = gz.Write(b)
+	_ = gz.Close()
+	return buf.Bytes()
+}
+
+func TestReceiver_Protobuf(t *testing.T) {
+	r := newTestReceiver(&fakeClock{now: time.Unix(100, 0)})
+	rec := push(t, r.OTLPHandler(), otlp.ProtobufContentType, gzipped(gaugeRequest("up", 1)), http.Header{"Content-Encoding": {"gzip"}})
+	if rec.Code != http.StatusOK || rec.Body.Len() != 0 || rec.Header().Get("Content-Type") != otlp.ProtobufContentType {
+		t.Fatalf("push: %d %q %x", rec.Code, rec.Header().Get("Content-Type"), rec.Body)
+	}
+
+	// An empty gauge name cannot be translated: the data point is rejected.
+	rec = push(t, r.OTLPHandler(), otlp.ProtobufContentType, gaugeRequest("", 1), nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("push status = %d, want 200", rec.Code)
+	}
+	var (
+		rejected uint64
+		msg      string
+	)
+	err := protobuf.ForEachField(rec.Body.Bytes(), func(f protobuf.Field) error {
+		return protobuf.ForEachField(f.Bytes, func(f protobuf.Field) error {
+			if f.Num == 1 {
+				rejected = f.Varint
+			} else {
+				msg = string(f.Bytes)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rejected != 1 || msg != `metric "": normalization for metric "" resulted in empty name` {
+		t.Errorf("partial success = %d %q", rejected, msg)
+	}
+	if _, got := scrape(t, r.MetricsHandler(), ""); got != "# TYPE up gauge\nup 1\n" {
+		t.Errorf("scrape:\n%s", got)
+	}
+}
+
+func TestReceiver_Errors(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		method      string
+		contentType string
+		encoding    string
+		body        []byte
+		wantStatus  int
+		wantBody    string
+	}{
+		{
+			name:        "method",
+			method:      http.MethodGet,
+			contentType: otlp.JSONContentType,
+			wantStatus:  http.StatusMethodNotAllowed,
+			wantBody:    `{"code":12,"message":"method GET not allowed"}`,
+		},
+		{
+			name:        "content type",
+			contentType: "text/plain",
+			wantStatus:  http.StatusUnsupportedMediaType,
+			wantBody:    "\x08\x0c\x12\x25unsupported content type \"text/plain\"",
+		},
+		{
+			name:        "content encoding",
+			contentType: otlp.JSONContentType,
+			encoding:    "br",
+			wantStatus:  http.StatusUnsupportedMediaType,
+			wantBody:    `{"code":12,"message":"unsupported content encoding \"br\""}`,
+		},
+		{
+			name:        "invalid gzip",
+			contentType: otlp.JSONContentType,
+			encoding:    "gzip",
+			body:        []byte("{}"),
+			wantStatus:  http.StatusBadRequest,
+			wantBody:    `{"code":3,"message":"invalid gzip body: unexpected EOF"}`,
+		},
+		{
+			name:        "too large",
+			contentType: otlp.JSONContentType,
+			encoding:    "gzip",
+			body:        gzipped(bytes.Repeat([]byte(" "), 2048)),
+			wantStatus:  http.StatusRequestEntityTooLarge,
+			wantBody:    `{"code":3,"message":"request body exceeds 1024 bytes"}`,
+		},
+		{
+			name:        "invalid request",
+			contentType: otlp.JSONContentType,
+			body:        []byte(`{"resourceMetrics":1}`),
+			wantStatus:  http.StatusBadRequest,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := NewReceiver(Options{MaxRequestBodySize: 1024})
+			method := tc.method
+			if method == "" {
+				method = http.MethodPost
+			}
+			req := httptest.NewRequest(method, "/v1/metrics", bytes.NewReader(tc.body))
+			req.Header.Set("Content-Type", tc.contentType)
+			req.Header.Set("Content-Encoding", tc.encoding)
+			rec := httptest.NewRecorder()
+			r.OTLPHandler().ServeHTTP(rec, req)
+			if rec.Code != tc.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tc.wantStatus)
+			}
+			body, _ := io.ReadAll(rec.Body)
+			if tc.wantBody != "" && string(body) != tc.wantBody {
+				t.Errorf("body = %q, want %q", body, tc.wantBody)
+			}
+		})
+	}
+}
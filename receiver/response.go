@@ -0,0 +1,73 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package receiver
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/prometheus/otlptranslator/internal/protobuf"
+	"github.com/prometheus/otlptranslator/otlp"
+)
+
+// gRPC status codes used in google.rpc.Status responses.
+const (
+	codeInvalidArgument = 3
+	codeUnimplemented   = 12
+)
+
+// writeResponse writes an ExportMetricsServiceResponse, with a partial
+// success if data points were rejected or errors occurred.
+func writeResponse(w http.ResponseWriter, contentType string, rejected int, msg string) {
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	if rejected == 0 && msg == "" {
+		if contentType == otlp.JSONContentType {
+			_, _ = w.Write([]byte("{}"))
+		}
+		return
+	}
+	if contentType == otlp.JSONContentType {
+		b, _ := json.Marshal(map[string]any{"partialSuccess": map[string]string{
+			"rejectedDataPoints": strconv.Itoa(rejected),
+			"errorMessage":       msg,
+		}})
+		_, _ = w.Write(b)
+		return
+	}
+	var partial []byte
+	partial = protobuf.AppendVarintField(partial, 1, uint64(rejected))
+	partial = protobuf.AppendStringField(partial, 2, msg)
+	_, _ = w.Write(protobuf.AppendMessageField(nil, 1, partial))
+}
+
+// writeStatus writes an error response holding a google.rpc.Status.
+func writeStatus(w http.ResponseWriter, contentType string, status int, msg string) {
+	code := codeInvalidArgument
+	if status == http.StatusUnsupportedMediaType || status == http.StatusMethodNotAllowed {
+		code = codeUnimplemented
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(status)
+	if contentType == otlp.JSONContentType {
+		b, _ := json.Marshal(map[string]any{"code": code, "message": msg})
+		_, _ = w.Write(b)
+		return
+	}
+	var b []byte
+	b = protobuf.AppendVarintField(b, 1, uint64(code))
+	b = protobuf.AppendStringField(b, 2, msg)
+	_, _ = w.Write(b)
+}
@@ -0,0 +1,137 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package receiver
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/prometheus/otlptranslator"
+	"github.com/prometheus/otlptranslator/otlp"
+)
+
+// store is an otlp.Appender keeping the latest samples of every series.
+type store struct {
+	metadata otlptranslator.MetadataBuilder
+	families map[string]*storedFamily
+
+	// now is the time samples are added at.
+	now time.Time
+	// accepted counts the data points added since it was last reset.
+	accepted int
+}
+
+type storedFamily struct {
+	metric otlptranslator.Metric
+	typ    otlptranslator.PrometheusType
+	series map[string]*storedSeries
+}
+
+// storedSeries holds the samples built from the latest data point of a
+// series.
+type storedSeries struct {
+	samples  []otlptranslator.Sample
+	lastSeen time.Time
+}
+
+func newStore(namer otlptranslator.MetricNamer) *store {
+	return &store{
+		metadata: otlptranslator.MetadataBuilder{
+			MetricNamer: namer,
+			UnitNamer:   otlptranslator.UnitNamer{UTF8Allowed: namer.UTF8Allowed, Behavior: namer.Behavior},
+		},
+		families: map[string]*storedFamily{},
+	}
+}
+
+// Add implements otlp.Appender. The samples, built from a single data point,
+// replace the ones of the series, or remove it if they are staleness
+// markers.
+func (s *store) Add(metric otlptranslator.Metric, samples ...otlptranslator.Sample) error {
+	if len(samples) == 0 {
+		return nil
+	}
+	md, err := s.metadata.Build(metric)
+	if err != nil {
+		return err
+	}
+	f, ok := s.families[md.FamilyName]
+	switch {
+	case !ok:
+		f = &storedFamily{typ: md.Type, series: map[string]*storedSeries{}}
+		s.families[md.FamilyName] = f
+	case f.typ != md.Type:
+		return fmt.Errorf("metric family %q already has type %s, got %s", md.FamilyName, f.typ, md.Type)
+	}
+	f.metric = metric
+	if metric.Name != otlptranslator.TargetInfoMetricName {
+		s.accepted++
+	}
+
+	// The last sample, `_count`, `_created` or the sample of a sum or gauge,
+	// carries the labels of the data point.
+	key := samples[len(samples)-1].Labels.String()
+	if otlptranslator.IsStaleNaN(samples[len(samples)-1].Value) {
+		delete(f.series, key)
+		return nil
+	}
+	stripped := make([]otlptranslator.Sample, len(samples))
+	for i, sample := range samples {
+		// Scraped samples get the time of the scrape.
+		sample.Timestamp = 0
+		stripped[i] = sample
+	}
+	f.series[key] = &storedSeries{samples: stripped, lastSeen: s.now}
+	return nil
+}
+
+// expire removes the series last seen before the given time.
+func (s *store) expire(before time.Time) {
+	for name, f := range s.families {
+		for key, series := range f.series {
+			if series.lastSeen.Before(before) {
+				delete(f.series, key)
+			}
+		}
+		if len(f.series) == 0 {
+			delete(s.families, name)
+		}
+	}
+}
+
+func (s *store) len() int {
+	var n int
+	for _, f := range s.families {
+		n += len(f.series)
+	}
+	return n
+}
+
+// writeTo adds all series to app, sorted by labels. Families that app
+// rejects are skipped.
+func (s *store) writeTo(app otlp.Appender) {
+	for _, f := range s.families {
+		keys := make([]string, 0, len(f.series))
+		for key := range f.series {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if err := app.Add(f.metric, f.series[key].samples...); err != nil {
+				break
+			}
+		}
+	}
+}
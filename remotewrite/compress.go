@@ -0,0 +1,42 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remotewrite
+
+import "github.com/prometheus/otlptranslator/internal/snappy"
+
+const (
+	// ContentEncoding is the content encoding of remote-write requests
+	// compressed by CompressSnappy.
+	ContentEncoding = "snappy"
+	// VersionHeader is the HTTP header holding the remote-write version of a
+	// request.
+	VersionHeader = "X-Prometheus-Remote-Write-Version"
+	// V1Version is the VersionHeader value of remote-write 1.0 requests.
+	V1Version = "0.1.0"
+	// V2Version is the VersionHeader value of remote-write 2.0 requests.
+	V2Version = "2.0.0"
+)
+
+// CompressSnappy compresses an encoded request with the Snappy block format,
+// as required by the remote-write specification.
+func CompressSnappy(body []byte) []byte {
+	return snappy.Encode(body)
+}
+
+// CompressSnappyFramed compresses an encoded request with the Snappy framing
+// format, for tools and storage reading Snappy streams. Remote-write receivers
+// only accept the block format of CompressSnappy.
+func CompressSnappyFramed(body []byte) []byte {
+	return snappy.EncodeFramed(body)
+}
@@ -0,0 +1,22 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package remotewrite encodes metrics translated by otlptranslator into
+// Prometheus remote-write requests, without depending on Prometheus nor on a
+// protobuf runtime.
+//
+// Main components:
+//   - V1Encoder: Encodes remote-write 1.0 prometheus.WriteRequest messages
+//   - V2Encoder: Encodes remote-write 2.0 io.prometheus.write.v2.Request messages
+//   - CompressSnappy: Compresses encoded requests for sending
+package remotewrite
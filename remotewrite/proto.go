@@ -0,0 +1,133 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remotewrite
+
+import (
+	"strings"
+
+	"github.com/prometheus/otlptranslator"
+	"github.com/prometheus/otlptranslator/internal/protobuf"
+)
+
+// metricType is the prometheus.MetricMetadata.MetricType enum of remote-write
+// 1.0, whose values are the same as the io.prometheus.write.v2.Metadata
+// ones.
+type metricType uint64
+
+const (
+	metricTypeUnknown        metricType = 0
+	metricTypeCounter        metricType = 1
+	metricTypeGauge          metricType = 2
+	metricTypeHistogram      metricType = 3
+	metricTypeGaugeHistogram metricType = 4
+	metricTypeSummary        metricType = 5
+)
+
+// newMetadataBuilder returns a MetadataBuilder translating units the same way
+// as names.
+func newMetadataBuilder(namer otlptranslator.MetricNamer) otlptranslator.MetadataBuilder {
+	return otlptranslator.MetadataBuilder{
+		MetricNamer: namer,
+		UnitNamer:   otlptranslator.UnitNamer{UTF8Allowed: namer.UTF8Allowed, Behavior: namer.Behavior},
+	}
+}
+
+func metricTypeOf(typ otlptranslator.PrometheusType) metricType {
+	switch typ {
+	case otlptranslator.PrometheusTypeCounter:
+		return metricTypeCounter
+	case otlptranslator.PrometheusTypeGauge:
+		return metricTypeGauge
+	case otlptranslator.PrometheusTypeHistogram:
+		return metricTypeHistogram
+	case otlptranslator.PrometheusTypeGaugeHistogram:
+		return metricTypeGaugeHistogram
+	case otlptranslator.PrometheusTypeSummary:
+		return metricTypeSummary
+	default:
+		return metricTypeUnknown
+	}
+}
+
+// encodeHistogram returns the protobuf encoding of a native histogram with
+// integer counts, as a prometheus.Histogram for remote-write 1.0 or as an
+// io.prometheus.write.v2.Histogram, which share the same fields.
+func encodeHistogram(h otlptranslator.NativeHistogram, timestamp int64) []byte {
+	var b []byte
+	// count_int and zero_count_int are part of oneofs, so they are written
+	// even when zero.
+	b = protobuf.AppendTag(b, 1, protobuf.VarintType)
+	b = protobuf.AppendVarint(b, h.Count)
+	b = protobuf.AppendDoubleField(b, 3, h.Sum)
+	b = protobuf.AppendVarintField(b, 4, protobuf.EncodeZigZag(int64(h.Schema)))
+	b = protobuf.AppendDoubleField(b, 5, h.ZeroThreshold)
+	b = protobuf.AppendTag(b, 6, protobuf.VarintType)
+	b = protobuf.AppendVarint(b, h.ZeroCount)
+	b = appendSpans(b, 8, h.NegativeSpans)
+	b = protobuf.AppendPackedVarintsField(b, 9, zigZagDeltas(h.NegativeBuckets))
+	b = appendSpans(b, 11, h.PositiveSpans)
+	b = protobuf.AppendPackedVarintsField(b, 12, zigZagDeltas(h.PositiveBuckets))
+	// CounterResetHint values match the ResetHint enum.
+	b = protobuf.AppendVarintField(b, 14, uint64(h.ResetHint))
+	return protobuf.AppendVarintField(b, 15, uint64(timestamp))
+}
+
+// appendSpans appends a repeated BucketSpan field.
+func appendSpans(b []byte, num int, spans []otlptranslator.BucketSpan) []byte {
+	for _, s := range spans {
+		var span []byte
+		span = protobuf.AppendVarintField(span, 1, protobuf.EncodeZigZag(int64(s.Offset)))
+		span = protobuf.AppendVarintField(span, 2, uint64(s.Length))
+		b = protobuf.AppendMessageField(b, num, span)
+	}
+	return b
+}
+
+func zigZagDeltas(deltas []int64) []uint64 {
+	res := make([]uint64, len(deltas))
+	for i, d := range deltas {
+		res[i] = protobuf.EncodeZigZag(d)
+	}
+	return res
+}
+
+// inFamily reports whether the sample named name belongs to the family of
+// the given name and type: it must be named after the family, with one of the
+// suffixes of the samples of its type, or be its `_created` series, named
+// created.
+func inFamily(name, family, created string, typ otlptranslator.PrometheusType) bool {
+	suffix, ok := strings.CutPrefix(name, family)
+	if !ok {
+		return name == created && hasCreatedSeries(typ)
+	}
+	switch typ {
+	case otlptranslator.PrometheusTypeHistogram, otlptranslator.PrometheusTypeGaugeHistogram:
+		return suffix == "_bucket" || suffix == "_sum" || suffix == "_count" || name == created && hasCreatedSeries(typ)
+	case otlptranslator.PrometheusTypeSummary:
+		return suffix == "" || suffix == "_sum" || suffix == "_count" || name == created
+	default:
+		return suffix == "" || name == created && hasCreatedSeries(typ)
+	}
+}
+
+// hasCreatedSeries reports whether families of the given type have a
+// `_created` series.
+func hasCreatedSeries(typ otlptranslator.PrometheusType) bool {
+	switch typ {
+	case otlptranslator.PrometheusTypeCounter, otlptranslator.PrometheusTypeHistogram, otlptranslator.PrometheusTypeSummary:
+		return true
+	default:
+		return false
+	}
+}
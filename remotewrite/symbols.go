@@ -0,0 +1,53 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remotewrite
+
+import "github.com/prometheus/otlptranslator"
+
+// symbolTable interns the strings of a remote-write 2.0 request. The first
+// symbol is always the empty string, as required by the specification.
+type symbolTable struct {
+	symbols []string
+	refs    map[string]uint32
+}
+
+func newSymbolTable() symbolTable {
+	return symbolTable{
+		symbols: []string{""},
+		refs:    map[string]uint32{"": 0},
+	}
+}
+
+// ref returns the reference of s, adding it to the table if needed.
+func (t *symbolTable) ref(s string) uint32 {
+	if ref, ok := t.refs[s]; ok {
+		return ref
+	}
+	ref := uint32(len(t.symbols))
+	t.symbols = append(t.symbols, s)
+	t.refs[s] = ref
+	return ref
+}
+
+// labelRefs returns the references of the label names and values of a
+// series, including the metric name as the __name__ label, sorted by label
+// name.
+func (t *symbolTable) labelRefs(name string, ls otlptranslator.Labels) []uint32 {
+	ls = ls.Merge(otlptranslator.Labels{{Name: otlptranslator.MetricNameLabel, Value: name}})
+	refs := make([]uint32, 0, 2*len(ls))
+	for _, l := range ls {
+		refs = append(refs, t.ref(l.Name), t.ref(l.Value))
+	}
+	return refs
+}
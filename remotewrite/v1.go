@@ -0,0 +1,210 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remotewrite
+
+import (
+	"fmt"
+
+	"github.com/prometheus/otlptranslator"
+	"github.com/prometheus/otlptranslator/internal/protobuf"
+)
+
+// V1ContentType is the content type of remote-write 1.0 requests.
+const V1ContentType = "application/x-protobuf"
+
+// seriesV1 is a prometheus.TimeSeries being built.
+type seriesV1 struct {
+	labels     otlptranslator.Labels
+	samples    []otlptranslator.Sample
+	histograms []otlptranslator.HistogramSample
+	exemplars  []otlptranslator.SampleExemplar
+}
+
+// V1Encoder builds a remote-write 1.0 request, prometheus.WriteRequest, from
+// translated OTLP samples.
+//
+// Samples of the same series are grouped in a single time series, in the
+// order they were added, along with their exemplars and staleness markers.
+// Every metric gets a MetricMetadata entry, built by
+// otlptranslator.MetadataBuilder: it is named after MetricNamer.Build and
+// holds the metric type, its unit as translated by UnitNamer.Build, and its
+// description as help. Remote-write 1.0 has no created timestamps: `_created`
+// samples are kept as regular series, and can be left out by the
+// SampleBuilder.
+//
+// Example usage:
+//
+//	enc := remotewrite.NewV1Encoder(otlptranslator.NewMetricNamer("", otlptranslator.UnderscoreEscapingWithSuffixes))
+//	if err := enc.Add(metric, samples...); err != nil {
+//		// handle err
+//	}
+//	body := remotewrite.CompressSnappy(enc.Encode())
+type V1Encoder struct {
+	metadataBuilder otlptranslator.MetadataBuilder
+	series          map[string]*seriesV1
+	order           []*seriesV1
+	metadata        map[string]*otlptranslator.Metadata
+	families        []*otlptranslator.Metadata
+}
+
+// NewV1Encoder creates a V1Encoder naming metrics with the given MetricNamer.
+func NewV1Encoder(namer otlptranslator.MetricNamer) *V1Encoder {
+	return &V1Encoder{
+		metadataBuilder: newMetadataBuilder(namer),
+		series:          map[string]*seriesV1{},
+		metadata:        map[string]*otlptranslator.Metadata{},
+	}
+}
+
+// Add adds the float samples of a metric, as built by
+// otlptranslator.SampleBuilder, to the request. It returns an error if a
+// sample does not belong to the metric, or if its series already holds native
+// histograms.
+func (e *V1Encoder) Add(metric otlptranslator.Metric, samples ...otlptranslator.Sample) error {
+	name, err := e.addMetadata(metric)
+	if err != nil {
+		return err
+	}
+	created, err := e.metadataBuilder.MetricNamer.BuildCreated(metric)
+	if err != nil {
+		return err
+	}
+	typ := metric.Descriptor().PrometheusType()
+	for _, s := range samples {
+		if !inFamily(s.Name, name, created, typ) {
+			return fmt.Errorf("sample %q does not belong to metric %q", s.Name, name)
+		}
+		ts := e.timeSeries(s.Name, s.Labels)
+		if len(ts.histograms) > 0 {
+			return fmt.Errorf("series %s%s mixes float samples and native histograms", s.Name, s.Labels)
+		}
+		ts.samples = append(ts.samples, s)
+		ts.exemplars = append(ts.exemplars, s.Exemplars...)
+	}
+	return nil
+}
+
+// AddHistograms adds the native histogram samples of a metric, as built by
+// otlptranslator.SampleBuilder.BuildExponentialHistogram, to the request.
+func (e *V1Encoder) AddHistograms(metric otlptranslator.Metric, samples ...otlptranslator.HistogramSample) error {
+	name, err := e.addMetadata(metric)
+	if err != nil {
+		return err
+	}
+	for _, s := range samples {
+		if s.Name != name {
+			return fmt.Errorf("histogram sample %q does not belong to metric %q", s.Name, name)
+		}
+		ts := e.timeSeries(s.Name, s.Labels)
+		if len(ts.samples) > 0 {
+			return fmt.Errorf("series %s%s mixes float samples and native histograms", s.Name, s.Labels)
+		}
+		ts.histograms = append(ts.histograms, s)
+		ts.exemplars = append(ts.exemplars, s.Exemplars...)
+	}
+	return nil
+}
+
+// Len returns the number of time series in the request.
+func (e *V1Encoder) Len() int {
+	return len(e.order)
+}
+
+// Reset empties the request, so that the encoder can be reused.
+func (e *V1Encoder) Reset() {
+	clear(e.series)
+	e.order = e.order[:0]
+	clear(e.metadata)
+	e.families = e.families[:0]
+}
+
+// addMetadata records the metadata of a metric, and returns its name.
+func (e *V1Encoder) addMetadata(metric otlptranslator.Metric) (string, error) {
+	md, err := e.metadataBuilder.Build(metric)
+	if err != nil {
+		return "", err
+	}
+	name := md.FamilyName
+	if existing, ok := e.metadata[name]; ok {
+		if existing.Help == "" {
+			existing.Help = md.Help
+		}
+		return name, nil
+	}
+	e.metadata[name] = &md
+	e.families = append(e.families, &md)
+	return name, nil
+}
+
+// timeSeries returns the time series of the given name and labels, creating
+// it if needed.
+func (e *V1Encoder) timeSeries(name string, ls otlptranslator.Labels) *seriesV1 {
+	key := name + "\xff" + ls.String()
+	if ts, ok := e.series[key]; ok {
+		return ts
+	}
+	ts := &seriesV1{labels: ls.Merge(otlptranslator.Labels{{Name: otlptranslator.MetricNameLabel, Value: name}})}
+	e.series[key] = ts
+	e.order = append(e.order, ts)
+	return ts
+}
+
+// Encode returns the protobuf encoding of the request, uncompressed.
+func (e *V1Encoder) Encode() []byte {
+	var b []byte
+	for _, ts := range e.order {
+		b = protobuf.AppendMessageField(b, 1, ts.encode())
+	}
+	for _, md := range e.families {
+		var metadata []byte
+		metadata = protobuf.AppendVarintField(metadata, 1, uint64(metricTypeOf(md.Type)))
+		metadata = protobuf.AppendStringField(metadata, 2, md.FamilyName)
+		metadata = protobuf.AppendStringField(metadata, 4, md.Help)
+		metadata = protobuf.AppendStringField(metadata, 5, md.Unit)
+		b = protobuf.AppendMessageField(b, 3, metadata)
+	}
+	return b
+}
+
+// encode returns the protobuf encoding of a prometheus.TimeSeries.
+func (ts *seriesV1) encode() []byte {
+	b := appendLabels(nil, 1, ts.labels)
+	for _, s := range ts.samples {
+		var sample []byte
+		sample = protobuf.AppendDoubleField(sample, 1, s.Value)
+		sample = protobuf.AppendVarintField(sample, 2, uint64(s.Timestamp))
+		b = protobuf.AppendMessageField(b, 2, sample)
+	}
+	for _, ex := range ts.exemplars {
+		exemplar := appendLabels(nil, 1, ex.Labels)
+		exemplar = protobuf.AppendDoubleField(exemplar, 2, ex.Value)
+		exemplar = protobuf.AppendVarintField(exemplar, 3, uint64(ex.Timestamp))
+		b = protobuf.AppendMessageField(b, 3, exemplar)
+	}
+	for _, h := range ts.histograms {
+		b = protobuf.AppendMessageField(b, 4, encodeHistogram(h.Histogram, h.Timestamp))
+	}
+	return b
+}
+
+// appendLabels appends a repeated prometheus.Label field.
+func appendLabels(b []byte, num int, ls otlptranslator.Labels) []byte {
+	for _, l := range ls {
+		var label []byte
+		label = protobuf.AppendStringField(label, 1, l.Name)
+		label = protobuf.AppendStringField(label, 2, l.Value)
+		b = protobuf.AppendMessageField(b, num, label)
+	}
+	return b
+}
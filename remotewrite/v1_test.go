@@ -0,0 +1,181 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remotewrite
+
+import (
+	"bytes"
+	"encoding/hex"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prometheus/otlptranslator"
+	"github.com/prometheus/otlptranslator/internal/snappy"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+// checkGolden compares got with the content of the given file in testdata.
+func checkGolden(t *testing.T, file string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", file)
+	if *update {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s mismatch:\ngot  %x\nwant %x", file, got, want)
+	}
+}
+
+func TestV1Encoder(t *testing.T) {
+	namer := otlptranslator.NewMetricNamer("", otlptranslator.UnderscoreEscapingWithSuffixes)
+	builder := otlptranslator.SampleBuilder{MetricNamer: namer}
+	enc := NewV1Encoder(namer)
+
+	counter := otlptranslator.Metric{Name: "http.requests", Type: otlptranslator.MetricTypeMonotonicCounter, Description: "Number of requests."}
+	samples, err := builder.BuildNumber(counter, otlptranslator.Labels{{Name: "job", Value: "api"}}, otlptranslator.NumberDataPoint{
+		Attributes:        []otlptranslator.Attribute{{Key: "http.method", Value: "GET"}},
+		StartTimeUnixNano: 1_000_000_000,
+		TimeUnixNano:      2_000_000_000,
+		Value:             3,
+		Exemplars: []otlptranslator.Exemplar{{
+			TimeUnixNano: 1_500_000_000,
+			Value:        1,
+			SpanID:       [8]byte{7: 1},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := enc.Add(counter, samples...); err != nil {
+		t.Fatal(err)
+	}
+	stale, err := builder.BuildNumber(counter, otlptranslator.Labels{{Name: "job", Value: "api"}}, otlptranslator.NumberDataPoint{
+		Attributes:   []otlptranslator.Attribute{{Key: "http.method", Value: "GET"}},
+		TimeUnixNano: 3_000_000_000,
+		Flags:        otlptranslator.DataPointFlagNoRecordedValue,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := enc.Add(counter, stale...); err != nil {
+		t.Fatal(err)
+	}
+
+	histogram := otlptranslator.Metric{Name: "http.duration", Unit: "s", Type: otlptranslator.MetricTypeExponentialHistogram, Description: "Duration of requests."}
+	h, err := builder.BuildExponentialHistogram(histogram, nil, otlptranslator.ExponentialHistogramDataPoint{
+		TimeUnixNano: 2_000_000_000,
+		Count:        4,
+		Sum:          6,
+		Scale:        -1,
+		ZeroCount:    1,
+		Positive:     otlptranslator.ExponentialHistogramBuckets{BucketCounts: []uint64{2, 1}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := enc.AddHistograms(histogram, h); err != nil {
+		t.Fatal(err)
+	}
+	if enc.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", enc.Len())
+	}
+
+	body := enc.Encode()
+	checkGolden(t, "v1_request.pb", body)
+
+	compressed := CompressSnappy(body)
+	checkGolden(t, "v1_request.pb.snappy", compressed)
+	decompressed, err := snappy.Decode(compressed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(decompressed, body) {
+		t.Error("CompressSnappy() does not round trip")
+	}
+	checkGolden(t, "v1_request.pb.sz", CompressSnappyFramed(body))
+
+	enc.Reset()
+	if enc.Len() != 0 || len(enc.Encode()) != 0 {
+		t.Errorf("Reset() left %d series", enc.Len())
+	}
+}
+
+// TestV1Encoder_Wire checks the encoding against bytes written by hand from
+// the prometheus.WriteRequest definition, unlike the golden files, which are
+// written by the encoder.
+func TestV1Encoder_Wire(t *testing.T) {
+	namer := otlptranslator.NewMetricNamer("", otlptranslator.UnderscoreEscapingWithSuffixes)
+	enc := NewV1Encoder(namer)
+	gauge := otlptranslator.Metric{Name: "memory", Unit: "By", Type: otlptranslator.MetricTypeGauge, Description: "Memory."}
+	job := otlptranslator.Labels{{Name: "job", Value: "api"}}
+	if err := enc.Add(gauge, otlptranslator.Sample{Name: "memory_bytes", Labels: job, Value: 1, Timestamp: 1}); err != nil {
+		t.Fatal(err)
+	}
+	// timeseries (1): labels (1) {name (1) "__name__", value (2) "memory_bytes"},
+	// labels (1) {name (1) "job", value (2) "api"}, samples (2) {value (1) 1,
+	// timestamp (2) 1}.
+	want := "0a33" +
+		"0a18" + "0a085f5f6e616d655f5f" + "120c6d656d6f72795f6279746573" +
+		"0a0a" + "0a036a6f62" + "1203617069" +
+		"120b" + "09000000000000f03f" + "1001" +
+		// metadata (3): type (1) GAUGE, metric_family_name (2) "memory_bytes",
+		// help (4) "Memory.", unit (5) "bytes".
+		"1a20" + "0802" + "120c6d656d6f72795f6279746573" + "22074d656d6f72792e" + "2a056279746573"
+	if got := hex.EncodeToString(enc.Encode()); got != want {
+		t.Errorf("Encode() = %s, want %s", got, want)
+	}
+}
+
+func TestV1Encoder_Errors(t *testing.T) {
+	namer := otlptranslator.NewMetricNamer("", otlptranslator.UnderscoreEscapingWithSuffixes)
+	enc := NewV1Encoder(namer)
+	gauge := otlptranslator.Metric{Name: "up", Type: otlptranslator.MetricTypeGauge}
+
+	for _, name := range []string{"down", "upper", "up_created"} {
+		err := enc.Add(gauge, otlptranslator.Sample{Name: name})
+		if want := `sample "` + name + `" does not belong to metric "up"`; err == nil || err.Error() != want {
+			t.Errorf("Add(%q) error = %v, want %q", name, err, want)
+		}
+	}
+	counter := otlptranslator.Metric{Name: "requests", Type: otlptranslator.MetricTypeMonotonicCounter}
+	for _, name := range []string{"requests_totalx", "requests_bucket"} {
+		err := enc.Add(counter, otlptranslator.Sample{Name: name})
+		if want := `sample "` + name + `" does not belong to metric "requests_total"`; err == nil || err.Error() != want {
+			t.Errorf("Add(%q) error = %v, want %q", name, err, want)
+		}
+	}
+	if err := enc.Add(counter, otlptranslator.Sample{Name: "requests_total"}, otlptranslator.Sample{Name: "requests_created"}); err != nil {
+		t.Errorf("Add() error = %v", err)
+	}
+	histogram := otlptranslator.Metric{Name: "latency", Unit: "s", Type: otlptranslator.MetricTypeHistogram}
+	err := enc.Add(histogram, otlptranslator.Sample{Name: "latency_seconds_bucketing"})
+	if want := `sample "latency_seconds_bucketing" does not belong to metric "latency_seconds"`; err == nil || err.Error() != want {
+		t.Errorf("Add() error = %v, want %q", err, want)
+	}
+	if err := enc.AddHistograms(gauge, otlptranslator.HistogramSample{Name: "up"}); err != nil {
+		t.Fatal(err)
+	}
+	err = enc.Add(gauge, otlptranslator.Sample{Name: "up"})
+	if err == nil || err.Error() != `series up{} mixes float samples and native histograms` {
+		t.Errorf("Add() error = %v", err)
+	}
+}
@@ -0,0 +1,223 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remotewrite
+
+import (
+	"fmt"
+
+	"github.com/prometheus/otlptranslator"
+	"github.com/prometheus/otlptranslator/internal/protobuf"
+)
+
+// V2ContentType is the content type of remote-write 2.0 requests.
+const V2ContentType = "application/x-protobuf;proto=io.prometheus.write.v2.Request"
+
+// seriesV2 is an io.prometheus.write.v2.TimeSeries being built.
+type seriesV2 struct {
+	labelRefs        []uint32
+	samples          []otlptranslator.Sample
+	histograms       []otlptranslator.HistogramSample
+	exemplars        []exemplarV2
+	typ              metricType
+	helpRef          uint32
+	unitRef          uint32
+	createdTimestamp int64
+}
+
+type exemplarV2 struct {
+	labelRefs []uint32
+	value     float64
+	timestamp int64
+}
+
+// V2Encoder builds a remote-write 2.0 request, io.prometheus.write.v2.Request,
+// from translated OTLP samples.
+//
+// Metric and label names, label values, help texts and units are interned in
+// the symbol table of the request. Samples of the same series are grouped in
+// a single time series, in the order they were added, carrying the metadata
+// of their metric as built by otlptranslator.MetadataBuilder: its type, its
+// unit as translated by UnitNamer.Build, and its description as help.
+// Exemplars and created timestamps of the samples are kept, as are staleness
+// markers. As created timestamps are natively supported, `_created` samples
+// are skipped.
+//
+// Example usage:
+//
+//	enc := remotewrite.NewV2Encoder(otlptranslator.NewMetricNamer("", otlptranslator.UnderscoreEscapingWithSuffixes))
+//	if err := enc.Add(metric, samples...); err != nil {
+//		// handle err
+//	}
+//	body := enc.Encode()
+type V2Encoder struct {
+	namer    otlptranslator.MetricNamer
+	metadata otlptranslator.MetadataBuilder
+	symbols  symbolTable
+	series   map[string]*seriesV2
+	order    []*seriesV2
+}
+
+// NewV2Encoder creates a V2Encoder naming metrics with the given MetricNamer.
+func NewV2Encoder(namer otlptranslator.MetricNamer) *V2Encoder {
+	return &V2Encoder{
+		namer:    namer,
+		metadata: newMetadataBuilder(namer),
+		symbols:  newSymbolTable(),
+		series:   map[string]*seriesV2{},
+	}
+}
+
+// Add adds the float samples of a metric, as built by
+// otlptranslator.SampleBuilder, to the request. It returns an error if a
+// sample does not belong to the metric, or if its series already holds native
+// histograms.
+func (e *V2Encoder) Add(metric otlptranslator.Metric, samples ...otlptranslator.Sample) error {
+	md, err := e.metadata.Build(metric)
+	if err != nil {
+		return err
+	}
+	name := md.FamilyName
+	created, err := e.namer.BuildCreated(metric)
+	if err != nil {
+		return err
+	}
+	for _, s := range samples {
+		if s.Name == created {
+			continue
+		}
+		if !inFamily(s.Name, name, created, md.Type) {
+			return fmt.Errorf("sample %q does not belong to metric %q", s.Name, name)
+		}
+		ts := e.timeSeries(md, s.Name, s.Labels)
+		if len(ts.histograms) > 0 {
+			return fmt.Errorf("series %s%s mixes float samples and native histograms", s.Name, s.Labels)
+		}
+		ts.samples = append(ts.samples, s)
+		ts.addExemplars(&e.symbols, s.Exemplars)
+		ts.createdTimestamp = max(ts.createdTimestamp, s.CreatedTimestamp)
+	}
+	return nil
+}
+
+// AddHistograms adds the native histogram samples of a metric, as built by
+// otlptranslator.SampleBuilder.BuildExponentialHistogram, to the request.
+func (e *V2Encoder) AddHistograms(metric otlptranslator.Metric, samples ...otlptranslator.HistogramSample) error {
+	md, err := e.metadata.Build(metric)
+	if err != nil {
+		return err
+	}
+	name := md.FamilyName
+	for _, s := range samples {
+		if s.Name != name {
+			return fmt.Errorf("histogram sample %q does not belong to metric %q", s.Name, name)
+		}
+		ts := e.timeSeries(md, s.Name, s.Labels)
+		if len(ts.samples) > 0 {
+			return fmt.Errorf("series %s%s mixes float samples and native histograms", s.Name, s.Labels)
+		}
+		ts.histograms = append(ts.histograms, s)
+		ts.addExemplars(&e.symbols, s.Exemplars)
+		ts.createdTimestamp = max(ts.createdTimestamp, s.CreatedTimestamp)
+	}
+	return nil
+}
+
+// Len returns the number of time series in the request.
+func (e *V2Encoder) Len() int {
+	return len(e.order)
+}
+
+// Reset empties the request, so that the encoder can be reused.
+func (e *V2Encoder) Reset() {
+	e.symbols = newSymbolTable()
+	clear(e.series)
+	e.order = e.order[:0]
+}
+
+// timeSeries returns the time series of the given name and labels, creating
+// it if needed.
+func (e *V2Encoder) timeSeries(md otlptranslator.Metadata, name string, ls otlptranslator.Labels) *seriesV2 {
+	key := name + "\xff" + ls.String()
+	if ts, ok := e.series[key]; ok {
+		return ts
+	}
+	ts := &seriesV2{
+		labelRefs: e.symbols.labelRefs(name, ls),
+		typ:       metricTypeOf(md.Type),
+		helpRef:   e.symbols.ref(md.Help),
+		unitRef:   e.symbols.ref(md.Unit),
+	}
+	e.series[key] = ts
+	e.order = append(e.order, ts)
+	return ts
+}
+
+func (ts *seriesV2) addExemplars(symbols *symbolTable, exemplars []otlptranslator.SampleExemplar) {
+	for _, ex := range exemplars {
+		refs := make([]uint32, 0, 2*len(ex.Labels))
+		for _, l := range ex.Labels {
+			refs = append(refs, symbols.ref(l.Name), symbols.ref(l.Value))
+		}
+		ts.exemplars = append(ts.exemplars, exemplarV2{labelRefs: refs, value: ex.Value, timestamp: ex.Timestamp})
+	}
+}
+
+// Encode returns the protobuf encoding of the request, uncompressed.
+func (e *V2Encoder) Encode() []byte {
+	var b []byte
+	for _, s := range e.symbols.symbols {
+		// Symbols are repeated, so the first, empty, one must be written too.
+		b = protobuf.AppendTag(b, 4, protobuf.BytesType)
+		b = protobuf.AppendString(b, s)
+	}
+	for _, ts := range e.order {
+		b = protobuf.AppendMessageField(b, 5, ts.encode())
+	}
+	return b
+}
+
+// encode returns the protobuf encoding of an io.prometheus.write.v2.TimeSeries.
+func (ts *seriesV2) encode() []byte {
+	b := protobuf.AppendPackedVarintsField(nil, 1, refsToUint64(ts.labelRefs))
+	for _, s := range ts.samples {
+		var sample []byte
+		sample = protobuf.AppendDoubleField(sample, 1, s.Value)
+		sample = protobuf.AppendVarintField(sample, 2, uint64(s.Timestamp))
+		b = protobuf.AppendMessageField(b, 2, sample)
+	}
+	for _, h := range ts.histograms {
+		b = protobuf.AppendMessageField(b, 3, encodeHistogram(h.Histogram, h.Timestamp))
+	}
+	for _, ex := range ts.exemplars {
+		var exemplar []byte
+		exemplar = protobuf.AppendPackedVarintsField(exemplar, 1, refsToUint64(ex.labelRefs))
+		exemplar = protobuf.AppendDoubleField(exemplar, 2, ex.value)
+		exemplar = protobuf.AppendVarintField(exemplar, 3, uint64(ex.timestamp))
+		b = protobuf.AppendMessageField(b, 4, exemplar)
+	}
+	var metadata []byte
+	metadata = protobuf.AppendVarintField(metadata, 1, uint64(ts.typ))
+	metadata = protobuf.AppendVarintField(metadata, 3, uint64(ts.helpRef))
+	metadata = protobuf.AppendVarintField(metadata, 4, uint64(ts.unitRef))
+	b = protobuf.AppendMessageField(b, 5, metadata)
+	return protobuf.AppendVarintField(b, 6, uint64(ts.createdTimestamp))
+}
+
+func refsToUint64(refs []uint32) []uint64 {
+	res := make([]uint64, len(refs))
+	for i, r := range refs {
+		res[i] = uint64(r)
+	}
+	return res
+}
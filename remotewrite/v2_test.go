@@ -0,0 +1,324 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remotewrite
+
+import (
+	"encoding/hex"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/prometheus/otlptranslator"
+	"github.com/prometheus/otlptranslator/internal/protobuf"
+)
+
+// decodedSeriesV2 is a decoded io.prometheus.write.v2.TimeSeries, with
+// symbols resolved.
+type decodedSeriesV2 struct {
+	Labels           []string
+	Samples          [][2]float64
+	Histograms       []map[int][]uint64
+	Exemplars        []decodedExemplarV2
+	Metadata         [3]string // Type, help, unit.
+	CreatedTimestamp int64
+}
+
+type decodedExemplarV2 struct {
+	Labels    []string
+	Value     float64
+	Timestamp int64
+}
+
+// decodeV2 decodes a remote-write 2.0 request. Histograms are decoded as the
+// raw values of their fields.
+func decodeV2(t *testing.T, b []byte) []decodedSeriesV2 {
+	t.Helper()
+	var (
+		symbols []string
+		raw     [][]byte
+	)
+	check := func(err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	check(protobuf.ForEachField(b, func(f protobuf.Field) error {
+		switch f.Num {
+		case 4:
+			symbols = append(symbols, string(f.Bytes))
+		case 5:
+			raw = append(raw, f.Bytes)
+		}
+		return nil
+	}))
+	if len(symbols) == 0 || symbols[0] != "" {
+		t.Fatalf("first symbol must be empty, got %q", symbols)
+	}
+	resolve := func(refs []uint64) []string {
+		res := make([]string, len(refs))
+		for i, r := range refs {
+			res[i] = symbols[r]
+		}
+		return res
+	}
+
+	var res []decodedSeriesV2
+	for _, ts := range raw {
+		var s decodedSeriesV2
+		check(protobuf.ForEachField(ts, func(f protobuf.Field) error {
+			switch f.Num {
+			case 1:
+				refs, err := protobuf.AppendVarints(nil, f)
+				s.Labels = resolve(refs)
+				return err
+			case 2:
+				var sample [2]float64
+				err := protobuf.ForEachField(f.Bytes, func(f protobuf.Field) error {
+					if f.Num == 1 {
+						sample[0] = f.Double()
+					} else {
+						sample[1] = float64(int64(f.Varint))
+					}
+					return nil
+				})
+				s.Samples = append(s.Samples, sample)
+				return err
+			case 3:
+				h := map[int][]uint64{}
+				err := protobuf.ForEachField(f.Bytes, func(f protobuf.Field) error {
+					switch f.Type {
+					case protobuf.VarintType:
+						h[f.Num] = append(h[f.Num], f.Varint)
+					case protobuf.Fixed64Type:
+						h[f.Num] = append(h[f.Num], f.Fixed)
+					default:
+						h[f.Num] = append(h[f.Num], uint64(len(h[f.Num])))
+						var err error
+						if f.Num == 9 || f.Num == 12 {
+							h[f.Num], err = protobuf.AppendVarints(nil, f)
+						} else {
+							// Spans: offset and length.
+							err = protobuf.ForEachField(f.Bytes, func(sf protobuf.Field) error {
+								h[100*f.Num+sf.Num] = append(h[100*f.Num+sf.Num], sf.Varint)
+								return nil
+							})
+						}
+						return err
+					}
+					return nil
+				})
+				s.Histograms = append(s.Histograms, h)
+				return err
+			case 4:
+				var e decodedExemplarV2
+				err := protobuf.ForEachField(f.Bytes, func(f protobuf.Field) error {
+					switch f.Num {
+					case 1:
+						refs, err := protobuf.AppendVarints(nil, f)
+						e.Labels = resolve(refs)
+						return err
+					case 2:
+						e.Value = f.Double()
+					case 3:
+						e.Timestamp = int64(f.Varint)
+					}
+					return nil
+				})
+				s.Exemplars = append(s.Exemplars, e)
+				return err
+			case 5:
+				s.Metadata[0] = "0"
+				return protobuf.ForEachField(f.Bytes, func(f protobuf.Field) error {
+					switch f.Num {
+					case 1:
+						s.Metadata[0] = string(rune('0' + f.Varint))
+					case 3:
+						s.Metadata[1] = symbols[f.Varint]
+					case 4:
+						s.Metadata[2] = symbols[f.Varint]
+					}
+					return nil
+				})
+			case 6:
+				s.CreatedTimestamp = int64(f.Varint)
+			}
+			return nil
+		}))
+		res = append(res, s)
+	}
+	return res
+}
+
+func TestV2Encoder(t *testing.T) {
+	namer := otlptranslator.NewMetricNamer("", otlptranslator.UnderscoreEscapingWithSuffixes)
+	builder := otlptranslator.SampleBuilder{
+		MetricNamer:   namer,
+		CreatedSeries: true,
+	}
+	enc := NewV2Encoder(namer)
+
+	counter := otlptranslator.Metric{Name: "http.requests", Type: otlptranslator.MetricTypeMonotonicCounter, Description: "Number of requests."}
+	for i, v := range []float64{3, 5} {
+		samples, err := builder.BuildNumber(counter, otlptranslator.Labels{{Name: "job", Value: "api"}}, otlptranslator.NumberDataPoint{
+			Attributes:        []otlptranslator.Attribute{{Key: "http.method", Value: "GET"}},
+			StartTimeUnixNano: 1_000_000_000,
+			TimeUnixNano:      uint64(2+i) * 1_000_000_000,
+			Value:             v,
+			Exemplars: []otlptranslator.Exemplar{{
+				TimeUnixNano: 1_500_000_000,
+				Value:        1,
+				TraceID:      [16]byte{15: 1},
+			}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := enc.Add(counter, samples...); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	histogram := otlptranslator.Metric{Name: "http.duration", Unit: "s", Type: otlptranslator.MetricTypeExponentialHistogram}
+	h, err := builder.BuildExponentialHistogram(histogram, nil, otlptranslator.ExponentialHistogramDataPoint{
+		StartTimeUnixNano: 1_000_000_000,
+		TimeUnixNano:      2_000_000_000,
+		Count:             4,
+		Sum:               6,
+		Scale:             -1,
+		ZeroCount:         1,
+		Positive:          otlptranslator.ExponentialHistogramBuckets{Offset: 0, BucketCounts: []uint64{2, 1}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := enc.AddHistograms(histogram, h); err != nil {
+		t.Fatal(err)
+	}
+
+	stale, err := builder.BuildNumber(counter, otlptranslator.Labels{{Name: "job", Value: "api"}}, otlptranslator.NumberDataPoint{
+		Attributes:   []otlptranslator.Attribute{{Key: "http.method", Value: "POST"}},
+		TimeUnixNano: 4_000_000_000,
+		Flags:        otlptranslator.DataPointFlagNoRecordedValue,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := enc.Add(counter, stale...); err != nil {
+		t.Fatal(err)
+	}
+	if enc.Len() != 3 {
+		t.Errorf("Len() = %d, want 3", enc.Len())
+	}
+
+	got := decodeV2(t, enc.Encode())
+	want := []decodedSeriesV2{
+		{
+			Labels:  []string{"__name__", "http_requests_total", "http_method", "GET", "job", "api"},
+			Samples: [][2]float64{{3, 2000}, {5, 3000}},
+			Exemplars: []decodedExemplarV2{
+				{Labels: []string{"trace_id", "00000000000000000000000000000001"}, Value: 1, Timestamp: 1500},
+				{Labels: []string{"trace_id", "00000000000000000000000000000001"}, Value: 1, Timestamp: 1500},
+			},
+			Metadata:         [3]string{"1", "Number of requests.", ""},
+			CreatedTimestamp: 1000,
+		},
+		{
+			Labels: []string{"__name__", "http_duration_seconds"},
+			Histograms: []map[int][]uint64{{
+				1:    {4},
+				3:    {math.Float64bits(6)},
+				4:    {protobuf.EncodeZigZag(-1)},
+				6:    {1},
+				11:   {0},
+				1101: {protobuf.EncodeZigZag(1)},
+				1102: {2},
+				12:   {protobuf.EncodeZigZag(2), protobuf.EncodeZigZag(-1)},
+				15:   {2000},
+			}},
+			Metadata:         [3]string{"3", "", "seconds"},
+			CreatedTimestamp: 1000,
+		},
+		{
+			Labels:   []string{"__name__", "http_requests_total", "http_method", "POST", "job", "api"},
+			Samples:  [][2]float64{{math.Float64frombits(otlptranslator.StaleNaN), 4000}},
+			Metadata: [3]string{"1", "Number of requests.", ""},
+		},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("decoded %d series, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if len(want[i].Samples) == 1 && otlptranslator.IsStaleNaN(want[i].Samples[0][0]) {
+			if !otlptranslator.IsStaleNaN(got[i].Samples[0][0]) {
+				t.Errorf("series %d: want a staleness marker, got %v", i, got[i].Samples)
+			}
+			got[i].Samples, want[i].Samples = nil, nil
+		}
+		if !reflect.DeepEqual(got[i], want[i]) {
+			t.Errorf("series %d:\ngot  %+v\nwant %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestV2Encoder_Golden(t *testing.T) {
+	namer := otlptranslator.NewMetricNamer("", otlptranslator.UnderscoreEscapingWithSuffixes)
+	enc := NewV2Encoder(namer)
+	gauge := otlptranslator.Metric{Name: "up", Type: otlptranslator.MetricTypeGauge}
+	if err := enc.Add(gauge, otlptranslator.Sample{Name: "up", Value: 1, Timestamp: 1}); err != nil {
+		t.Fatal(err)
+	}
+	// symbols: "", "__name__", "up"; timeseries: labels_refs [1 2],
+	// sample {value 1, timestamp 1}, metadata {type GAUGE}.
+	want := "2200" + "22085f5f6e616d655f5f" + "22027570" +
+		"2a15" + "0a020102" + "120b09000000000000f03f1001" + "2a020802"
+	if got := hex.EncodeToString(enc.Encode()); got != want {
+		t.Errorf("Encode() = %s, want %s", got, want)
+	}
+
+	enc.Reset()
+	if enc.Len() != 0 || hex.EncodeToString(enc.Encode()) != "2200" {
+		t.Errorf("Reset() left %d series", enc.Len())
+	}
+}
+
+func TestV2Encoder_Errors(t *testing.T) {
+	namer := otlptranslator.NewMetricNamer("", otlptranslator.UnderscoreEscapingWithSuffixes)
+	enc := NewV2Encoder(namer)
+	gauge := otlptranslator.Metric{Name: "up", Type: otlptranslator.MetricTypeGauge}
+
+	for _, name := range []string{"down", "upper"} {
+		err := enc.Add(gauge, otlptranslator.Sample{Name: name})
+		if want := `sample "` + name + `" does not belong to metric "up"`; err == nil || err.Error() != want {
+			t.Errorf("Add(%q) error = %v, want %q", name, err, want)
+		}
+	}
+	counter := otlptranslator.Metric{Name: "requests", Type: otlptranslator.MetricTypeMonotonicCounter}
+	err := enc.Add(counter, otlptranslator.Sample{Name: "requests_totalx"})
+	if want := `sample "requests_totalx" does not belong to metric "requests_total"`; err == nil || err.Error() != want {
+		t.Errorf("Add() error = %v, want %q", err, want)
+	}
+	histogram := otlptranslator.Metric{Name: "latency", Unit: "s", Type: otlptranslator.MetricTypeHistogram}
+	err = enc.Add(histogram, otlptranslator.Sample{Name: "latency_seconds_bucketing"})
+	if want := `sample "latency_seconds_bucketing" does not belong to metric "latency_seconds"`; err == nil || err.Error() != want {
+		t.Errorf("Add() error = %v, want %q", err, want)
+	}
+	if err := enc.Add(gauge, otlptranslator.Sample{Name: "up"}); err != nil {
+		t.Fatal(err)
+	}
+	err = enc.AddHistograms(gauge, otlptranslator.HistogramSample{Name: "up"})
+	if err == nil || err.Error() != `series up{} mixes float samples and native histograms` {
+		t.Errorf("AddHistograms() error = %v", err)
+	}
+}
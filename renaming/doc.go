@@ -0,0 +1,41 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package renaming applies declarative rules renaming, changing the unit of,
+// or dropping OpenTelemetry metrics, either before their names are built by
+// a MetricNamer, or as overrides of the translated names. It replaces the
+// transform processors otherwise maintained in front of Prometheus only to
+// fix badly named metrics.
+//
+// Rules are loaded from YAML:
+//
+//	mode: first # or all
+//	rules:
+//	  - match:
+//	      name: vendor\.(.+)\.millis
+//	      type: gauge|histogram
+//	    rename: acme.$1.duration
+//	    unit: ms
+//	  - match:
+//	      name: vendor\.debug\..*
+//	    drop: true
+//	  - stage: after
+//	    match:
+//	      name: acme_queue_size
+//	    rename: acme_queue_length
+//
+// Main components:
+//   - Rules: Compiled rules, applied to metrics and translated names
+//   - Config: The rules and their matching mode, validated by New
+//   - Load: Loads and validates rules from YAML
+package renaming
@@ -0,0 +1,181 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package renaming
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/prometheus/otlptranslator/internal/yaml"
+)
+
+// Load loads and validates rules from YAML, as described in the package
+// documentation. Unknown fields are errors.
+func Load(content []byte) (*Rules, error) {
+	root, err := yaml.Parse(content)
+	if err != nil {
+		return nil, err
+	}
+	l := &loader{content: content}
+	cfg, lines, err := l.config(root)
+	if err != nil {
+		return nil, err
+	}
+	return newRules(cfg, lines)
+}
+
+// LoadFile loads and validates rules from a YAML file.
+func LoadFile(name string) (*Rules, error) {
+	content, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	rules, err := Load(content)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return rules, nil
+}
+
+// loader decodes a Config from YAML nodes.
+type loader struct {
+	content []byte
+}
+
+func (l *loader) errorf(n *yaml.Node, format string, args ...any) error {
+	line := bytes.Count(l.content[:n.Start], []byte("\n")) + 1
+	return fmt.Errorf("line %d: %s", line, fmt.Sprintf(format, args...))
+}
+
+// config decodes the configuration, and returns the line of every rule.
+func (l *loader) config(root *yaml.Node) (Config, []int, error) {
+	var cfg Config
+	if root.Kind == yaml.NullNode {
+		return cfg, nil, nil
+	}
+	var lines []int
+	err := l.fields(root, map[string]func(*yaml.Node) error{
+		"mode": func(n *yaml.Node) error {
+			s, err := l.scalar(n, "mode")
+			cfg.Mode = Mode(s)
+			return err
+		},
+		"rules": func(n *yaml.Node) error {
+			if n.Kind == yaml.NullNode {
+				return nil
+			}
+			if n.Kind != yaml.SequenceNode {
+				return l.errorf(n, "rules must be a sequence")
+			}
+			for _, item := range n.Items {
+				rl, err := l.rule(item)
+				if err != nil {
+					return err
+				}
+				cfg.Rules = append(cfg.Rules, rl)
+				lines = append(lines, bytes.Count(l.content[:item.Start], []byte("\n"))+1)
+			}
+			return nil
+		},
+	})
+	return cfg, lines, err
+}
+
+func (l *loader) rule(n *yaml.Node) (Rule, error) {
+	var rl Rule
+	err := l.fields(n, map[string]func(*yaml.Node) error{
+		"stage": func(n *yaml.Node) error {
+			s, err := l.scalar(n, "stage")
+			rl.Stage = Stage(s)
+			return err
+		},
+		"match": func(n *yaml.Node) error {
+			return l.fields(n, map[string]func(*yaml.Node) error{
+				"name": func(n *yaml.Node) (err error) {
+					rl.Match.Name, err = l.scalar(n, "name")
+					return err
+				},
+				"unit": func(n *yaml.Node) (err error) {
+					rl.Match.Unit, err = l.scalar(n, "unit")
+					return err
+				},
+				"type": func(n *yaml.Node) (err error) {
+					rl.Match.Type, err = l.scalar(n, "type")
+					return err
+				},
+			})
+		},
+		"rename": func(n *yaml.Node) (err error) {
+			rl.Rename, err = l.scalar(n, "rename")
+			return err
+		},
+		"unit": func(n *yaml.Node) error {
+			unit, err := l.scalar(n, "unit")
+			rl.Unit = &unit
+			return err
+		},
+		"drop": func(n *yaml.Node) error {
+			s, err := l.scalar(n, "drop")
+			switch {
+			case err != nil:
+				return err
+			case s == "true":
+				rl.Drop = true
+			case s != "false":
+				return l.errorf(n, "drop must be true or false")
+			}
+			return nil
+		},
+	})
+	return rl, err
+}
+
+// fields decodes the fields of a mapping.
+func (l *loader) fields(n *yaml.Node, decoders map[string]func(*yaml.Node) error) error {
+	switch n.Kind {
+	case yaml.MappingNode:
+	case yaml.OpaqueNode:
+		return l.errorf(n, "flow collections, anchors, aliases and tags are not supported")
+	default:
+		return l.errorf(n, "expected a mapping")
+	}
+	seen := make(map[string]bool, len(n.Keys))
+	for i, k := range n.Keys {
+		decode, ok := decoders[k.Value]
+		switch {
+		case k.Kind != yaml.ScalarNode || !ok:
+			return l.errorf(k, "unknown field %q", k.Value)
+		case seen[k.Value]:
+			return l.errorf(k, "duplicate field %q", k.Value)
+		}
+		seen[k.Value] = true
+		if err := decode(n.Values[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// scalar decodes a scalar field. Null values are empty strings.
+func (l *loader) scalar(n *yaml.Node, field string) (string, error) {
+	switch n.Kind {
+	case yaml.ScalarNode:
+		return n.Value, nil
+	case yaml.NullNode:
+		return "", nil
+	default:
+		return "", l.errorf(n, "%s must be a string", field)
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package renaming
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/otlptranslator"
+)
+
+func TestLoad(t *testing.T) {
+	r, err := Load([]byte(`mode: all
+rules:
+  - match:
+      name: vendor\.(.+)\.millis
+      type: gauge|histogram
+    rename: acme.$1.duration
+    unit: ms
+  - match:
+      name: 'acme\..*'
+      unit: ms
+    unit: ""
+  - match:
+      name: vendor\.debug\..*
+    drop: true
+  - stage: after
+    match:
+      name: acme_queue_size
+    rename: acme_queue_length
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.mode != AllMatch || len(r.before) != 3 || len(r.after) != 1 {
+		t.Fatalf("Load() = %+v", r)
+	}
+	got, ok := r.Apply(otlptranslator.Metric{Name: "vendor.query.millis", Type: otlptranslator.MetricTypeGauge})
+	if wantMetric := (otlptranslator.Metric{Name: "acme.query.duration", Type: otlptranslator.MetricTypeGauge}); !ok || got != wantMetric {
+		t.Errorf("Apply() = %+v, %t, want %+v", got, ok, wantMetric)
+	}
+	if _, ok := r.Apply(otlptranslator.Metric{Name: "vendor.debug.x"}); ok {
+		t.Error("Apply() did not drop the metric")
+	}
+	if got, _ := r.Override(otlptranslator.Metric{}, "acme_queue_size"); got != "acme_queue_length" {
+		t.Errorf("Override() = %q, want %q", got, "acme_queue_length")
+	}
+
+	if r, err := Load([]byte("# No rules.\n")); err != nil || len(r.before)+len(r.after) != 0 || r.mode != FirstMatch {
+		t.Errorf("Load() of an empty file = %+v, %v", r, err)
+	}
+}
+
+func TestLoad_Errors(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		in      string
+		wantErr string
+	}{
+		{name: "syntax", in: "rules: 'a\n", wantErr: "line 1: "},
+		{name: "root", in: "- a\n", wantErr: "line 1: expected a mapping"},
+		{name: "unknown field", in: "rules:\n  - match:\n      labels: a\n    drop: true\n", wantErr: `line 3: unknown field "labels"`},
+		{name: "duplicate field", in: "mode: all\nmode: first\n", wantErr: `line 2: duplicate field "mode"`},
+		{name: "rules", in: "rules: a\n", wantErr: "line 1: rules must be a sequence"},
+		{name: "flow", in: "rules:\n  - {drop: true}\n", wantErr: "line 2: flow collections, anchors, aliases and tags are not supported"},
+		{name: "drop", in: "rules:\n  - drop: yes\n", wantErr: "line 2: drop must be true or false"},
+		{name: "scalar", in: "rules:\n  - rename:\n      a: b\n", wantErr: "line 3: rename must be a string"},
+		{name: "validation", in: "rules:\n  - drop: true\n  - match:\n      name: (\n    drop: true\n", wantErr: "line 3: rule 2: invalid name regular expression"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Load([]byte(tc.in))
+			if err == nil || !strings.HasPrefix(err.Error(), tc.wantErr) {
+				t.Errorf("Load() error = %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
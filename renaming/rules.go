@@ -0,0 +1,283 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package renaming
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"unicode/utf8"
+
+	"github.com/prometheus/otlptranslator"
+	"github.com/prometheus/otlptranslator/internal/legacy"
+)
+
+// Mode selects which of the rules matching a metric are applied.
+type Mode string
+
+const (
+	// FirstMatch applies only the first matching rule of each stage.
+	FirstMatch Mode = "first"
+	// AllMatch applies every matching rule, in order, each one matching the
+	// metric as changed by the previous ones.
+	AllMatch Mode = "all"
+)
+
+// Stage selects when a rule is applied.
+type Stage string
+
+const (
+	// BeforeTranslation rules match and change OpenTelemetry metrics,
+	// before their names are built.
+	BeforeTranslation Stage = "before"
+	// AfterTranslation rules match translated names, and override them.
+	AfterTranslation Stage = "after"
+)
+
+// Config is the configuration of Rules.
+type Config struct {
+	// Mode defaults to FirstMatch.
+	Mode  Mode
+	Rules []Rule
+}
+
+// Rule matches metrics and renames them, changes their unit, or drops them.
+type Rule struct {
+	// Stage defaults to BeforeTranslation.
+	Stage Stage
+	Match Match
+	// Rename is the new name of the metric. It may reference the capturing
+	// groups of the Match.Name regular expression as in regexp.Expand, such
+	// as $1 or ${name}, and $0 for the whole name.
+	Rename string
+	// Unit is the new unit of the metric, if not nil. It is only supported
+	// before translation.
+	Unit *string
+	// Drop drops the metric. It cannot be combined with other actions.
+	Drop bool
+}
+
+// Match holds the regular expressions a metric must fully match, as in
+// Prometheus relabeling. An empty expression matches everything.
+type Match struct {
+	// Name is matched against the OpenTelemetry name of the metric before
+	// translation, and against the translated name after translation.
+	Name string
+	// Unit is matched against the OpenTelemetry unit of the metric. Use ^$
+	// to match metrics without a unit.
+	Unit string
+	// Type is matched against the name of the metric type, as returned by
+	// otlptranslator.MetricType.String: gauge, counter, updowncounter,
+	// histogram, exponential_histogram, summary or unknown.
+	Type string
+}
+
+// Rules are compiled renaming rules. They are safe for concurrent use, and
+// the zero value applies no rules.
+type Rules struct {
+	mode          Mode
+	before, after []rule
+}
+
+// rule is a compiled Rule.
+type rule struct {
+	name, unit, typ *regexp.Regexp
+	rename          string
+	setUnit         *string
+	drop            bool
+}
+
+// New validates and compiles the rules of cfg.
+func New(cfg Config) (*Rules, error) {
+	return newRules(cfg, nil)
+}
+
+// newRules compiles the rules of cfg. Errors are prefixed with the line of
+// the rule, if lines are given.
+func newRules(cfg Config, lines []int) (*Rules, error) {
+	r := &Rules{mode: cfg.Mode}
+	switch cfg.Mode {
+	case "":
+		r.mode = FirstMatch
+	case FirstMatch, AllMatch:
+	default:
+		return nil, fmt.Errorf("unknown mode %q, want %q or %q", cfg.Mode, FirstMatch, AllMatch)
+	}
+	for i, rl := range cfg.Rules {
+		compiled, err := compileRule(rl)
+		if err != nil {
+			if lines != nil {
+				return nil, fmt.Errorf("line %d: rule %d: %w", lines[i], i+1, err)
+			}
+			return nil, fmt.Errorf("rule %d: %w", i+1, err)
+		}
+		if rl.Stage == AfterTranslation {
+			r.after = append(r.after, compiled)
+		} else {
+			r.before = append(r.before, compiled)
+		}
+	}
+	return r, nil
+}
+
+func compileRule(rl Rule) (rule, error) {
+	switch rl.Stage {
+	case "", BeforeTranslation:
+	case AfterTranslation:
+		if rl.Unit != nil {
+			return rule{}, errors.New("units cannot be changed after translation")
+		}
+	default:
+		return rule{}, fmt.Errorf("unknown stage %q, want %q or %q", rl.Stage, BeforeTranslation, AfterTranslation)
+	}
+	switch {
+	case rl.Drop && (rl.Rename != "" || rl.Unit != nil):
+		return rule{}, errors.New("drop cannot be combined with other actions")
+	case !rl.Drop && rl.Rename == "" && rl.Unit == nil:
+		return rule{}, errors.New("no action: want rename, unit or drop")
+	}
+
+	compiled := rule{rename: rl.Rename, setUnit: rl.Unit, drop: rl.Drop}
+	var err error
+	if compiled.name, err = compileMatch("name", rl.Match.Name); err != nil {
+		return rule{}, err
+	}
+	if compiled.unit, err = compileMatch("unit", rl.Match.Unit); err != nil {
+		return rule{}, err
+	}
+	if compiled.typ, err = compileMatch("type", rl.Match.Type); err != nil {
+		return rule{}, err
+	}
+	if !matchesAnyType(compiled.typ) {
+		return rule{}, fmt.Errorf("type %q matches no metric type", rl.Match.Type)
+	}
+	return compiled, nil
+}
+
+// compileMatch compiles a fully anchored regular expression.
+func compileMatch(field, expr string) (*regexp.Regexp, error) {
+	if expr == "" {
+		expr = ".*"
+	}
+	re, err := regexp.Compile("^(?:" + expr + ")$")
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s regular expression: %w", field, err)
+	}
+	return re, nil
+}
+
+func matchesAnyType(re *regexp.Regexp) bool {
+	for typ := otlptranslator.MetricType(0); typ <= otlptranslator.MetricTypeSummary; typ++ {
+		if re.MatchString(typ.String()) {
+			return true
+		}
+	}
+	return false
+}
+
+// match reports whether the rule matches a metric with the given name, and
+// returns the submatches of the name.
+func (rl *rule) match(name string, metric otlptranslator.Metric) ([]int, bool) {
+	if !rl.unit.MatchString(metric.Unit) || !rl.typ.MatchString(metric.Type.String()) {
+		return nil, false
+	}
+	submatches := rl.name.FindStringSubmatchIndex(name)
+	return submatches, submatches != nil
+}
+
+// expand returns the new name of a metric matched with submatches.
+func (rl *rule) expand(name string, submatches []int) string {
+	return string(rl.name.ExpandString(nil, rl.rename, name, submatches))
+}
+
+// Apply applies the rules of the BeforeTranslation stage to metric, and
+// returns the changed metric, or false if it is dropped.
+//
+// Example:
+//
+//	metric, ok := rules.Apply(metric)
+//	if !ok {
+//		return // Dropped.
+//	}
+//	samples, err := sampleBuilder.BuildNumber(metric, extra, point)
+func (r *Rules) Apply(metric otlptranslator.Metric) (otlptranslator.Metric, bool) {
+	for i := range r.before {
+		rl := &r.before[i]
+		submatches, ok := rl.match(metric.Name, metric)
+		if !ok {
+			continue
+		}
+		if rl.drop {
+			return otlptranslator.Metric{}, false
+		}
+		if rl.rename != "" {
+			metric.Name = rl.expand(metric.Name, submatches)
+		}
+		if rl.setUnit != nil {
+			metric.Unit = *rl.setUnit
+		}
+		if r.mode != AllMatch {
+			break
+		}
+	}
+	return metric, true
+}
+
+// Override applies the rules of the AfterTranslation stage to name, the
+// translated name of metric, and returns the overridden name, or false if
+// the metric is dropped.
+func (r *Rules) Override(metric otlptranslator.Metric, name string) (string, bool) {
+	for i := range r.after {
+		rl := &r.after[i]
+		submatches, ok := rl.match(name, metric)
+		if !ok {
+			continue
+		}
+		if rl.drop {
+			return "", false
+		}
+		name = rl.expand(name, submatches)
+		if r.mode != AllMatch {
+			break
+		}
+	}
+	return name, true
+}
+
+// Build builds the name of metric with namer, applying the rules of both
+// stages around it, and returns false if the metric is dropped. Overridden
+// names must be valid for namer: legacy Prometheus names unless it allows
+// UTF-8.
+func (r *Rules) Build(namer *otlptranslator.MetricNamer, metric otlptranslator.Metric) (string, bool, error) {
+	metric, ok := r.Apply(metric)
+	if !ok {
+		return "", false, nil
+	}
+	name, err := namer.Build(metric)
+	if err != nil {
+		return "", false, err
+	}
+	overridden, ok := r.Override(metric, name)
+	switch {
+	case !ok:
+		return "", false, nil
+	case overridden == name:
+		return name, true, nil
+	case overridden == "" || !utf8.ValidString(overridden):
+		return "", false, fmt.Errorf("metric %q overridden with invalid name %q", name, overridden)
+	case !namer.UTF8Allowed && !legacy.IsMetricName(overridden):
+		return "", false, fmt.Errorf("metric %q overridden with name %q, which is not a valid legacy Prometheus metric name", name, overridden)
+	}
+	return overridden, true, nil
+}
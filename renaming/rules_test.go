@@ -0,0 +1,180 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package renaming
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/otlptranslator"
+)
+
+func ptr(s string) *string {
+	return &s
+}
+
+func TestRules_Apply(t *testing.T) {
+	rules := []Rule{
+		{Match: Match{Name: `vendor\.(.+)\.millis`, Type: "gauge|histogram"}, Rename: "acme.$1.duration", Unit: ptr("ms")},
+		{Match: Match{Name: `vendor\.debug\..*`}, Drop: true},
+		{Match: Match{Unit: "^$", Type: "counter"}, Unit: ptr("{event}")},
+		{Match: Match{Name: `acme\..*`}, Rename: "${0}.renamed"},
+	}
+	for _, tc := range []struct {
+		name    string
+		mode    Mode
+		in      otlptranslator.Metric
+		want    otlptranslator.Metric
+		dropped bool
+	}{
+		{
+			name: "rename and unit",
+			in:   otlptranslator.Metric{Name: "vendor.db.query.millis", Type: otlptranslator.MetricTypeHistogram},
+			want: otlptranslator.Metric{Name: "acme.db.query.duration", Unit: "ms", Type: otlptranslator.MetricTypeHistogram},
+		},
+		{
+			name: "all matches",
+			mode: AllMatch,
+			in:   otlptranslator.Metric{Name: "vendor.db.query.millis", Type: otlptranslator.MetricTypeHistogram},
+			want: otlptranslator.Metric{Name: "acme.db.query.duration.renamed", Unit: "ms", Type: otlptranslator.MetricTypeHistogram},
+		},
+		{
+			name: "type mismatch",
+			in:   otlptranslator.Metric{Name: "vendor.db.query.millis", Type: otlptranslator.MetricTypeSummary},
+			want: otlptranslator.Metric{Name: "vendor.db.query.millis", Type: otlptranslator.MetricTypeSummary},
+		},
+		{
+			name:    "drop",
+			in:      otlptranslator.Metric{Name: "vendor.debug.allocs", Type: otlptranslator.MetricTypeGauge},
+			dropped: true,
+		},
+		{
+			name: "unit match",
+			in:   otlptranslator.Metric{Name: "events", Type: otlptranslator.MetricTypeMonotonicCounter},
+			want: otlptranslator.Metric{Name: "events", Unit: "{event}", Type: otlptranslator.MetricTypeMonotonicCounter},
+		},
+		{
+			name: "unit mismatch",
+			in:   otlptranslator.Metric{Name: "events", Unit: "1", Type: otlptranslator.MetricTypeMonotonicCounter},
+			want: otlptranslator.Metric{Name: "events", Unit: "1", Type: otlptranslator.MetricTypeMonotonicCounter},
+		},
+		{
+			name: "partial name match",
+			in:   otlptranslator.Metric{Name: "my.vendor.debug.allocs", Type: otlptranslator.MetricTypeGauge},
+			want: otlptranslator.Metric{Name: "my.vendor.debug.allocs", Type: otlptranslator.MetricTypeGauge},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := New(Config{Mode: tc.mode, Rules: rules})
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, ok := r.Apply(tc.in)
+			if ok == tc.dropped || (ok && got != tc.want) {
+				t.Errorf("Apply() = %+v, %t, want %+v, %t", got, ok, tc.want, !tc.dropped)
+			}
+		})
+	}
+}
+
+func TestRules_Build(t *testing.T) {
+	r, err := New(Config{Rules: []Rule{
+		{Match: Match{Name: `vendor\.(.+)`}, Rename: "acme.$1"},
+		{Stage: AfterTranslation, Match: Match{Name: `acme_queue_size`}, Rename: "acme_queue_length"},
+		{Stage: AfterTranslation, Match: Match{Name: `acme_internal_.*`}, Drop: true},
+		{Stage: AfterTranslation, Match: Match{Name: `acme_(.*)_total`, Type: "counter"}, Rename: "acme.$1.count"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		name     string
+		strategy otlptranslator.TranslationStrategyOption
+		in       otlptranslator.Metric
+		want     string
+		dropped  bool
+		wantErr  string
+	}{
+		{
+			name:     "before and after",
+			strategy: otlptranslator.UnderscoreEscapingWithSuffixes,
+			in:       otlptranslator.Metric{Name: "vendor.queue.size", Type: otlptranslator.MetricTypeGauge},
+			want:     "acme_queue_length",
+		},
+		{
+			name:     "not overridden",
+			strategy: otlptranslator.UnderscoreEscapingWithSuffixes,
+			in:       otlptranslator.Metric{Name: "vendor.latency", Unit: "s", Type: otlptranslator.MetricTypeHistogram},
+			want:     "acme_latency_seconds",
+		},
+		{
+			name:     "dropped after translation",
+			strategy: otlptranslator.UnderscoreEscapingWithSuffixes,
+			in:       otlptranslator.Metric{Name: "vendor.internal.gc", Type: otlptranslator.MetricTypeGauge},
+			dropped:  true,
+		},
+		{
+			name:     "invalid legacy name",
+			strategy: otlptranslator.UnderscoreEscapingWithSuffixes,
+			in:       otlptranslator.Metric{Name: "vendor.requests", Type: otlptranslator.MetricTypeMonotonicCounter},
+			wantErr:  `metric "acme_requests_total" overridden with name "acme.requests.count", which is not a valid legacy Prometheus metric name`,
+		},
+		{
+			name:     "UTF-8 name",
+			strategy: otlptranslator.NoUTF8EscapingWithSuffixes,
+			in:       otlptranslator.Metric{Name: "acme_requests", Type: otlptranslator.MetricTypeMonotonicCounter},
+			want:     "acme.requests.count",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			namer := otlptranslator.NewMetricNamer("", tc.strategy)
+			got, ok, err := r.Build(&namer, tc.in)
+			if tc.wantErr != "" {
+				if err == nil || err.Error() != tc.wantErr {
+					t.Fatalf("Build() error = %v, want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tc.want || ok == tc.dropped {
+				t.Errorf("Build() = %q, %t, want %q, %t", got, ok, tc.want, !tc.dropped)
+			}
+		})
+	}
+}
+
+func TestNew_Errors(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		cfg     Config
+		wantErr string
+	}{
+		{name: "mode", cfg: Config{Mode: "any"}, wantErr: `unknown mode "any", want "first" or "all"`},
+		{name: "stage", cfg: Config{Rules: []Rule{{Stage: "during", Drop: true}}}, wantErr: `rule 1: unknown stage "during"`},
+		{name: "no action", cfg: Config{Rules: []Rule{{Match: Match{Name: "a"}}}}, wantErr: "rule 1: no action"},
+		{name: "drop and rename", cfg: Config{Rules: []Rule{{Drop: true, Rename: "a"}}}, wantErr: "rule 1: drop cannot be combined"},
+		{name: "unit after translation", cfg: Config{Rules: []Rule{{Stage: AfterTranslation, Unit: ptr("s")}}}, wantErr: "rule 1: units cannot be changed after translation"},
+		{name: "regexp", cfg: Config{Rules: []Rule{{Drop: true}, {Match: Match{Name: "("}, Drop: true}}}, wantErr: "rule 2: invalid name regular expression"},
+		{name: "type", cfg: Config{Rules: []Rule{{Match: Match{Type: "sum"}, Drop: true}}}, wantErr: `rule 1: type "sum" matches no metric type`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := New(tc.cfg)
+			if err == nil || !strings.HasPrefix(err.Error(), tc.wantErr) {
+				t.Errorf("New() error = %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
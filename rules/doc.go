@@ -0,0 +1,23 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package rules migrates Prometheus rule files between translation
+// strategies, so that alerting and recording rules keep selecting the series
+// of OpenTelemetry metrics once Prometheus translates their names
+// differently.
+//
+// Main components:
+//   - Migrator: Rewrites the expressions, labels and annotations of rule files
+//   - Result: The migrated file, its diff and the cases left to review
+//   - Ambiguity: A name that could not be mapped with certainty
+package rules
@@ -0,0 +1,321 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rules
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/otlptranslator/internal/diff"
+	"github.com/prometheus/otlptranslator/internal/migrate"
+	"github.com/prometheus/otlptranslator/internal/yaml"
+	"github.com/prometheus/otlptranslator/promql"
+)
+
+// templateLabelRef matches the references to labels in templates, either as
+// $labels.name or as index $labels "name".
+var templateLabelRef = regexp.MustCompile(`\$labels\.([a-zA-Z_][a-zA-Z0-9_]*)|index\s+\$labels\s+("(?:[^"\\]|\\.)*")`)
+
+// migration is the migration of a rule file. It translates the names of
+// the rule being migrated as a promql.Translator, reporting ambiguities
+// instead of returning errors.
+type migration struct {
+	m           *Migrator
+	src         []byte
+	edits       []diff.Edit
+	ambiguities []Ambiguity
+	// recorded holds the names of the recording rules of the file.
+	recorded map[string]bool
+	// group, rule and line locate the names being translated.
+	group, rule string
+	line        int
+}
+
+func (mg *migration) errorf(n *yaml.Node, format string, args ...any) error {
+	return fmt.Errorf("line %d: %s", mg.lineAt(n.Start), fmt.Sprintf(format, args...))
+}
+
+// lineAt returns the line of a source offset.
+func (mg *migration) lineAt(offset int) int {
+	return bytes.Count(mg.src[:offset], []byte("\n")) + 1
+}
+
+// ambiguous reports an ambiguous name at the current line.
+func (mg *migration) ambiguous(name string, candidates []string, reason string) {
+	a := Ambiguity{Line: mg.line, Group: mg.group, Rule: mg.rule, Name: name, Candidates: candidates, Reason: reason}
+	if !slices.ContainsFunc(mg.ambiguities, func(b Ambiguity) bool {
+		return a.Line == b.Line && a.Name == b.Name && a.Reason == b.Reason
+	}) {
+		mg.ambiguities = append(mg.ambiguities, a)
+	}
+}
+
+// unsupported reports a node that cannot be migrated.
+func (mg *migration) unsupported(n *yaml.Node) {
+	mg.line = mg.lineAt(n.Start)
+	mg.ambiguous("", nil, "flow collections, anchors, aliases and tags are not supported, left unchanged")
+}
+
+// migrateFile migrates the groups of a rule file.
+func (mg *migration) migrateFile(root *yaml.Node) error {
+	if root.Kind == yaml.NullNode {
+		return nil
+	}
+	if root.Kind != yaml.MappingNode {
+		return mg.errorf(root, "the rule file must be a mapping")
+	}
+	groups := root.Get("groups")
+	switch {
+	case groups == nil || groups.Kind == yaml.NullNode:
+		return nil
+	case groups.Kind == yaml.OpaqueNode:
+		mg.unsupported(groups)
+		return nil
+	case groups.Kind != yaml.SequenceNode:
+		return mg.errorf(groups, "groups must be a sequence")
+	}
+
+	// Recording rules are named by users: their names are kept wherever they
+	// are selected.
+	for _, g := range groups.Items {
+		if rules := g.Get("rules"); rules != nil && rules.Kind == yaml.SequenceNode {
+			for _, r := range rules.Items {
+				if name := r.Get("record").Scalar(); name != "" {
+					mg.recorded[name] = true
+				}
+			}
+		}
+	}
+
+	for _, g := range groups.Items {
+		switch g.Kind {
+		case yaml.OpaqueNode:
+			mg.unsupported(g)
+			continue
+		case yaml.MappingNode:
+		default:
+			return mg.errorf(g, "rule groups must be mappings")
+		}
+		mg.group = g.Get("name").Scalar()
+		rules := g.Get("rules")
+		switch {
+		case rules == nil || rules.Kind == yaml.NullNode:
+			continue
+		case rules.Kind == yaml.OpaqueNode:
+			mg.unsupported(rules)
+			continue
+		case rules.Kind != yaml.SequenceNode:
+			return mg.errorf(rules, "rules must be a sequence")
+		}
+		for _, r := range rules.Items {
+			switch r.Kind {
+			case yaml.OpaqueNode:
+				mg.unsupported(r)
+				continue
+			case yaml.MappingNode:
+			default:
+				return mg.errorf(r, "rules must be mappings")
+			}
+			if mg.rule = r.Get("alert").Scalar(); mg.rule == "" {
+				mg.rule = r.Get("record").Scalar()
+			}
+			if err := mg.migrateExpr(r.Get("expr")); err != nil {
+				return err
+			}
+			mg.migrateLabels(r.Get("labels"))
+			mg.migrateTemplates(r.Get("annotations"))
+		}
+		mg.rule = ""
+	}
+
+	slices.SortFunc(mg.edits, func(a, b diff.Edit) int { return a.Start - b.Start })
+	slices.SortStableFunc(mg.ambiguities, func(a, b Ambiguity) int { return a.Line - b.Line })
+	return nil
+}
+
+// migrateExpr migrates the expression of a rule.
+func (mg *migration) migrateExpr(n *yaml.Node) error {
+	switch {
+	case n == nil || n.Kind == yaml.NullNode:
+		return nil
+	case n.Kind == yaml.OpaqueNode:
+		mg.unsupported(n)
+		return nil
+	case n.Kind != yaml.ScalarNode:
+		return mg.errorf(n, "expr must be a string")
+	}
+	mg.line = mg.lineAt(n.Offsets[0])
+	reported := len(mg.ambiguities)
+	query, unresolved, err := promql.NewTranslatorRewriter(mg).Rewrite(n.Value)
+	if err != nil {
+		mg.ambiguous("", nil, fmt.Sprintf("the expression cannot be migrated: %v", err))
+		return nil
+	}
+	// Locate the names reported by the translator in multi-line expressions.
+	for i := reported; i < len(mg.ambiguities); i++ {
+		if pos := strings.Index(n.Value, mg.ambiguities[i].Name); pos >= 0 {
+			mg.ambiguities[i].Line = mg.lineAt(n.Offsets[pos])
+		}
+	}
+	for _, u := range unresolved {
+		mg.line = mg.lineAt(n.Offsets[u.Pos])
+		mg.ambiguous(u.Selector, nil, u.Reason)
+	}
+	mg.replace(n, query)
+	return nil
+}
+
+// migrateLabels migrates the labels of a rule: the keys that are known
+// attributes, and the label references of the value templates.
+func (mg *migration) migrateLabels(n *yaml.Node) {
+	if n != nil && n.Kind == yaml.OpaqueNode {
+		mg.unsupported(n)
+		return
+	}
+	if n == nil || n.Kind != yaml.MappingNode {
+		return
+	}
+	for i, k := range n.Keys {
+		mg.line = mg.lineAt(k.Start)
+		switch names, _ := mg.m.mapper.KnownLabel(k.Value); {
+		case len(names) == 1:
+			mg.replace(k, names[0])
+		case len(names) > 1:
+			mg.ambiguous(k.Value, names, "known attributes translated to this label have different new names")
+		}
+		mg.migrateTemplate(n.Values[i])
+	}
+}
+
+// migrateTemplates migrates the label references of the value templates of
+// annotations.
+func (mg *migration) migrateTemplates(n *yaml.Node) {
+	if n != nil && n.Kind == yaml.OpaqueNode {
+		mg.unsupported(n)
+		return
+	}
+	if n == nil || n.Kind != yaml.MappingNode {
+		return
+	}
+	for _, v := range n.Values {
+		mg.migrateTemplate(v)
+	}
+}
+
+// migrateTemplate migrates the label references of a template. References
+// to labels that are not valid identifiers use the index function.
+func (mg *migration) migrateTemplate(n *yaml.Node) {
+	if n.Kind == yaml.OpaqueNode {
+		mg.unsupported(n)
+		return
+	}
+	if n.Kind != yaml.ScalarNode {
+		return
+	}
+	var b strings.Builder
+	last := 0
+	for _, loc := range templateLabelRef.FindAllStringSubmatchIndex(n.Value, -1) {
+		mg.line = mg.lineAt(n.Offsets[loc[0]])
+		if loc[2] >= 0 {
+			oldName := n.Value[loc[2]:loc[3]]
+			newName, _ := mg.LabelName(oldName)
+			if newName == oldName {
+				continue
+			}
+			b.WriteString(n.Value[last:loc[0]])
+			if migrate.IsLegacyLabelName(newName) {
+				b.WriteString("$labels." + newName)
+			} else {
+				b.WriteString("(index $labels " + strconv.Quote(newName) + ")")
+			}
+			last = loc[1]
+			continue
+		}
+		oldName, err := strconv.Unquote(n.Value[loc[4]:loc[5]])
+		if err != nil {
+			continue
+		}
+		newName, _ := mg.LabelName(oldName)
+		if newName == oldName {
+			continue
+		}
+		b.WriteString(n.Value[last:loc[4]])
+		b.WriteString(strconv.Quote(newName))
+		last = loc[5]
+	}
+	b.WriteString(n.Value[last:])
+	mg.replace(n, b.String())
+}
+
+// MetricName implements promql.Translator.
+func (mg *migration) MetricName(name string) (string, error) {
+	if mg.recorded[name] {
+		return name, nil
+	}
+	newName, a := mg.m.mapper.MetricName(name)
+	if a != nil {
+		mg.ambiguous(name, a.Candidates, a.Reason)
+	}
+	return newName, nil
+}
+
+// LabelName implements promql.Translator.
+func (mg *migration) LabelName(name string) (string, error) {
+	newName, a := mg.m.mapper.LabelName(name)
+	if a != nil {
+		mg.ambiguous(name, a.Candidates, a.Reason)
+	}
+	return newName, nil
+}
+
+// replace records the edits replacing the value of a scalar, keeping its
+// style. Plain scalars that would become invalid are double-quoted.
+func (mg *migration) replace(n *yaml.Node, value string) {
+	if value == n.Value {
+		return
+	}
+	if n.Style == yaml.PlainStyle && !isPlainSafe(value) {
+		mg.edits = append(mg.edits, diff.Edit{Start: n.Start, End: n.End, Text: strconv.Quote(value)})
+		return
+	}
+	for _, e := range diff.Words(n.Value, value) {
+		text := e.Text
+		switch n.Style {
+		case yaml.SingleQuotedStyle:
+			text = strings.ReplaceAll(text, "'", "''")
+		case yaml.DoubleQuotedStyle:
+			text = strconv.Quote(text)
+			text = text[1 : len(text)-1]
+		}
+		mg.edits = append(mg.edits, diff.Edit{Start: n.Offsets[e.Start], End: n.Offsets[e.End], Text: text})
+	}
+}
+
+// isPlainSafe reports whether s can be written as a plain scalar in a block
+// mapping, keeping its line breaks.
+func isPlainSafe(s string) bool {
+	s = strings.ReplaceAll(s, "\n", " ")
+	switch {
+	case s == "" || strings.TrimSpace(s) != s:
+		return false
+	case strings.ContainsRune("-?:,[]{}#&*!|>'\"%@`", rune(s[0])):
+		return false
+	default:
+		return !strings.Contains(s, ": ") && !strings.Contains(s, " #") && !strings.HasSuffix(s, ":")
+	}
+}
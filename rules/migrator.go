@@ -0,0 +1,167 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rules
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/prometheus/otlptranslator"
+	"github.com/prometheus/otlptranslator/internal/diff"
+	"github.com/prometheus/otlptranslator/internal/migrate"
+	"github.com/prometheus/otlptranslator/internal/yaml"
+)
+
+// MigratorOptions configures a Migrator.
+type MigratorOptions struct {
+	// From is the translation strategy the rule files were written for.
+	From otlptranslator.TranslationStrategyOption
+	// To is the translation strategy the rule files are migrated to.
+	To otlptranslator.TranslationStrategyOption
+	// Namespace is the namespace of metric names, under both strategies.
+	Namespace string
+	// Metrics are the known OpenTelemetry metrics. Their names are mapped
+	// exactly, while the names of other metrics are reverse translated.
+	Metrics []otlptranslator.Metric
+	// Attributes are the known OpenTelemetry attribute names. Their labels
+	// are mapped exactly, while other labels are reverse translated.
+	Attributes []string
+}
+
+// Ambiguity is a name, or an expression, that a Migrator could not map with
+// certainty, and that should be reviewed.
+type Ambiguity struct {
+	// Line is the line of the name in the rule file.
+	Line int
+	// Group and Rule are the names of the rule group and of the rule.
+	Group, Rule string
+	// Name is the metric or label name, or the selector, that is ambiguous.
+	Name string
+	// Candidates are the possible new names, if known. The name is migrated
+	// to the first one if it was not left unchanged.
+	Candidates []string
+	// Reason explains why the name is ambiguous.
+	Reason string
+}
+
+// String returns the position, name and reason of the ambiguity.
+func (a Ambiguity) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "line %d", a.Line)
+	if a.Group != "" {
+		fmt.Fprintf(&b, ", group %q", a.Group)
+	}
+	if a.Rule != "" {
+		fmt.Fprintf(&b, ", rule %q", a.Rule)
+	}
+	if a.Name != "" {
+		fmt.Fprintf(&b, ": %s", a.Name)
+	}
+	fmt.Fprintf(&b, ": %s", a.Reason)
+	if len(a.Candidates) > 0 {
+		fmt.Fprintf(&b, " (candidates: %s)", strings.Join(a.Candidates, ", "))
+	}
+	return b.String()
+}
+
+// Result is the result of the migration of a rule file.
+type Result struct {
+	// Content is the migrated rule file.
+	Content []byte
+	// Diff is the unified diff of the migration, empty if nothing changed.
+	Diff string
+	// Ambiguities are the cases that should be reviewed, in the order of the
+	// file.
+	Ambiguities []Ambiguity
+}
+
+// Migrator migrates Prometheus rule files from a translation strategy to
+// another.
+//
+// Every metric name of the rule expressions is mapped to the name the new
+// strategy gives to the same OpenTelemetry metric. Names of known metrics are
+// mapped exactly. Other names are reverse translated: the type and unit of
+// the metric are guessed from its suffixes, assuming OpenTelemetry names do
+// not hold suffixes themselves, and its name is built again with the new
+// strategy. Label names are mapped the same way, in expressions, in the keys
+// of rule labels for known attributes, and in the $labels references of label
+// and annotation templates. Names of recording rules, and names holding a
+// colon, are left unchanged.
+//
+// Names are ambiguous, and reported as such, when several known metrics or
+// attributes map to different names, when suffixes must be added to unknown
+// metrics, whose type and unit cannot be guessed, and when escaping is
+// disabled: underscores of unknown names may then stand for escaped
+// characters, such as dots.
+//
+// The rule file is edited in place: formatting, comments and quoting styles
+// are kept, except for plain scalars that must be quoted after the
+// migration.
+//
+// Example usage:
+//
+//	m, err := rules.NewMigrator(rules.MigratorOptions{
+//		From:    otlptranslator.UnderscoreEscapingWithSuffixes,
+//		To:      otlptranslator.NoUTF8EscapingWithSuffixes,
+//		Metrics: []otlptranslator.Metric{{Name: "http.server.request.duration", Unit: "s", Type: otlptranslator.MetricTypeHistogram}},
+//	})
+//	if err != nil {
+//		// handle err
+//	}
+//	res, err := m.Migrate("alerts.yml", content)
+//	// res.Content selects {"http.server.request.duration_seconds_bucket"}
+//	// instead of http_server_request_duration_seconds_bucket.
+type Migrator struct {
+	mapper *migrate.Mapper
+}
+
+// NewMigrator creates a Migrator. It returns an error if a strategy is
+// unknown, or if a known metric or attribute cannot be translated.
+func NewMigrator(opts MigratorOptions) (*Migrator, error) {
+	mapper, err := migrate.NewMapper(migrate.Options(opts))
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{mapper: mapper}, nil
+}
+
+// Migrate migrates the content of a rule file. The file name is only used in
+// the diff and in errors. It returns an error if the file is not a valid
+// rule file, in the YAML subset supported by the Migrator: block mappings and
+// sequences, and plain, quoted, literal and folded scalars, in a single
+// document.
+func (m *Migrator) Migrate(filename string, content []byte) (Result, error) {
+	root, err := yaml.Parse(content)
+	if err != nil {
+		return Result{}, fmt.Errorf("%s: %w", filename, err)
+	}
+	mg := &migration{m: m, src: content, recorded: map[string]bool{}}
+	if err := mg.migrateFile(root); err != nil {
+		return Result{}, fmt.Errorf("%s: %w", filename, err)
+	}
+
+	var out []byte
+	last := 0
+	for _, e := range mg.edits {
+		out = append(out, content[last:e.Start]...)
+		out = append(out, e.Text...)
+		last = e.End
+	}
+	out = append(out, content[last:]...)
+	return Result{
+		Content:     out,
+		Diff:        diff.Unified("a/"+filename, "b/"+filename, string(content), string(out)),
+		Ambiguities: mg.ambiguities,
+	}, nil
+}
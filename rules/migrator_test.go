@@ -0,0 +1,277 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rules
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/otlptranslator"
+)
+
+func TestMigrator(t *testing.T) {
+	duration := otlptranslator.Metric{Name: "http.server.request.duration", Unit: "s", Type: otlptranslator.MetricTypeHistogram}
+	for _, tc := range []struct {
+		name        string
+		opts        MigratorOptions
+		in          string
+		want        string
+		ambiguities []string
+	}{
+		{
+			name: "known metrics and attributes",
+			opts: MigratorOptions{
+				From:       otlptranslator.UnderscoreEscapingWithSuffixes,
+				To:         otlptranslator.NoUTF8EscapingWithSuffixes,
+				Metrics:    []otlptranslator.Metric{duration},
+				Attributes: []string{"http.route"},
+			},
+			in: `groups:
+  - name: http
+    rules:
+      - alert: HighLatency
+        # The 90th percentile.
+        expr: histogram_quantile(0.9, sum by (le, http_route) (rate(http_server_request_duration_seconds_bucket[5m]))) > 1
+        for: 5m
+        labels:
+          severity: page
+          http_route: '{{ $labels.http_route }}'
+        annotations:
+          summary: "Latency of {{ $labels.http_route }} on {{ $labels.instance }}"
+`,
+			want: `groups:
+  - name: http
+    rules:
+      - alert: HighLatency
+        # The 90th percentile.
+        expr: histogram_quantile(0.9, sum by (le, "http.route") (rate({"http.server.request.duration_seconds_bucket"}[5m]))) > 1
+        for: 5m
+        labels:
+          severity: page
+          http.route: '{{ (index $labels "http.route") }}'
+        annotations:
+          summary: "Latency of {{ (index $labels \"http.route\") }} on {{ $labels.instance }}"
+`,
+		},
+		{
+			name: "reverse translation",
+			opts: MigratorOptions{
+				From: otlptranslator.UnderscoreEscapingWithSuffixes,
+				To:   otlptranslator.UnderscoreEscapingWithoutSuffixes,
+			},
+			in: `groups:
+- name: example
+  rules:
+  - record: job:latency:p90
+    expr: |
+      histogram_quantile(0.9,
+        sum by (job, le) (rate(latency_seconds_bucket[5m])))
+  - alert: Errors
+    expr: rate(errors_total[5m]) / rate(requests_total[5m]) > job:latency:p90
+  - alert: Memory
+    expr: >-
+      memory_usage_bytes
+      > memory_limit_bytes
+`,
+			want: `groups:
+- name: example
+  rules:
+  - record: job:latency:p90
+    expr: |
+      histogram_quantile(0.9,
+        sum by (job, le) (rate(latency_bucket[5m])))
+  - alert: Errors
+    expr: rate(errors[5m]) / rate(requests[5m]) > job:latency:p90
+  - alert: Memory
+    expr: >-
+      memory_usage
+      > memory_limit
+`,
+		},
+		{
+			name: "unknown names when disabling escaping",
+			opts: MigratorOptions{
+				From: otlptranslator.UnderscoreEscapingWithSuffixes,
+				To:   otlptranslator.NoTranslation,
+			},
+			in: `groups:
+  - name: example
+    rules:
+      - alert: Down
+        expr: 'sum by (job, k8s_pod_name) (process_cpu_time_seconds_total) == 0'
+`,
+			want: `groups:
+  - name: example
+    rules:
+      - alert: Down
+        expr: 'sum by (job, k8s_pod_name) (process_cpu_time) == 0'
+`,
+			ambiguities: []string{
+				`line 5, group "example", rule "Down": k8s_pod_name: the attribute is unknown, and underscores of its name may stand for escaped characters`,
+				`line 5, group "example", rule "Down": process_cpu_time_seconds_total: the metric is unknown, and underscores of its name may stand for escaped characters (candidates: process_cpu_time)`,
+			},
+		},
+		{
+			name: "suffixes of unknown metrics",
+			opts: MigratorOptions{
+				From:    otlptranslator.NoTranslation,
+				To:      otlptranslator.UnderscoreEscapingWithSuffixes,
+				Metrics: []otlptranslator.Metric{duration},
+			},
+			in: `groups:
+  - name: example
+    rules:
+      - alert: Slow
+        expr: histogram_quantile(0.9, rate({"http.server.request.duration_bucket", "http.route"="/"}[5m])) > queue_length
+`,
+			want: `groups:
+  - name: example
+    rules:
+      - alert: Slow
+        expr: histogram_quantile(0.9, rate(http_server_request_duration_seconds_bucket{http_route="/"}[5m])) > queue_length
+`,
+			ambiguities: []string{
+				`line 5, group "example", rule "Slow": queue_length: the metric is unknown, and its type and unit are needed to add suffixes`,
+			},
+		},
+		{
+			name: "quoted plain scalar",
+			opts: MigratorOptions{
+				From:    otlptranslator.UnderscoreEscapingWithSuffixes,
+				To:      otlptranslator.NoUTF8EscapingWithSuffixes,
+				Metrics: []otlptranslator.Metric{duration},
+			},
+			in: `groups:
+  - name: example
+    rules:
+      - alert: Requests
+        expr: http_server_request_duration_seconds_count > 0
+`,
+			want: `groups:
+  - name: example
+    rules:
+      - alert: Requests
+        expr: "{\"http.server.request.duration_seconds_count\"} > 0"
+`,
+		},
+		{
+			name: "conflicting known metrics",
+			opts: MigratorOptions{
+				From: otlptranslator.UnderscoreEscapingWithSuffixes,
+				To:   otlptranslator.NoUTF8EscapingWithSuffixes,
+				Metrics: []otlptranslator.Metric{
+					{Name: "queue.size", Type: otlptranslator.MetricTypeGauge},
+					{Name: "queue_size", Type: otlptranslator.MetricTypeGauge},
+				},
+			},
+			in: `groups:
+  - name: example
+    rules:
+      - alert: Full
+        expr: queue_size > 100
+        labels: {severity: page}
+`,
+			want: `groups:
+  - name: example
+    rules:
+      - alert: Full
+        expr: queue_size > 100
+        labels: {severity: page}
+`,
+			ambiguities: []string{
+				`line 5, group "example", rule "Full": queue_size: known metrics translated to this name have different new names (candidates: queue.size, queue_size)`,
+				`line 6, group "example", rule "Full": flow collections, anchors, aliases and tags are not supported, left unchanged`,
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			m, err := NewMigrator(tc.opts)
+			if err != nil {
+				t.Fatalf("NewMigrator() error = %v", err)
+			}
+			res, err := m.Migrate("rules.yml", []byte(tc.in))
+			if err != nil {
+				t.Fatalf("Migrate() error = %v", err)
+			}
+			if string(res.Content) != tc.want {
+				t.Errorf("Migrate() content =\n%s\nwant\n%s", res.Content, tc.want)
+			}
+			if (res.Diff == "") != (tc.in == tc.want) {
+				t.Errorf("Migrate() diff = %q", res.Diff)
+			}
+			var ambiguities []string
+			for _, a := range res.Ambiguities {
+				ambiguities = append(ambiguities, a.String())
+			}
+			if !reflect.DeepEqual(ambiguities, tc.ambiguities) {
+				t.Errorf("Migrate() ambiguities =\n%s\nwant\n%s", strings.Join(ambiguities, "\n"), strings.Join(tc.ambiguities, "\n"))
+			}
+		})
+	}
+}
+
+func TestMigrator_Diff(t *testing.T) {
+	m, err := NewMigrator(MigratorOptions{
+		From: otlptranslator.UnderscoreEscapingWithSuffixes,
+		To:   otlptranslator.UnderscoreEscapingWithoutSuffixes,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := m.Migrate("rules.yml", []byte("groups:\n- name: a\n  rules:\n  - alert: A\n    expr: requests_total > 0\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `--- a/rules.yml
++++ b/rules.yml
+@@ -2,4 +2,4 @@
+ - name: a
+   rules:
+   - alert: A
+-    expr: requests_total > 0
++    expr: requests > 0
+`
+	if res.Diff != want {
+		t.Errorf("Migrate() diff =\n%s\nwant\n%s", res.Diff, want)
+	}
+}
+
+func TestMigrator_Errors(t *testing.T) {
+	if _, err := NewMigrator(MigratorOptions{From: "Unknown", To: otlptranslator.NoTranslation}); err == nil {
+		t.Error("NewMigrator() with an unknown strategy succeeded")
+	}
+	m, err := NewMigrator(MigratorOptions{From: otlptranslator.UnderscoreEscapingWithSuffixes, To: otlptranslator.NoTranslation})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		name string
+		in   string
+		err  string
+	}{
+		{name: "not a mapping", in: "- a\n", err: "rules.yml: line 1: the rule file must be a mapping"},
+		{name: "groups not a sequence", in: "groups: a\n", err: "rules.yml: line 1: groups must be a sequence"},
+		{name: "bad indentation", in: "groups:\n  - name: a\n     rules: []\n", err: "rules.yml: line 3: mapping values are not allowed in multi-line plain scalars"},
+		{name: "unterminated string", in: "groups:\n  - name: 'a\n", err: "rules.yml: line 2: unterminated quoted scalar"},
+		{name: "multiple documents", in: "groups: []\n---\ngroups: []\n", err: "rules.yml: line 2: multiple documents are not supported"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := m.Migrate("rules.yml", []byte(tc.in))
+			if err == nil || err.Error() != tc.err {
+				t.Errorf("Migrate() error = %v, want %s", err, tc.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,276 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlptranslator
+
+import (
+	"math"
+	"strconv"
+)
+
+const (
+	bucketSuffix  = "_bucket"
+	sumSuffix     = "_sum"
+	countSuffix   = "_count"
+	createdSuffix = "_created"
+)
+
+// Sample is a single Prometheus float sample produced from an OTLP data
+// point.
+type Sample struct {
+	// Name is the name of the series the sample belongs to, including
+	// structural suffixes such as `_bucket`, `_sum` or `_count`.
+	Name string
+	// Labels holds the labels of the series, without the metric name.
+	Labels Labels
+	Value  float64
+	// Timestamp is the sample timestamp in milliseconds since the Unix epoch.
+	Timestamp int64
+	// CreatedTimestamp is the time in milliseconds since the Unix epoch at
+	// which the series was created, as taken from the data point start time.
+	// It is zero when unknown or meaningless, as for gauges.
+	CreatedTimestamp int64
+	// Exemplars holds the exemplars of the sample, sorted by value. Only the
+	// samples of sums, gauges and histogram buckets have exemplars.
+	Exemplars []SampleExemplar
+}
+
+// SampleBuilder expands OTLP data points into Prometheus samples.
+//
+// Sums and gauges produce a single sample. Only counters, that is monotonic
+// cumulative sums, carry a created timestamp. Histograms produce one `_bucket`
+// sample per bucket plus `_sum` and `_count` samples, and summaries one
+// sample per quantile plus `_sum` and `_count` samples.
+//
+// The start time of cumulative data points is carried as the created
+// timestamp of counter, histogram and summary samples. It can additionally
+// be exposed as an OpenMetrics `_created` series, and be used to inject a
+// zero sample at the start of the series so that rate() and increase() take
+// the first data point of a brand-new series into account.
+//
+// Exemplars are attached to the sample of sums and gauges, and to the
+// `_bucket` sample of the bucket their value falls into for histograms.
+//
+// Data points flagged with DataPointFlagNoRecordedValue produce the same
+// series, all holding a StaleNaN staleness marker, without exemplars.
+//
+// Example usage:
+//
+//	builder := SampleBuilder{
+//		MetricNamer: NewMetricNamer("", UnderscoreEscapingWithSuffixes),
+//		CreatedSeries: true,
+//	}
+//	metric := Metric{Name: "http.requests", Type: MetricTypeMonotonicCounter}
+//	samples, err := builder.BuildNumber(metric, nil, point)
+//	// samples hold http_requests_total and http_requests_created.
+type SampleBuilder struct {
+	MetricNamer MetricNamer
+	LabelNamer  LabelNamer
+	// CreatedSeries, if true, adds an OpenMetrics `_created` sample holding
+	// the start time in seconds for counters, histograms and summaries whose
+	// data point has a start time.
+	CreatedSeries bool
+	// StartTimeZeroSamples, if true, adds a zero sample at the start time of
+	// counters, histograms and summaries, before the actual samples. Quantile
+	// samples of summaries are not cumulative and get no zero sample.
+	StartTimeZeroSamples bool
+}
+
+// BuildNumber builds the samples for a gauge or sum data point. extra holds
+// labels, such as the ones built from resource attributes, added to the ones
+// built from the data point attributes.
+func (b *SampleBuilder) BuildNumber(metric Metric, extra Labels, p NumberDataPoint) ([]Sample, error) {
+	name, ls, err := b.names(metric, extra, p.Attributes)
+	if err != nil {
+		return nil, err
+	}
+	exemplars, err := b.pointExemplars(p.Flags, p.Exemplars)
+	if err != nil {
+		return nil, err
+	}
+	ts := nanosToMillis(p.TimeUnixNano)
+	if metric.Descriptor().PrometheusType() != PrometheusTypeCounter {
+		return []Sample{{Name: name, Labels: ls, Value: staleValue(p.Flags, p.Value), Timestamp: ts, Exemplars: exemplars}}, nil
+	}
+
+	ct := nanosToMillis(p.StartTimeUnixNano)
+	samples := make([]Sample, 0, 3)
+	if b.injectZero(p.Flags, ct, ts) {
+		samples = append(samples, Sample{Name: name, Labels: ls, Timestamp: ct, CreatedTimestamp: ct})
+	}
+	samples = append(samples, Sample{Name: name, Labels: ls, Value: staleValue(p.Flags, p.Value), Timestamp: ts, CreatedTimestamp: ct, Exemplars: exemplars})
+	return b.appendCreated(samples, metric, ls, p.Flags, p.StartTimeUnixNano, ts)
+}
+
+// BuildHistogram builds the samples for an explicit bucket histogram data
+// point. See BuildNumber for the meaning of extra.
+func (b *SampleBuilder) BuildHistogram(metric Metric, extra Labels, p HistogramDataPoint) ([]Sample, error) {
+	name, ls, err := b.names(metric, extra, p.Attributes)
+	if err != nil {
+		return nil, err
+	}
+	buckets := make([]ClassicBucket, 0, len(p.ExplicitBounds)+1)
+	var cumulative uint64
+	for i, bound := range p.ExplicitBounds {
+		if i < len(p.BucketCounts) {
+			cumulative += p.BucketCounts[i]
+		}
+		buckets = append(buckets, ClassicBucket{UpperBound: bound, Count: float64(cumulative)})
+	}
+	buckets = append(buckets, ClassicBucket{UpperBound: math.Inf(1), Count: float64(p.Count)})
+	exemplars, err := b.pointExemplars(p.Flags, p.Exemplars)
+	if err != nil {
+		return nil, err
+	}
+
+	startTimeUnixNano := histogramStartTime(metric, p.StartTimeUnixNano)
+	samples := b.appendHistogramSamples(nil, name, ls, p.Flags, startTimeUnixNano, p.TimeUnixNano, buckets, exemplars, p.Sum, p.Count)
+	return b.appendCreated(samples, metric, ls, p.Flags, startTimeUnixNano, nanosToMillis(p.TimeUnixNano))
+}
+
+// histogramStartTime returns the start time of a histogram data point, or 0
+// for gauge histograms, such as delta histograms, which have no created
+// timestamp, zero sample or `_created` series, like gauges.
+func histogramStartTime(metric Metric, startTimeUnixNano uint64) uint64 {
+	if metric.Descriptor().PrometheusType() != PrometheusTypeHistogram {
+		return 0
+	}
+	return startTimeUnixNano
+}
+
+// appendHistogramSamples appends the `_bucket`, `_sum` and `_count` samples
+// of a classic histogram with the given cumulative buckets and exemplars.
+func (b *SampleBuilder) appendHistogramSamples(samples []Sample, name string, ls Labels, flags DataPointFlags, startTimeUnixNano, timeUnixNano uint64, buckets []ClassicBucket, exemplars []SampleExemplar, sum float64, count uint64) []Sample {
+	ts := nanosToMillis(timeUnixNano)
+	ct := nanosToMillis(startTimeUnixNano)
+	appendSeries := func(name string, ls Labels, v float64, exemplars []SampleExemplar) {
+		if b.injectZero(flags, ct, ts) {
+			samples = append(samples, Sample{Name: name, Labels: ls, Timestamp: ct, CreatedTimestamp: ct})
+		}
+		samples = append(samples, Sample{Name: name, Labels: ls, Value: staleValue(flags, v), Timestamp: ts, CreatedTimestamp: ct, Exemplars: exemplars})
+	}
+	perBucket := bucketExemplars(exemplars, buckets)
+	for i, bucket := range buckets {
+		var exemplars []SampleExemplar
+		if perBucket != nil {
+			exemplars = perBucket[i]
+		}
+		appendSeries(name+bucketSuffix, bucketLabels(ls, bucket.UpperBound), bucket.Count, exemplars)
+	}
+	appendSeries(name+sumSuffix, ls, sum, nil)
+	appendSeries(name+countSuffix, ls, float64(count), nil)
+	return samples
+}
+
+// BuildSummary builds the samples for a summary data point. See BuildNumber
+// for the meaning of extra.
+func (b *SampleBuilder) BuildSummary(metric Metric, extra Labels, p SummaryDataPoint) ([]Sample, error) {
+	name, ls, err := b.names(metric, extra, p.Attributes)
+	if err != nil {
+		return nil, err
+	}
+	ts := nanosToMillis(p.TimeUnixNano)
+	ct := nanosToMillis(p.StartTimeUnixNano)
+
+	samples := make([]Sample, 0, len(p.QuantileValues)+6)
+	for _, q := range p.QuantileValues {
+		samples = append(samples, Sample{Name: name, Labels: quantileLabels(ls, q.Quantile), Value: staleValue(p.Flags, q.Value), Timestamp: ts, CreatedTimestamp: ct})
+	}
+	for _, s := range []struct {
+		name  string
+		value float64
+	}{
+		{name + sumSuffix, p.Sum},
+		{name + countSuffix, float64(p.Count)},
+	} {
+		if b.injectZero(p.Flags, ct, ts) {
+			samples = append(samples, Sample{Name: s.name, Labels: ls, Timestamp: ct, CreatedTimestamp: ct})
+		}
+		samples = append(samples, Sample{Name: s.name, Labels: ls, Value: staleValue(p.Flags, s.value), Timestamp: ts, CreatedTimestamp: ct})
+	}
+	return b.appendCreated(samples, metric, ls, p.Flags, p.StartTimeUnixNano, ts)
+}
+
+// names translates the metric name and the series labels.
+func (b *SampleBuilder) names(metric Metric, extra Labels, attributes []Attribute) (string, Labels, error) {
+	name, err := b.MetricNamer.Build(metric)
+	if err != nil {
+		return "", nil, err
+	}
+	ls, err := b.LabelNamer.BuildLabels(attributes)
+	if err != nil {
+		return "", nil, err
+	}
+	return name, ls.Merge(extra), nil
+}
+
+// pointExemplars builds the exemplars of a data point. Staleness markers
+// have none.
+func (b *SampleBuilder) pointExemplars(flags DataPointFlags, exemplars []Exemplar) ([]SampleExemplar, error) {
+	if flags.NoRecordedValue() {
+		return nil, nil
+	}
+	return b.buildExemplars(exemplars)
+}
+
+// injectZero reports whether a zero sample must be added at the created
+// timestamp ct of a series whose sample is at ts. Staleness markers never get
+// a zero sample.
+func (b *SampleBuilder) injectZero(flags DataPointFlags, ct, ts int64) bool {
+	return b.StartTimeZeroSamples && !flags.NoRecordedValue() && ct > 0 && ct < ts
+}
+
+// appendCreated appends the `_created` sample of the series, if enabled and
+// the start time is known.
+func (b *SampleBuilder) appendCreated(samples []Sample, metric Metric, ls Labels, flags DataPointFlags, startTimeUnixNano uint64, ts int64) ([]Sample, error) {
+	if !b.CreatedSeries || startTimeUnixNano == 0 {
+		return samples, nil
+	}
+	name, err := b.MetricNamer.BuildCreated(metric)
+	if err != nil {
+		return nil, err
+	}
+	return append(samples, Sample{
+		Name:      name,
+		Labels:    ls,
+		Value:     staleValue(flags, float64(startTimeUnixNano)/1e9),
+		Timestamp: ts,
+	}), nil
+}
+
+func bucketLabels(ls Labels, bound float64) Labels {
+	return ls.Merge(Labels{{Name: BucketLabel, Value: formatFloat(bound)}})
+}
+
+func quantileLabels(ls Labels, quantile float64) Labels {
+	return ls.Merge(Labels{{Name: QuantileLabel, Value: formatFloat(quantile)}})
+}
+
+// formatFloat formats a bucket boundary or quantile the way Prometheus does.
+func formatFloat(f float64) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "+Inf"
+	case math.IsInf(f, -1):
+		return "-Inf"
+	case math.IsNaN(f):
+		return "NaN"
+	default:
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+}
+
+// nanosToMillis converts an OTLP timestamp to a Prometheus one.
+func nanosToMillis(ns uint64) int64 {
+	return int64(ns / 1e6)
+}
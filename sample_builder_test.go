@@ -0,0 +1,225 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlptranslator
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSampleBuilder_BuildNumber(t *testing.T) {
+	method := Labels{{Name: "method", Value: "GET"}}
+	point := NumberDataPoint{
+		Attributes:        []Attribute{{Key: "method", Value: "GET"}},
+		StartTimeUnixNano: 1_500_000_000,
+		TimeUnixNano:      3_000_000_000,
+		Value:             42,
+	}
+	tests := []struct {
+		name    string
+		builder SampleBuilder
+		metric  Metric
+		want    []Sample
+	}{
+		{
+			name:    "gauge has no created timestamp",
+			builder: SampleBuilder{MetricNamer: NewMetricNamer("", UnderscoreEscapingWithSuffixes), CreatedSeries: true, StartTimeZeroSamples: true},
+			metric:  Metric{Name: "queue.size", Type: MetricTypeGauge},
+			want: []Sample{
+				{Name: "queue_size", Labels: method, Value: 42, Timestamp: 3000},
+			},
+		},
+		{
+			name:    "counter with created timestamp",
+			builder: SampleBuilder{MetricNamer: NewMetricNamer("", UnderscoreEscapingWithSuffixes)},
+			metric:  Metric{Name: "requests", Type: MetricTypeMonotonicCounter},
+			want: []Sample{
+				{Name: "requests_total", Labels: method, Value: 42, Timestamp: 3000, CreatedTimestamp: 1500},
+			},
+		},
+		{
+			name:    "counter with created series and zero sample",
+			builder: SampleBuilder{MetricNamer: NewMetricNamer("", UnderscoreEscapingWithSuffixes), CreatedSeries: true, StartTimeZeroSamples: true},
+			metric:  Metric{Name: "requests", Type: MetricTypeMonotonicCounter},
+			want: []Sample{
+				{Name: "requests_total", Labels: method, Value: 0, Timestamp: 1500, CreatedTimestamp: 1500},
+				{Name: "requests_total", Labels: method, Value: 42, Timestamp: 3000, CreatedTimestamp: 1500},
+				{Name: "requests_created", Labels: method, Value: 1.5, Timestamp: 3000},
+			},
+		},
+		{
+			name:    "created series without suffixes",
+			builder: SampleBuilder{MetricNamer: NewMetricNamer("", NoTranslation), CreatedSeries: true},
+			metric:  Metric{Name: "http.requests", Type: MetricTypeMonotonicCounter},
+			want: []Sample{
+				{Name: "http.requests", Labels: method, Value: 42, Timestamp: 3000, CreatedTimestamp: 1500},
+				{Name: "http.requests_created", Labels: method, Value: 1.5, Timestamp: 3000},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.builder.BuildNumber(tt.metric, nil, point)
+			if err != nil {
+				t.Fatalf("BuildNumber() returned an error: %s", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("BuildNumber() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSampleBuilder_BuildHistogram(t *testing.T) {
+	builder := SampleBuilder{MetricNamer: NewMetricNamer("", UnderscoreEscapingWithSuffixes), CreatedSeries: true}
+	metric := Metric{Name: "http.duration", Unit: "s", Type: MetricTypeHistogram}
+	job := Labels{{Name: "job", Value: "api"}}
+	got, err := builder.BuildHistogram(metric, job, HistogramDataPoint{
+		StartTimeUnixNano: 1_000_000_000,
+		TimeUnixNano:      2_000_000_000,
+		Count:             6,
+		Sum:               12.5,
+		BucketCounts:      []uint64{1, 2, 3},
+		ExplicitBounds:    []float64{0.5, 1},
+	})
+	if err != nil {
+		t.Fatalf("BuildHistogram() returned an error: %s", err)
+	}
+	want := []Sample{
+		{Name: "http_duration_seconds_bucket", Labels: Labels{{Name: "job", Value: "api"}, {Name: "le", Value: "0.5"}}, Value: 1, Timestamp: 2000, CreatedTimestamp: 1000},
+		{Name: "http_duration_seconds_bucket", Labels: Labels{{Name: "job", Value: "api"}, {Name: "le", Value: "1"}}, Value: 3, Timestamp: 2000, CreatedTimestamp: 1000},
+		{Name: "http_duration_seconds_bucket", Labels: Labels{{Name: "job", Value: "api"}, {Name: "le", Value: "+Inf"}}, Value: 6, Timestamp: 2000, CreatedTimestamp: 1000},
+		{Name: "http_duration_seconds_sum", Labels: job, Value: 12.5, Timestamp: 2000, CreatedTimestamp: 1000},
+		{Name: "http_duration_seconds_count", Labels: job, Value: 6, Timestamp: 2000, CreatedTimestamp: 1000},
+		{Name: "http_duration_seconds_created", Labels: job, Value: 1, Timestamp: 2000},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("BuildHistogram() = %+v, want %+v", got, want)
+	}
+}
+
+func TestSampleBuilder_BuildHistogram_Delta(t *testing.T) {
+	builder := SampleBuilder{MetricNamer: NewMetricNamer("", UnderscoreEscapingWithSuffixes), CreatedSeries: true, StartTimeZeroSamples: true}
+	metric := Metric{Name: "http.duration", Unit: "s", Type: MetricTypeHistogram, Temporality: TemporalityDelta}
+	got, err := builder.BuildHistogram(metric, nil, HistogramDataPoint{
+		StartTimeUnixNano: 1_000_000_000,
+		TimeUnixNano:      2_000_000_000,
+		Count:             3,
+		Sum:               2,
+		BucketCounts:      []uint64{1, 2},
+		ExplicitBounds:    []float64{0.5},
+	})
+	if err != nil {
+		t.Fatalf("BuildHistogram() returned an error: %s", err)
+	}
+	want := []Sample{
+		{Name: "http_duration_seconds_bucket", Labels: Labels{{Name: "le", Value: "0.5"}}, Value: 1, Timestamp: 2000},
+		{Name: "http_duration_seconds_bucket", Labels: Labels{{Name: "le", Value: "+Inf"}}, Value: 3, Timestamp: 2000},
+		{Name: "http_duration_seconds_sum", Value: 2, Timestamp: 2000},
+		{Name: "http_duration_seconds_count", Value: 3, Timestamp: 2000},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("BuildHistogram() = %+v, want %+v", got, want)
+	}
+}
+
+func TestSampleBuilder_BuildSummary(t *testing.T) {
+	builder := SampleBuilder{MetricNamer: NewMetricNamer("", UnderscoreEscapingWithSuffixes), StartTimeZeroSamples: true}
+	metric := Metric{Name: "rpc.duration", Unit: "ms", Type: MetricTypeSummary}
+	got, err := builder.BuildSummary(metric, nil, SummaryDataPoint{
+		StartTimeUnixNano: 1_000_000_000,
+		TimeUnixNano:      2_000_000_000,
+		Count:             10,
+		Sum:               100,
+		QuantileValues:    []ValueAtQuantile{{Quantile: 0.99, Value: 30}},
+	})
+	if err != nil {
+		t.Fatalf("BuildSummary() returned an error: %s", err)
+	}
+	want := []Sample{
+		{Name: "rpc_duration_milliseconds", Labels: Labels{{Name: "quantile", Value: "0.99"}}, Value: 30, Timestamp: 2000, CreatedTimestamp: 1000},
+		{Name: "rpc_duration_milliseconds_sum", Timestamp: 1000, CreatedTimestamp: 1000},
+		{Name: "rpc_duration_milliseconds_sum", Value: 100, Timestamp: 2000, CreatedTimestamp: 1000},
+		{Name: "rpc_duration_milliseconds_count", Timestamp: 1000, CreatedTimestamp: 1000},
+		{Name: "rpc_duration_milliseconds_count", Value: 10, Timestamp: 2000, CreatedTimestamp: 1000},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("BuildSummary() = %+v, want %+v", got, want)
+	}
+}
+
+func TestSampleBuilder_Exemplars(t *testing.T) {
+	builder := SampleBuilder{MetricNamer: NewMetricNamer("", UnderscoreEscapingWithSuffixes)}
+	exemplars := []Exemplar{
+		{
+			FilteredAttributes: []Attribute{{Key: "user.id", Value: "42"}},
+			TimeUnixNano:       2_000_000_000,
+			Value:              3,
+			TraceID:            [16]byte{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+			SpanID:             [8]byte{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+		},
+		{TimeUnixNano: 2_500_000_000, Value: 0.2},
+	}
+	traced := SampleExemplar{
+		Labels: Labels{
+			{Name: "span_id", Value: "00f067aa0ba902b7"},
+			{Name: "trace_id", Value: "4bf92f3577b34da6a3ce929d0e0e4736"},
+			{Name: "user_id", Value: "42"},
+		},
+		Value:     3,
+		Timestamp: 2000,
+	}
+	untraced := SampleExemplar{Value: 0.2, Timestamp: 2500}
+
+	samples, err := builder.BuildNumber(Metric{Name: "requests", Type: MetricTypeMonotonicCounter}, nil, NumberDataPoint{
+		TimeUnixNano: 3_000_000_000,
+		Value:        42,
+		Exemplars:    exemplars,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []SampleExemplar{untraced, traced}; !reflect.DeepEqual(samples[0].Exemplars, want) {
+		t.Errorf("BuildNumber() exemplars = %+v, want %+v", samples[0].Exemplars, want)
+	}
+
+	samples, err = builder.BuildHistogram(Metric{Name: "latency", Unit: "s", Type: MetricTypeHistogram}, nil, HistogramDataPoint{
+		TimeUnixNano:   3_000_000_000,
+		Count:          2,
+		BucketCounts:   []uint64{1, 0, 1},
+		ExplicitBounds: []float64{0.5, 1},
+		Exemplars:      exemplars,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]SampleExemplar{{untraced}, nil, {traced}, nil, nil}
+	for i, s := range samples {
+		if !reflect.DeepEqual(s.Exemplars, want[i]) {
+			t.Errorf("BuildHistogram() exemplars of %s%s = %+v, want %+v", s.Name, s.Labels, s.Exemplars, want[i])
+		}
+	}
+
+	samples, err = builder.BuildNumber(Metric{Name: "requests", Type: MetricTypeMonotonicCounter}, nil, NumberDataPoint{
+		TimeUnixNano: 3_000_000_000,
+		Exemplars:    exemplars,
+		Flags:        DataPointFlagNoRecordedValue,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if samples[0].Exemplars != nil {
+		t.Errorf("BuildNumber() kept exemplars on a staleness marker: %+v", samples[0].Exemplars)
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlptranslator
+
+import (
+	"math"
+	"testing"
+)
+
+func TestIsStaleNaN(t *testing.T) {
+	if !IsStaleNaN(math.Float64frombits(StaleNaN)) {
+		t.Error("IsStaleNaN(StaleNaN) = false, want true")
+	}
+	if IsStaleNaN(math.NaN()) {
+		t.Error("IsStaleNaN(math.NaN()) = true, want false")
+	}
+	if IsStaleNaN(math.Float64frombits(NormalNaN)) {
+		t.Error("IsStaleNaN(NormalNaN) = true, want false")
+	}
+}
+
+func TestSampleBuilder_NoRecordedValue(t *testing.T) {
+	builder := SampleBuilder{
+		MetricNamer:          NewMetricNamer("", UnderscoreEscapingWithSuffixes),
+		CreatedSeries:        true,
+		StartTimeZeroSamples: true,
+	}
+	const start, end = 1_000_000_000, 2_000_000_000
+
+	tests := []struct {
+		name      string
+		build     func() ([]Sample, error)
+		wantNames []string
+	}{
+		{
+			name: "gauge",
+			build: func() ([]Sample, error) {
+				return builder.BuildNumber(Metric{Name: "queue", Type: MetricTypeGauge}, nil, NumberDataPoint{
+					TimeUnixNano: end, Value: 1, Flags: DataPointFlagNoRecordedValue,
+				})
+			},
+			wantNames: []string{"queue"},
+		},
+		{
+			name: "counter",
+			build: func() ([]Sample, error) {
+				return builder.BuildNumber(Metric{Name: "requests", Type: MetricTypeMonotonicCounter}, nil, NumberDataPoint{
+					StartTimeUnixNano: start, TimeUnixNano: end, Value: 1, Flags: DataPointFlagNoRecordedValue,
+				})
+			},
+			wantNames: []string{"requests_total", "requests_created"},
+		},
+		{
+			name: "histogram",
+			build: func() ([]Sample, error) {
+				return builder.BuildHistogram(Metric{Name: "latency", Unit: "s", Type: MetricTypeHistogram}, nil, HistogramDataPoint{
+					StartTimeUnixNano: start, TimeUnixNano: end, Count: 1, Sum: 1,
+					BucketCounts: []uint64{1, 0}, ExplicitBounds: []float64{1},
+					Flags: DataPointFlagNoRecordedValue,
+				})
+			},
+			wantNames: []string{
+				"latency_seconds_bucket", "latency_seconds_bucket",
+				"latency_seconds_sum", "latency_seconds_count", "latency_seconds_created",
+			},
+		},
+		{
+			name: "summary",
+			build: func() ([]Sample, error) {
+				return builder.BuildSummary(Metric{Name: "latency", Unit: "s", Type: MetricTypeSummary}, nil, SummaryDataPoint{
+					StartTimeUnixNano: start, TimeUnixNano: end, Count: 1, Sum: 1,
+					QuantileValues: []ValueAtQuantile{{Quantile: 0.5, Value: 1}, {Quantile: 0.9, Value: 1}},
+					Flags:          DataPointFlagNoRecordedValue,
+				})
+			},
+			wantNames: []string{
+				"latency_seconds", "latency_seconds",
+				"latency_seconds_sum", "latency_seconds_count", "latency_seconds_created",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			samples, err := tt.build()
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if len(samples) != len(tt.wantNames) {
+				t.Fatalf("got %d samples, want %d: %+v", len(samples), len(tt.wantNames), samples)
+			}
+			for i, s := range samples {
+				if s.Name != tt.wantNames[i] {
+					t.Errorf("sample %d name = %q, want %q", i, s.Name, tt.wantNames[i])
+				}
+				if !IsStaleNaN(s.Value) {
+					t.Errorf("sample %d (%s%s) = %g, want a stale marker", i, s.Name, s.Labels, s.Value)
+				}
+				if s.Timestamp != 2000 {
+					t.Errorf("sample %d timestamp = %d, want 2000", i, s.Timestamp)
+				}
+			}
+		})
+	}
+}
+
+func TestDeltaAccumulator_NoRecordedValue(t *testing.T) {
+	acc := newTestAccumulator(DeltaAccumulatorOptions{})
+	if _, err := acc.AddSum(deltaCounter, nil, NumberDataPoint{StartTimeUnixNano: 1, TimeUnixNano: 2, Value: 5}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := acc.AddSum(deltaCounter, nil, NumberDataPoint{StartTimeUnixNano: 2, TimeUnixNano: 3, Flags: DataPointFlagNoRecordedValue})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Flags.NoRecordedValue() {
+		t.Error("AddSum() dropped the NoRecordedValue flag")
+	}
+	if acc.Len() != 0 {
+		t.Errorf("Len() = %d, want 0", acc.Len())
+	}
+
+	got, err = acc.AddSum(deltaCounter, nil, NumberDataPoint{StartTimeUnixNano: 4, TimeUnixNano: 5, Value: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.StartTimeUnixNano != 4 || got.Value != 1 {
+		t.Errorf("AddSum() = {start: %d, value: %g}, want {start: 4, value: 1}", got.StartTimeUnixNano, got.Value)
+	}
+}
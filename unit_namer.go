@@ -25,6 +25,10 @@ import "strings"
 //	result = namer.Build("By/s")   // "bytes_per_second"
 type UnitNamer struct {
 	UTF8Allowed bool
+	// Behavior pins the rules units are translated with. The zero value
+	// keeps the BehaviorV1 rules. Units are not translated with unknown
+	// versions, see Build.
+	Behavior BehaviorVersion
 }
 
 // Build builds a unit name for the specified unit string.
@@ -43,15 +47,28 @@ type UnitNamer struct {
 //	namer.Build("s")           // "seconds"
 //	namer.Build("requests/s")  // "requests_per_second"
 //	namer.Build("1")           // "" (dimensionless)
+//
+// Build returns an empty unit if Behavior is an unknown behavior version,
+// rather than translating it with other rules than the metric name. Use
+// MetadataBuilder.Build to get the error.
 func (un *UnitNamer) Build(unit string) string {
+	if un.Behavior.validate() != nil {
+		return ""
+	}
 	mainUnit, perUnit := buildUnitSuffixes(unit)
 	if !un.UTF8Allowed {
 		mainUnit, perUnit = cleanUpUnit(mainUnit), cleanUpUnit(perUnit)
 	}
 
+	// Since BehaviorV2, the underscores of units are all trimmed rather than
+	// one of them, so that units do not end up with double underscores.
+	v2 := !un.Behavior.before(BehaviorV2)
 	var u string
 	switch {
 	case mainUnit != "" && perUnit != "":
+		if v2 {
+			mainUnit = strings.TrimRight(mainUnit, "_")
+		}
 		u = mainUnit + "_" + perUnit
 	case mainUnit != "":
 		u = mainUnit
@@ -60,6 +77,9 @@ func (un *UnitNamer) Build(unit string) string {
 	}
 
 	// Clean up leading and trailing underscores
+	if v2 {
+		return strings.Trim(u, "_")
+	}
 	if len(u) > 0 && u[0:1] == "_" {
 		u = u[1:]
 	}
@@ -127,3 +147,88 @@ func cleanUpUnit(unit string) string {
 		strings.Map(replaceInvalidMetricChar, unit),
 	), "_")
 }
+
+// reverseUnitMap and reversePerUnitMap map Prometheus units back to the OTLP
+// units they are built from.
+var (
+	reverseUnitMap    = invertUnitMap(unitMap)
+	reversePerUnitMap = invertUnitMap(perUnitMap)
+)
+
+func invertUnitMap(m map[string]string) map[string]string {
+	res := make(map[string]string, len(m))
+	for otel, prom := range m {
+		if prom != "" {
+			res[prom] = otel
+		}
+	}
+	return res
+}
+
+// Parse reverses Build, returning the OTLP unit corresponding to a Prometheus
+// unit. Known units are mapped back to their UCUM notation, and the `ratio`
+// unit of gauges to "1". Unknown units are returned unchanged.
+//
+// Examples:
+//
+//	namer := UnitNamer{}
+//	namer.Parse("seconds")           // "s"
+//	namer.Parse("bytes_per_second")  // "By/s"
+//	namer.Parse("per_second")        // "1/s"
+//	namer.Parse("requests")          // "requests"
+func (un *UnitNamer) Parse(unit string) string {
+	if unit == "ratio" {
+		return "1"
+	}
+	mainUnit, perUnit, found := strings.Cut(unit, "_per_")
+	if !found {
+		perUnit, found = strings.CutPrefix(unit, "per_")
+		if found {
+			mainUnit = ""
+		}
+	}
+	if otel, ok := reverseUnitMap[mainUnit]; ok {
+		mainUnit = otel
+	}
+	if !found {
+		return mainUnit
+	}
+	if otel, ok := reversePerUnitMap[perUnit]; ok {
+		perUnit = otel
+	}
+	if mainUnit == "" {
+		mainUnit = "1"
+	}
+	return mainUnit + "/" + perUnit
+}
+
+// CutUnitSuffix looks for a known unit suffix, as added by MetricNamer.Build,
+// at the end of a Prometheus metric name. The `_total` suffix of counters must
+// be removed first. It returns the name before the suffix and the Prometheus
+// unit, which UnitNamer.Parse turns back into an OTLP unit, and reports
+// whether a suffix was found.
+//
+// Examples:
+//
+//	CutUnitSuffix("http_server_duration_seconds")  // "http_server_duration", "seconds", true
+//	CutUnitSuffix("network_bytes_per_second")      // "network", "bytes_per_second", true
+//	CutUnitSuffix("cpu_utilization_ratio")         // "cpu_utilization", "ratio", true
+//	CutUnitSuffix("http_requests")                 // "http_requests", "", false
+func CutUnitSuffix(name string) (before, unit string, found bool) {
+	tokens := strings.Split(name, "_")
+	n := len(tokens)
+	switch {
+	case n > 2 && tokens[n-2] == "per" && reversePerUnitMap[tokens[n-1]] != "":
+		unitTokens := 2
+		if _, ok := reverseUnitMap[tokens[n-3]]; ok && n > 3 {
+			unitTokens = 3
+		}
+		before, unit = strings.Join(tokens[:n-unitTokens], "_"), strings.Join(tokens[n-unitTokens:], "_")
+	case n > 1 && (tokens[n-1] == "ratio" || reverseUnitMap[tokens[n-1]] != ""):
+		before, unit = strings.Join(tokens[:n-1], "_"), tokens[n-1]
+	}
+	if before == "" {
+		return name, "", false
+	}
+	return before, unit, true
+}
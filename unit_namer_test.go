@@ -0,0 +1,63 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlptranslator
+
+import "testing"
+
+func TestUnitNamer_Parse(t *testing.T) {
+	namer := UnitNamer{}
+	for _, tc := range []struct {
+		unit string
+		want string
+	}{
+		{unit: "seconds", want: "s"},
+		{unit: "bytes_per_second", want: "By/s"},
+		{unit: "per_second", want: "1/s"},
+		{unit: "requests_per_minute", want: "requests/m"},
+		{unit: "ratio", want: "1"},
+		{unit: "celsius", want: "Cel"},
+		{unit: "requests", want: "requests"},
+		{unit: "", want: ""},
+	} {
+		if got := namer.Parse(tc.unit); got != tc.want {
+			t.Errorf("Parse(%q) = %q, want %q", tc.unit, got, tc.want)
+		}
+		// Known units make the round trip.
+		if got := namer.Build(namer.Parse(tc.unit)); tc.unit != "ratio" && got != tc.unit {
+			t.Errorf("Build(Parse(%q)) = %q", tc.unit, got)
+		}
+	}
+}
+
+func TestCutUnitSuffix(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		wantBefore string
+		wantUnit   string
+		wantFound  bool
+	}{
+		{name: "http_server_duration_seconds", wantBefore: "http_server_duration", wantUnit: "seconds", wantFound: true},
+		{name: "network_bytes_per_second", wantBefore: "network", wantUnit: "bytes_per_second", wantFound: true},
+		{name: "requests_per_second", wantBefore: "requests", wantUnit: "per_second", wantFound: true},
+		{name: "cpu_utilization_ratio", wantBefore: "cpu_utilization", wantUnit: "ratio", wantFound: true},
+		{name: "http_requests", wantBefore: "http_requests"},
+		{name: "seconds", wantBefore: "seconds"},
+		{name: "per_second", wantBefore: "per_second"},
+	} {
+		before, unit, found := CutUnitSuffix(tc.name)
+		if before != tc.wantBefore || unit != tc.wantUnit || found != tc.wantFound {
+			t.Errorf("CutUnitSuffix(%q) = %q, %q, %v, want %q, %q, %v", tc.name, before, unit, found, tc.wantBefore, tc.wantUnit, tc.wantFound)
+		}
+	}
+}